@@ -0,0 +1,94 @@
+package graphql
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"github.com/mrinalwahal/boilerplate/records/service"
+)
+
+// Handler serves the GraphQL queries and mutations of the records.
+type Handler struct {
+
+	// schema is the executable GraphQL schema.
+	schema graphql.Schema
+
+	// log is the `log/slog` instance that will be used to log messages.
+	// Default: `slog.DefaultLogger`
+	//
+	// This field is optional.
+	log *slog.Logger
+}
+
+type HandlerConfig struct {
+
+	// Service layer.
+	//
+	// This field is mandatory.
+	Service service.Service
+
+	// Logger is the `log/slog` instance that will be used to log messages.
+	// Default: `slog.DefaultLogger`
+	//
+	// This field is optional.
+	Logger *slog.Logger
+}
+
+// NewHandler creates a new instance of `Handler`.
+func NewHandler(config *HandlerConfig) *Handler {
+	if config == nil || config.Service == nil {
+		panic("graphql: service is required")
+	}
+
+	schema, err := NewSchema(config.Service)
+	if err != nil {
+		panic(err)
+	}
+
+	handler := Handler{
+		schema: schema,
+		log:    config.Logger,
+	}
+
+	// Set the default logger if not provided.
+	if handler.log == nil {
+		handler.log = slog.Default()
+	}
+
+	return &handler
+}
+
+// request is the body of a GraphQL request.
+//
+// Link: https://graphql.org/learn/serving-over-http/#post-request
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// ServeHTTP executes the GraphQL request in the body.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.log.DebugContext(r.Context(), "handling request")
+
+	var body request
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "invalid graphql request", http.StatusBadRequest)
+		return
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:         h.schema,
+		RequestString:  body.Query,
+		OperationName:  body.OperationName,
+		VariableValues: body.Variables,
+		Context:        r.Context(),
+	})
+
+	// The errors of the individual fields are returned in the `errors` field with a `200 OK`,
+	// as the GraphQL over HTTP spec requires.
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,132 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mrinalwahal/boilerplate/model"
+	"github.com/mrinalwahal/boilerplate/pkg/middleware"
+	"github.com/mrinalwahal/boilerplate/records/service"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandler_ServeHTTP(t *testing.T) {
+
+	// Get the mock service layer.
+	svc := service.NewMockService(gomock.NewController(t))
+
+	h := NewHandler(&HandlerConfig{
+		Service: svc,
+	})
+
+	// Test record.
+	record := &model.Record{
+		Title:  "Test Record",
+		UserID: uuid.New(),
+	}
+	record.ID = uuid.New()
+
+	// Executes the supplied query and returns the decoded response.
+	execute := func(t *testing.T, r *http.Request) map[string]interface{} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Handler.ServeHTTP() = %v, want %v", w.Code, http.StatusOK)
+		}
+
+		var resp map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode the response: %v", err)
+		}
+		return resp
+	}
+
+	// Returns the GraphQL request with the supplied query and variables.
+	request := func(query string, variables map[string]interface{}) *http.Request {
+		body, _ := json.Marshal(map[string]interface{}{
+			"query":     query,
+			"variables": variables,
+		})
+		return httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	}
+
+	t.Run("query a record", func(t *testing.T) {
+
+		svc.EXPECT().Get(gomock.Any(), record.ID).Return(record, nil).Times(1)
+
+		resp := execute(t, request(`query ($id: ID!) { record(id: $id) { id title userId } }`, map[string]interface{}{
+			"id": record.ID.String(),
+		}))
+
+		data := resp["data"].(map[string]interface{})["record"].(map[string]interface{})
+		if data["title"] != record.Title {
+			t.Errorf("title = %v, want %v", data["title"], record.Title)
+		}
+		if data["userId"] != record.UserID.String() {
+			t.Errorf("userId = %v, want %v", data["userId"], record.UserID)
+		}
+	})
+
+	t.Run("query records w/ filter", func(t *testing.T) {
+
+		svc.EXPECT().List(gomock.Any(), &service.ListOptions{
+			Search: "test",
+			Limit:  1,
+		}).Return([]*model.Record{record}, nil).Times(1)
+
+		resp := execute(t, request(`{ records(filter: {search: "test", limit: 1}) { id } }`, nil))
+
+		records := resp["data"].(map[string]interface{})["records"].([]interface{})
+		if len(records) != 1 {
+			t.Errorf("expected 1 record, got %d", len(records))
+		}
+	})
+
+	t.Run("create record w/ claims", func(t *testing.T) {
+
+		svc.EXPECT().Create(gomock.Any(), &service.CreateOptions{
+			Title:  record.Title,
+			UserID: record.UserID,
+		}).Return(record, nil).Times(1)
+
+		r := request(`mutation { createRecord(input: {title: "Test Record"}) { id } }`, nil)
+		r = r.WithContext(middleware.WithJWTClaims(r.Context(), middleware.JWTClaims{
+			XUserID: record.UserID,
+		}))
+
+		resp := execute(t, r)
+
+		if _, exists := resp["errors"]; exists {
+			t.Errorf("expected no errors, got %v", resp["errors"])
+		}
+	})
+
+	t.Run("create record w/o claims", func(t *testing.T) {
+
+		svc.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+
+		resp := execute(t, request(`mutation { createRecord(input: {title: "Test Record"}) { id } }`, nil))
+
+		if _, exists := resp["errors"]; !exists {
+			t.Errorf("expected an error, got %v", resp)
+		}
+	})
+
+	t.Run("delete record", func(t *testing.T) {
+
+		svc.EXPECT().Delete(gomock.Any(), record.ID).Return(nil).Times(1)
+
+		resp := execute(t, request(`mutation ($id: ID!) { deleteRecord(id: $id) }`, map[string]interface{}{
+			"id": record.ID.String(),
+		}))
+
+		if resp["data"].(map[string]interface{})["deleteRecord"] != true {
+			t.Errorf("expected the record to be deleted, got %v", resp)
+		}
+	})
+}
@@ -0,0 +1,211 @@
+package graphql
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+	"github.com/mrinalwahal/boilerplate/model"
+	"github.com/mrinalwahal/boilerplate/pkg/errs"
+	"github.com/mrinalwahal/boilerplate/pkg/middleware"
+	"github.com/mrinalwahal/boilerplate/records/service"
+)
+
+var ErrInvalidJWTClaims = fmt.Errorf("invalid jwt claims")
+var ErrInvalidRecordID = errs.Wrap(errs.InvalidArgument, "invalid record id")
+
+// recordType is the GraphQL type of a record.
+var recordType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Record",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.ID),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*model.Record).ID.String(), nil
+			},
+		},
+		"title": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.String),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*model.Record).Title, nil
+			},
+		},
+		"description": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.String),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*model.Record).Description, nil
+			},
+		},
+		"userId": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.ID),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*model.Record).UserID.String(), nil
+			},
+		},
+		"createdAt": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.DateTime),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*model.Record).CreatedAt, nil
+			},
+		},
+		"updatedAt": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.DateTime),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*model.Record).UpdatedAt, nil
+			},
+		},
+	},
+})
+
+// recordsFilterType is the GraphQL input type of the options for listing records.
+var recordsFilterType = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "RecordsFilter",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"title":          &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"search":         &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"skip":           &graphql.InputObjectFieldConfig{Type: graphql.Int},
+		"limit":          &graphql.InputObjectFieldConfig{Type: graphql.Int},
+		"orderBy":        &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"orderDirection": &graphql.InputObjectFieldConfig{Type: graphql.String},
+	},
+})
+
+// createRecordInputType is the GraphQL input type of the options for creating a record.
+var createRecordInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "CreateRecordInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"title":       &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"description": &graphql.InputObjectFieldConfig{Type: graphql.String},
+	},
+})
+
+// updateRecordInputType is the GraphQL input type of the options for updating a record.
+var updateRecordInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "UpdateRecordInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"title":       &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"description": &graphql.InputObjectFieldConfig{Type: graphql.String},
+	},
+})
+
+// NewSchema creates the GraphQL schema of the records which resolves the fields through the supplied service layer.
+//
+// The resolvers receive the request context, so the Row Level Security (RLS) checks apply to the JWT claims stored in it.
+func NewSchema(svc service.Service) (graphql.Schema, error) {
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"record": &graphql.Field{
+					Type: recordType,
+					Args: graphql.FieldConfigArgument{
+						"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						id, err := idOf(p)
+						if err != nil {
+							return nil, err
+						}
+						return svc.Get(p.Context, id)
+					},
+				},
+				"records": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(recordType))),
+					Args: graphql.FieldConfigArgument{
+						"filter": &graphql.ArgumentConfig{Type: recordsFilterType},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						filter, _ := p.Args["filter"].(map[string]interface{})
+						return svc.List(p.Context, &service.ListOptions{
+							Title:          stringOf(filter, "title"),
+							Search:         stringOf(filter, "search"),
+							Skip:           intOf(filter, "skip"),
+							Limit:          intOf(filter, "limit"),
+							OrderBy:        service.OrderBy(stringOf(filter, "orderBy")),
+							OrderDirection: service.OrderDirection(stringOf(filter, "orderDirection")),
+						})
+					},
+				},
+			},
+		}),
+		Mutation: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Mutation",
+			Fields: graphql.Fields{
+				"createRecord": &graphql.Field{
+					Type: recordType,
+					Args: graphql.FieldConfigArgument{
+						"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(createRecordInputType)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						claims, exists := middleware.JWTClaimsFromContext(p.Context)
+						if !exists {
+							return nil, ErrInvalidJWTClaims
+						}
+						input, _ := p.Args["input"].(map[string]interface{})
+						return svc.Create(p.Context, &service.CreateOptions{
+							Title:       stringOf(input, "title"),
+							Description: stringOf(input, "description"),
+							UserID:      claims.XUserID,
+						})
+					},
+				},
+				"updateRecord": &graphql.Field{
+					Type: recordType,
+					Args: graphql.FieldConfigArgument{
+						"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+						"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(updateRecordInputType)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						id, err := idOf(p)
+						if err != nil {
+							return nil, err
+						}
+						input, _ := p.Args["input"].(map[string]interface{})
+						return svc.Update(p.Context, id, &service.UpdateOptions{
+							Title:       stringOf(input, "title"),
+							Description: stringOf(input, "description"),
+						})
+					},
+				},
+				"deleteRecord": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+					Args: graphql.FieldConfigArgument{
+						"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						id, err := idOf(p)
+						if err != nil {
+							return nil, err
+						}
+						if err := svc.Delete(p.Context, id); err != nil {
+							return false, err
+						}
+						return true, nil
+					},
+				},
+			},
+		}),
+	})
+}
+
+// idOf parses the `id` argument of the field.
+func idOf(p graphql.ResolveParams) (uuid.UUID, error) {
+	value, _ := p.Args["id"].(string)
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, ErrInvalidRecordID
+	}
+	return id, nil
+}
+
+// stringOf returns the string value of the supplied key in the input object, or an empty string if it's absent.
+func stringOf(input map[string]interface{}, key string) string {
+	value, _ := input[key].(string)
+	return value
+}
+
+// intOf returns the integer value of the supplied key in the input object, or zero if it's absent.
+func intOf(input map[string]interface{}, key string) int {
+	value, _ := input[key].(int)
+	return value
+}
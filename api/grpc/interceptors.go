@@ -0,0 +1,136 @@
+package grpc
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/mrinalwahal/boilerplate/pkg/middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type AuthConfig struct {
+
+	// JWT is the configuration of the `JWT` HTTP middleware, whose keys will be used to validate the JWT,
+	// so both the transports validate the JWTs identically. Its `Prefix` is the type of the JWT.
+	// Either its `Key`, `Keys` or `JWKSURL` must be set.
+	//
+	// This field is mandatory.
+	JWT *middleware.JWTConfig
+
+	// Metadata is the request metadata that will be used to extract the JWT from.
+	// Default: `authorization`
+	//
+	// This field is optional.
+	Metadata string
+}
+
+// Auth interceptor validates the JWT in the request metadata and writes its claims to the context.
+//
+// It mirrors the `JWT` HTTP middleware, so the Row Level Security (RLS) checks apply the same way.
+func Auth(config *AuthConfig) grpc.UnaryServerInterceptor {
+
+	// Validate the configuration.
+	if config == nil || config.JWT == nil {
+		panic("grpc: auth: jwt is required")
+	}
+
+	if config.Metadata == "" {
+		config.Metadata = "authorization"
+	}
+
+	parser := middleware.NewJWTParser(config.JWT)
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+
+		// Extract the JWT from the appropriate metadata.
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get(config.Metadata)
+		if len(values) == 0 || values[0] == "" {
+			return nil, status.Error(codes.Unauthenticated, "failed to extract the JWT from appropriate metadata")
+		}
+
+		// Remove the prefix from the JWT.
+		token := strings.TrimSpace(strings.TrimPrefix(values[0], config.JWT.Prefix))
+
+		claims, err := parser.Parse(ctx, token)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+
+		return handler(middleware.WithJWTClaims(ctx, claims), req)
+	}
+}
+
+type LoggingConfig struct {
+
+	// Logger is the `log/slog` instance that will be used to log messages.
+	// Default: `slog.DefaultLogger`
+	//
+	// This field is optional.
+	Logger *slog.Logger
+}
+
+// Logging interceptor logs the incoming calls, mirroring the `Logging` HTTP middleware.
+func Logging(config *LoggingConfig) grpc.UnaryServerInterceptor {
+
+	// Set the default configuration.
+	if config == nil {
+		config = &LoggingConfig{}
+	}
+
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		config.Logger.LogAttrs(ctx, slog.LevelInfo, "incoming call to "+info.FullMethod,
+			slog.String("timestamp", start.String()),
+			slog.String("method", info.FullMethod),
+			slog.String("code", status.Code(err).String()),
+			slog.Duration("latency", time.Since(start)),
+		)
+		return resp, err
+	}
+}
+
+type RecoverConfig struct {
+
+	// Logger is the `log/slog` instance that will be used to log messages.
+	// Default: nil
+	//
+	// This field is optional.
+	Logger *slog.Logger
+}
+
+// Recover interceptor recovers from the panics and fails the call with the `Internal` code,
+// mirroring the `Recover` HTTP middleware.
+func Recover(config *RecoverConfig) grpc.UnaryServerInterceptor {
+
+	// Set the default configuration.
+	if config == nil {
+		config = &RecoverConfig{}
+	}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				if config.Logger != nil {
+					config.Logger.LogAttrs(ctx, slog.LevelError, "panic recovered", slog.Attr{
+						Key:   "panic error",
+						Value: slog.AnyValue(r),
+					})
+				}
+				err = status.Error(codes.Internal, "internal error")
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,18 @@
+package recordspb
+
+import (
+	"github.com/mrinalwahal/boilerplate/model"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// FromModel converts the record to its protobuf message.
+func FromModel(record *model.Record) *Record {
+	return &Record{
+		Id:          record.ID.String(),
+		Title:       record.Title,
+		Description: record.Description,
+		UserId:      record.UserID.String(),
+		CreatedAt:   timestamppb.New(record.CreatedAt),
+		UpdatedAt:   timestamppb.New(record.UpdatedAt),
+	}
+}
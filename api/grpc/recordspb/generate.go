@@ -0,0 +1,4 @@
+// Package recordspb contains the protobuf messages and the gRPC service definitions of the records.
+package recordspb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative records.proto
@@ -0,0 +1,174 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/mrinalwahal/boilerplate/api/grpc/recordspb"
+	"github.com/mrinalwahal/boilerplate/pkg/errs"
+	"github.com/mrinalwahal/boilerplate/pkg/middleware"
+	"github.com/mrinalwahal/boilerplate/records/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// Server implements the `RecordService` gRPC service on top of the service layer.
+type Server struct {
+	recordspb.UnimplementedRecordServiceServer
+
+	// Service layer.
+	//
+	// This field is mandatory.
+	service service.Service
+
+	// log is the `log/slog` instance that will be used to log messages.
+	// Default: `slog.DefaultLogger`
+	//
+	// This field is optional.
+	log *slog.Logger
+}
+
+type ServerConfig struct {
+
+	// Service layer.
+	//
+	// This field is mandatory.
+	Service service.Service
+
+	// Logger is the `log/slog` instance that will be used to log messages.
+	// Default: `slog.DefaultLogger`
+	//
+	// This field is optional.
+	Logger *slog.Logger
+}
+
+// NewServer creates a new instance of `Server`.
+func NewServer(config *ServerConfig) *Server {
+	if config == nil || config.Service == nil {
+		panic("grpc: service is required")
+	}
+
+	server := Server{
+		service: config.Service,
+		log:     config.Logger,
+	}
+
+	// Set the default logger if not provided.
+	if server.log == nil {
+		server.log = slog.Default()
+	}
+
+	return &server
+}
+
+func (s *Server) Create(ctx context.Context, req *recordspb.CreateRequest) (*recordspb.Record, error) {
+	claims, exists := middleware.JWTClaimsFromContext(ctx)
+	if !exists {
+		return nil, status.Error(codes.Unauthenticated, "invalid jwt claims")
+	}
+
+	record, err := s.service.Create(ctx, &service.CreateOptions{
+		Title:       req.GetTitle(),
+		Description: req.GetDescription(),
+		UserID:      claims.XUserID,
+	})
+	if err != nil {
+		return nil, s.statusOf(ctx, err)
+	}
+	return recordspb.FromModel(record), nil
+}
+
+func (s *Server) Get(ctx context.Context, req *recordspb.GetRequest) (*recordspb.Record, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid record id")
+	}
+
+	record, err := s.service.Get(ctx, id)
+	if err != nil {
+		return nil, s.statusOf(ctx, err)
+	}
+	return recordspb.FromModel(record), nil
+}
+
+func (s *Server) List(ctx context.Context, req *recordspb.ListRequest) (*recordspb.ListResponse, error) {
+	records, err := s.service.List(ctx, &service.ListOptions{
+		Title:          req.GetTitle(),
+		Search:         req.GetSearch(),
+		Skip:           int(req.GetSkip()),
+		Limit:          int(req.GetLimit()),
+		OrderBy:        service.OrderBy(req.GetOrderBy()),
+		OrderDirection: service.OrderDirection(req.GetOrderDirection()),
+	})
+	if err != nil {
+		return nil, s.statusOf(ctx, err)
+	}
+
+	var resp recordspb.ListResponse
+	for _, record := range records {
+		resp.Records = append(resp.Records, recordspb.FromModel(record))
+	}
+	return &resp, nil
+}
+
+func (s *Server) Update(ctx context.Context, req *recordspb.UpdateRequest) (*recordspb.Record, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid record id")
+	}
+
+	record, err := s.service.Update(ctx, id, &service.UpdateOptions{
+		Title:       req.GetTitle(),
+		Description: req.GetDescription(),
+	})
+	if err != nil {
+		return nil, s.statusOf(ctx, err)
+	}
+	return recordspb.FromModel(record), nil
+}
+
+func (s *Server) Delete(ctx context.Context, req *recordspb.DeleteRequest) (*recordspb.DeleteResponse, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid record id")
+	}
+
+	if err := s.service.Delete(ctx, id); err != nil {
+		return nil, s.statusOf(ctx, err)
+	}
+	return &recordspb.DeleteResponse{}, nil
+}
+
+// statusOf converts the error returned by the service layer to a gRPC status error.
+//
+// It mirrors the HTTP status codes returned by the v1 handlers.
+// The errors which aren't classified, like the failures of the database, are logged and reported as `Internal`
+// with a generic message, so their details don't leak to the caller.
+func (s *Server) statusOf(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, gorm.ErrRecordNotFound), errs.Is(err, errs.NotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errs.Is(err, errs.Unavailable):
+		return status.Error(codes.Unavailable, err.Error())
+	case errs.Is(err, errs.PermissionDenied):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errs.Is(err, errs.AlreadyExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errs.Is(err, errs.Unprocessable):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errs.Is(err, errs.InvalidArgument):
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	s.log.LogAttrs(ctx, slog.LevelError, "internal error",
+		slog.String("error", err.Error()),
+	)
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,273 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/mrinalwahal/boilerplate/api/grpc/recordspb"
+	"github.com/mrinalwahal/boilerplate/model"
+	"github.com/mrinalwahal/boilerplate/pkg/middleware"
+	"github.com/mrinalwahal/boilerplate/records/service"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"gorm.io/gorm"
+)
+
+// Contains all the configuration required by our tests.
+type testconfig struct {
+
+	// Mock service layer.
+	service *service.MockService
+
+	// Client connected to the in-process server.
+	client recordspb.RecordServiceClient
+}
+
+// Setup the test environment.
+func configure(t *testing.T) *testconfig {
+
+	// Get the mock service layer.
+	svc := service.NewMockService(gomock.NewController(t))
+
+	// Start the in-process server.
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		Recover(nil),
+		Logging(nil),
+		Auth(&AuthConfig{
+			JWT: &middleware.JWTConfig{
+				Key: "secret",
+			},
+		}),
+	))
+	recordspb.RegisterRecordServiceServer(server, NewServer(&ServerConfig{
+		Service: svc,
+	}))
+	go server.Serve(listener)
+
+	// Connect the client to the server.
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to connect to the server: %v", err)
+	}
+
+	// Cleanup the environment after the test is complete.
+	t.Cleanup(func() {
+		conn.Close()
+		server.Stop()
+	})
+
+	return &testconfig{
+		service: svc,
+		client:  recordspb.NewRecordServiceClient(conn),
+	}
+}
+
+// authorize returns the context carrying a JWT of the supplied user in the outgoing metadata.
+func authorize(t *testing.T, userID uuid.UUID) context.Context {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, middleware.JWTClaims{
+		XUserID: userID,
+	})
+	signed, err := token.SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+signed)
+}
+
+func TestServer(t *testing.T) {
+
+	// Setup the test environment.
+	config := configure(t)
+
+	userID := uuid.New()
+
+	// Test record.
+	record := &model.Record{
+		Title:  "Test Record",
+		UserID: userID,
+	}
+	record.ID = uuid.New()
+
+	t.Run("create record w/ claims from metadata", func(t *testing.T) {
+
+		config.service.EXPECT().Create(gomock.Any(), &service.CreateOptions{
+			Title:  record.Title,
+			UserID: userID,
+		}).DoAndReturn(func(ctx context.Context, _ *service.CreateOptions) (*model.Record, error) {
+
+			// The claims must reach the service layer for the RLS checks.
+			if claims, exists := middleware.JWTClaimsFromContext(ctx); !exists || claims.XUserID != userID {
+				t.Errorf("expected the claims of %s in the context, got %v", userID, claims)
+			}
+			return record, nil
+		}).Times(1)
+
+		resp, err := config.client.Create(authorize(t, userID), &recordspb.CreateRequest{
+			Title: record.Title,
+		})
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if resp.GetId() != record.ID.String() {
+			t.Errorf("Create() id = %v, want %v", resp.GetId(), record.ID)
+		}
+	})
+
+	t.Run("list records", func(t *testing.T) {
+
+		config.service.EXPECT().List(gomock.Any(), &service.ListOptions{
+			Limit: 1,
+		}).Return([]*model.Record{record}, nil).Times(1)
+
+		resp, err := config.client.List(authorize(t, userID), &recordspb.ListRequest{
+			Limit: 1,
+		})
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		if len(resp.GetRecords()) != 1 {
+			t.Errorf("expected 1 record, got %d", len(resp.GetRecords()))
+		}
+	})
+
+	t.Run("get missing record", func(t *testing.T) {
+
+		config.service.EXPECT().Get(gomock.Any(), record.ID).Return(nil, gorm.ErrRecordNotFound).Times(1)
+
+		_, err := config.client.Get(authorize(t, userID), &recordspb.GetRequest{
+			Id: record.ID.String(),
+		})
+		if status.Code(err) != codes.NotFound {
+			t.Errorf("Get() code = %v, want %v", status.Code(err), codes.NotFound)
+		}
+	})
+
+	t.Run("create record w/ invalid title", func(t *testing.T) {
+
+		config.service.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, service.ErrInvalidOptions).Times(1)
+
+		_, err := config.client.Create(authorize(t, userID), &recordspb.CreateRequest{})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Create() code = %v, want %v", status.Code(err), codes.InvalidArgument)
+		}
+	})
+
+	t.Run("hide the details of the database failures", func(t *testing.T) {
+
+		config.service.EXPECT().Get(gomock.Any(), record.ID).Return(nil, fmt.Errorf("dial tcp 10.0.0.7:5432: connection refused")).Times(1)
+
+		_, err := config.client.Get(authorize(t, userID), &recordspb.GetRequest{
+			Id: record.ID.String(),
+		})
+		if status.Code(err) != codes.Internal {
+			t.Errorf("Get() code = %v, want %v", status.Code(err), codes.Internal)
+		}
+		if message := status.Convert(err).Message(); strings.Contains(message, "10.0.0.7") {
+			t.Errorf("Get() message = %q, want a generic message", message)
+		}
+	})
+
+	t.Run("reject call w/o jwt", func(t *testing.T) {
+
+		config.service.EXPECT().Get(gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := config.client.Get(context.Background(), &recordspb.GetRequest{
+			Id: record.ID.String(),
+		})
+		if status.Code(err) != codes.Unauthenticated {
+			t.Errorf("Get() code = %v, want %v", status.Code(err), codes.Unauthenticated)
+		}
+	})
+
+	t.Run("recover from panic", func(t *testing.T) {
+
+		config.service.EXPECT().Delete(gomock.Any(), record.ID).DoAndReturn(func(context.Context, uuid.UUID) error {
+			panic("boom")
+		}).Times(1)
+
+		_, err := config.client.Delete(authorize(t, userID), &recordspb.DeleteRequest{
+			Id: record.ID.String(),
+		})
+		if status.Code(err) != codes.Internal {
+			t.Errorf("Delete() code = %v, want %v", status.Code(err), codes.Internal)
+		}
+	})
+}
+
+func TestAuth(t *testing.T) {
+
+	// The interceptor is configured with rotated keys only, like the `JWT` HTTP middleware can be.
+	interceptor := Auth(&AuthConfig{
+		JWT: &middleware.JWTConfig{
+			Keys: map[string]string{
+				"current": "secret",
+			},
+		},
+	})
+
+	// Signs a JWT with the supplied key ID and secret.
+	sign := func(id, secret string) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, middleware.JWTClaims{
+			XUserID: uuid.New(),
+		})
+		token.Header["kid"] = id
+		signed, err := token.SignedString([]byte(secret))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+
+	tests := []struct {
+		name     string
+		token    string
+		wantCode codes.Code
+	}{
+		{
+			name:     "jwt signed w/ a configured key id",
+			token:    sign("current", "secret"),
+			wantCode: codes.OK,
+		},
+		{
+			name:     "jwt signed w/ an unknown key id",
+			token:    sign("retired", "secret"),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "jwt signed w/ another secret",
+			token:    sign("current", "other"),
+			wantCode: codes.Unauthenticated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+tt.token))
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+				if _, exists := middleware.JWTClaimsFromContext(ctx); !exists {
+					t.Errorf("expected the claims in the context")
+				}
+				return nil, nil
+			})
+			if code := status.Code(err); code != tt.wantCode {
+				t.Errorf("Auth() code = %v, want %v", code, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package router
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mrinalwahal/boilerplate/pkg/openapi"
+)
+
+// OpenAPI generates the OpenAPI document describing the routes registered on the router.
+func (r *HTTPRouter) OpenAPI() *openapi.Document {
+	document := openapi.New("Records API", "v1")
+
+	// The router is mounted under the `/records` prefix.
+	document.Servers = []openapi.Server{{URL: "/records"}}
+
+	for _, route := range r.routes {
+		operation := openapi.Operation{
+			Summary:   route.Summary,
+			Responses: make(map[string]*openapi.Response),
+		}
+
+		// Add the path parameters.
+		for _, segment := range strings.Split(route.Pattern, "/") {
+			if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+				continue
+			}
+			name := strings.TrimSuffix(strings.TrimPrefix(segment, "{"), "}")
+			schema := openapi.Schema{Type: "string"}
+			if name == "id" {
+				schema.Format = "uuid"
+			}
+			operation.Parameters = append(operation.Parameters, &openapi.Parameter{
+				Name:     name,
+				In:       "path",
+				Required: true,
+				Schema:   &schema,
+			})
+		}
+
+		// Add the query parameters.
+		if route.Query != nil {
+			operation.Parameters = append(operation.Parameters, document.QueryParameters(route.Query)...)
+		}
+
+		// Add the request body.
+		if route.Body != nil {
+			operation.RequestBody = &openapi.RequestBody{
+				Required: true,
+				Content: map[string]*openapi.MediaType{
+					"application/json": {Schema: document.Schema(route.Body)},
+				},
+			}
+		}
+
+		// Add the responses.
+		status := route.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		operation.Responses[strconv.Itoa(status)] = &openapi.Response{
+			Description: http.StatusText(status),
+			Content: map[string]*openapi.MediaType{
+				"application/json": {Schema: envelope(document.Schema(route.Data))},
+			},
+		}
+		operation.Responses[strconv.Itoa(http.StatusBadRequest)] = &openapi.Response{
+			Description: http.StatusText(http.StatusBadRequest),
+			Content: map[string]*openapi.MediaType{
+				"application/json": {Schema: envelope(nil)},
+			},
+		}
+
+		document.AddOperation(route.Method, route.Pattern, &operation)
+	}
+
+	return document
+}
+
+// envelope returns the schema of the default HTTP response structure wrapping the supplied data.
+func envelope(data *openapi.Schema) *openapi.Schema {
+	schema := openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			"message": {Type: "string"},
+			"error":   {Type: "string"},
+		},
+	}
+	if data != nil {
+		schema.Properties["data"] = data
+	}
+	return &schema
+}
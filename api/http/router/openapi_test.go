@@ -0,0 +1,66 @@
+package router
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_Router_OpenAPI(t *testing.T) {
+
+	// Prepare the router.
+	router := NewHTTPRouter(&HTTPRouterConfig{})
+
+	document := router.OpenAPI()
+
+	t.Run("document contains the create record operation", func(t *testing.T) {
+
+		item, exists := document.Paths["/v1"]
+		if !exists {
+			t.Fatalf("expected path %q in the document", "/v1")
+		}
+
+		operation, exists := (*item)["post"]
+		if !exists {
+			t.Fatalf("expected a post operation on path %q", "/v1")
+		}
+
+		if operation.RequestBody == nil {
+			t.Fatalf("expected the create operation to have a request body")
+		}
+
+		if _, exists := operation.Responses["201"]; !exists {
+			t.Fatalf("expected the create operation to have a 201 response")
+		}
+	})
+
+	t.Run("document contains the create record schema", func(t *testing.T) {
+
+		schema, exists := document.Components.Schemas["CreateOptions"]
+		if !exists {
+			t.Fatalf("expected schema %q in the document", "CreateOptions")
+		}
+
+		if _, exists := schema.Properties["title"]; !exists {
+			t.Fatalf("expected property %q in the create schema", "title")
+		}
+
+		// The user ID is read from the JWT claims, not from the request body.
+		if _, exists := schema.Properties["UserID"]; exists {
+			t.Fatalf("expected the user id to be excluded from the create schema")
+		}
+	})
+
+	t.Run("document encodes to yaml", func(t *testing.T) {
+
+		data, err := document.YAML()
+		if err != nil {
+			t.Fatalf("failed to encode the document: %v", err)
+		}
+
+		for _, want := range []string{"openapi: 3.0.3", "/v1:", "CreateOptions:"} {
+			if !strings.Contains(string(data), want) {
+				t.Errorf("expected the document to contain %q", want)
+			}
+		}
+	})
+}
@@ -4,6 +4,9 @@ import (
 	"log/slog"
 	"net/http"
 
+	"github.com/mrinalwahal/boilerplate/model"
+	"github.com/mrinalwahal/boilerplate/pkg/health"
+	"github.com/mrinalwahal/boilerplate/pkg/middleware"
 	v1 "github.com/mrinalwahal/boilerplate/records/handlers/http/v1"
 	"github.com/mrinalwahal/boilerplate/records/service"
 )
@@ -21,13 +24,112 @@ type HTTPRouter struct {
 	//
 	// This field is optional.
 	log *slog.Logger
+
+	// naming is the naming convention of the keys in the JSON responses.
+	naming v1.FieldNaming
+
+	// catalog is the catalog of the localized error messages.
+	catalog v1.Catalog
+
+	// idempotentDelete treats the delete of a missing record as a success.
+	idempotentDelete bool
+
+	// routes is the list of routes registered on the router.
+	routes []Route
+
+	// notFound is the handler of the requests which don't match any route.
+	notFound http.Handler
+}
+
+// Route describes a route registered on the router.
+//
+// Apart from serving the requests, the description is also used to generate the API documentation.
+type Route struct {
+
+	// Method is the HTTP method of the route.
+	//
+	// Example: `http.MethodPost`
+	Method string
+
+	// Pattern is the path pattern of the route.
+	//
+	// Example: `/v1/{id}`
+	Pattern string
+
+	// Summary is a short description of what the route does.
+	Summary string
+
+	// Handler is the handler that serves the route.
+	Handler http.Handler
+
+	// Query is the struct which declares the query parameters accepted by the route using `query` tags.
+	Query any
+
+	// Body is the struct into which the request body is decoded.
+	Body any
+
+	// Data is the value returned in the `data` field of the response.
+	Data any
+
+	// Status is the HTTP status code returned on success.
+	Status int
 }
 
 // HandleFunc registers the handler function for the given pattern.
 // func (r *HTTPRouter) HandleFunc(pattern string, handlerFunc func(w http.ResponseWriter, req *http.Request)) {}
 
 // ServeHTTP handles the incoming HTTP request.
-// func (r *HTTPRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {}
+//
+// The pattern of the matched route is recorded for the `Logging` middleware.
+// The requests which don't match any route are answered with the JSON `404 Not Found` envelope
+// instead of the plain-text one written by `http.ServeMux`.
+func (r *HTTPRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	h, pattern := r.Handler(req)
+	if pattern != "" {
+		middleware.SetRoutePattern(req.Context(), pattern)
+		r.ServeMux.ServeHTTP(w, req)
+		return
+	}
+	h.ServeHTTP(&notFoundWriter{
+		ResponseWriter: w,
+		notFound:       r.notFound,
+		request:        req,
+	}, req)
+}
+
+// notFoundWriter is the response writer which replaces the plain-text `404 Not Found` response with the JSON one.
+//
+// The other responses of the unmatched requests, like `405 Method Not Allowed` or the redirects, are written as is.
+type notFoundWriter struct {
+	http.ResponseWriter
+
+	//	Handler which writes the JSON response.
+	notFound http.Handler
+
+	//	Request being served.
+	request *http.Request
+
+	//	Whether the response has been replaced.
+	replaced bool
+}
+
+// WriteHeader writes the JSON response in place of the `404 Not Found` one.
+func (w *notFoundWriter) WriteHeader(status int) {
+	if status != http.StatusNotFound {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	w.replaced = true
+	w.notFound.ServeHTTP(w.ResponseWriter, w.request)
+}
+
+// Write discards the plain-text body if the response has been replaced.
+func (w *notFoundWriter) Write(b []byte) (int, error) {
+	if w.replaced {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
 
 type HTTPRouterConfig struct {
 
@@ -41,6 +143,33 @@ type HTTPRouterConfig struct {
 	//
 	// This field is optional.
 	Logger *slog.Logger
+
+	// FieldNaming is the naming convention of the keys in the JSON responses.
+	// Default: `v1.SnakeCase`
+	//
+	// This field is optional.
+	FieldNaming v1.FieldNaming
+
+	// Catalog is the catalog of the localized error messages, selected with the `Accept-Language` header.
+	// If it is nil, the error messages are not localized.
+	// Default: `nil`
+	//
+	// This field is optional.
+	Catalog v1.Catalog
+
+	// IdempotentDelete treats the delete of a missing record as a success, answered with `204 No Content`,
+	// so the clients can safely retry the deletes. Otherwise, it's answered with `404 Not Found`.
+	// Default: `false`
+	//
+	// This field is optional.
+	IdempotentDelete bool
+
+	// Checkers are the checkers of the dependencies reported by `GET /healthz`, by the name of the dependency.
+	// If any of them fails, the route responds with `503 Service Unavailable`.
+	// Default: `nil`
+	//
+	// This field is optional.
+	Checkers map[string]health.Checker
 }
 
 // NewHTTPRouter creates a new instance of `HTTPRouter`.
@@ -50,6 +179,10 @@ func NewHTTPRouter(config *HTTPRouterConfig) *HTTPRouter {
 		ServeMux: http.NewServeMux(),
 		service:  config.Service,
 		log:      config.Logger,
+		naming:   config.FieldNaming,
+		catalog:  config.Catalog,
+
+		idempotentDelete: config.IdempotentDelete,
 	}
 
 	// Set the default logger if not provided.
@@ -60,42 +193,159 @@ func NewHTTPRouter(config *HTTPRouterConfig) *HTTPRouter {
 	// router.log = router.log.With("layer", "http")
 
 	// Register the default routes.
-	router.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("OK"))
-	})
+	router.Handle("GET /healthz", health.Handler(&health.Config{
+		Checkers: config.Checkers,
+	}))
 
 	// Register the v1 routes.
 	router.RegisterV1Routes()
 
+	router.notFound = middleware.Chain(
+		v1.Protobuf,
+		v1.FieldNames(router.naming),
+		v1.Localize(router.catalog),
+	)(http.HandlerFunc(v1.NotFound))
+
 	return &router
 }
 
+// Register registers the route on the router.
+//
+// The `GET` routes also serve the `HEAD` requests, with the same headers and without the body.
+func (r *HTTPRouter) Register(route Route) {
+	handler := middleware.Chain(
+		v1.Protobuf,
+		v1.FieldNames(r.naming),
+		v1.Localize(r.catalog),
+		v1.Fields,
+	)(route.Handler)
+	if route.Method == http.MethodGet {
+		handler = middleware.Head(handler)
+	}
+	r.Handle(route.Method+" "+route.Pattern, handler)
+	r.routes = append(r.routes, route)
+}
+
+// Routes returns the list of routes registered on the router.
+func (r *HTTPRouter) Routes() []Route {
+	return r.routes
+}
+
 // RegisterV1Routes registers /v1 routes.
 func (r *HTTPRouter) RegisterV1Routes() {
 
-	r.Handle("POST /v1", v1.NewCreateHandler(&v1.CreateHandlerConfig{
-		Service: r.service,
-		Logger:  r.log,
-	}))
+	r.Register(Route{
+		Method:  http.MethodPost,
+		Pattern: "/v1",
+		Summary: "Create a record.",
+		Handler: v1.NewCreateHandler(&v1.CreateHandlerConfig{
+			Service: r.service,
+			Logger:  r.log,
+		}),
+		Query:  v1.CreateQuery{},
+		Body:   v1.CreateOptions{},
+		Data:   model.Record{},
+		Status: http.StatusCreated,
+	})
 
-	r.Handle("GET /v1", v1.NewListHandler(&v1.ListHandlerConfig{
-		Service: r.service,
-		Logger:  r.log,
-	}))
+	r.Register(Route{
+		Method:  http.MethodGet,
+		Pattern: "/v1",
+		Summary: "List the records.",
+		Handler: middleware.Paginate(&middleware.PaginateConfig{
+			MaxLimit: service.DefaultMaxPageSize,
+			MaxLimitByScope: map[string]int{
+				service.AdminScope: service.DefaultAdminMaxPageSize,
+			},
+			MaxSkip: service.DefaultMaxSkip,
+		})(v1.NewListHandler(&v1.ListHandlerConfig{
+			Service: r.service,
+			Logger:  r.log,
+		})),
+		Query:  v1.ListOptions{},
+		Data:   []model.Record{},
+		Status: http.StatusOK,
+	})
 
-	r.Handle("GET /v1/{id}", v1.NewGetHandler(&v1.GetHandlerConfig{
-		Service: r.service,
-		Logger:  r.log,
-	}))
+	r.Register(Route{
+		Method:  http.MethodGet,
+		Pattern: "/v1/count",
+		Summary: "Count the records.",
+		Handler: v1.NewCountHandler(&v1.CountHandlerConfig{
+			Service: r.service,
+			Logger:  r.log,
+		}),
+		Query:  v1.CountOptions{},
+		Data:   v1.CountResult{},
+		Status: http.StatusOK,
+	})
 
-	r.Handle("PATCH /v1/{id}", v1.NewUpdateHandler(&v1.UpdateHandlerConfig{
-		Service: r.service,
-		Logger:  r.log,
-	}))
+	r.Register(Route{
+		Method:  http.MethodGet,
+		Pattern: "/v1/aggregate",
+		Summary: "Count the records per group.",
+		Handler: v1.NewAggregateHandler(&v1.AggregateHandlerConfig{
+			Service: r.service,
+			Logger:  r.log,
+		}),
+		Query:  v1.AggregateOptions{},
+		Data:   []model.Group{},
+		Status: http.StatusOK,
+	})
 
-	r.Handle("DELETE /v1/{id}", v1.NewDeleteHandler(&v1.DeleteHandlerConfig{
-		Service: r.service,
-		Logger:  r.log,
-	}))
+	r.Register(Route{
+		Method:  http.MethodGet,
+		Pattern: "/v1/{id}",
+		Summary: "Get a record.",
+		Handler: v1.LoadRecord(r.service)(v1.NewGetHandler(&v1.GetHandlerConfig{
+			Service: r.service,
+			Logger:  r.log,
+		})),
+		Data:   model.Record{},
+		Status: http.StatusOK,
+	})
+
+	r.Register(Route{
+		Method:  http.MethodPatch,
+		Pattern: "/v1/{id}",
+		Summary: "Update a record.",
+		Handler: v1.LoadRecord(r.service)(v1.NewUpdateHandler(&v1.UpdateHandlerConfig{
+			Service: r.service,
+			Logger:  r.log,
+		})),
+		Body:   v1.UpdateOptions{},
+		Data:   model.Record{},
+		Status: http.StatusOK,
+	})
+
+	r.Register(Route{
+		Method:  http.MethodPost,
+		Pattern: "/v1/{id}/clone",
+		Summary: "Duplicate a record.",
+		Handler: v1.NewCloneHandler(&v1.CloneHandlerConfig{
+			Service: r.service,
+			Logger:  r.log,
+		}),
+		Data:   model.Record{},
+		Status: http.StatusCreated,
+	})
+
+	// The idempotent deletes skip loading the record, since a missing one isn't an error for them.
+	var deleteHandler http.Handler = v1.NewDeleteHandler(&v1.DeleteHandlerConfig{
+		Service:    r.service,
+		Logger:     r.log,
+		Idempotent: r.idempotentDelete,
+	})
+	deleteStatus := http.StatusNoContent
+	if !r.idempotentDelete {
+		deleteHandler = v1.LoadRecord(r.service)(deleteHandler)
+		deleteStatus = http.StatusOK
+	}
+	r.Register(Route{
+		Method:  http.MethodDelete,
+		Pattern: "/v1/{id}",
+		Summary: "Delete a record.",
+		Handler: deleteHandler,
+		Status:  deleteStatus,
+	})
 }
@@ -5,9 +5,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -40,7 +42,7 @@ func configure(t *testing.T) *testconfig {
 	}
 
 	// Migrate the schema.
-	if err := conn.AutoMigrate(&model.Record{}); err != nil {
+	if err := conn.AutoMigrate(&model.Record{}, &model.AuditLog{}); err != nil {
 		t.Fatalf("failed to migrate the schema: %v", err)
 	}
 
@@ -94,7 +96,7 @@ func Test_Router(t *testing.T) {
 		w := httptest.NewRecorder()
 
 		// Set random UserID in the request context.
-		ctx := context.WithValue(r.Context(), middleware.XJWTClaims, middleware.JWTClaims{
+		ctx := middleware.WithJWTClaims(r.Context(), middleware.JWTClaims{
 			XUserID: uuid.New(),
 		})
 		r = r.WithContext(ctx)
@@ -134,7 +136,7 @@ func Test_Router(t *testing.T) {
 		r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/v1/%s", record.ID), nil)
 		w := httptest.NewRecorder()
 
-		r = r.WithContext(context.WithValue(r.Context(), middleware.XJWTClaims, claims))
+		r = r.WithContext(middleware.WithJWTClaims(r.Context(), claims))
 
 		// Prepare the router.
 		router := NewHTTPRouter(&HTTPRouterConfig{
@@ -158,7 +160,7 @@ func Test_Router(t *testing.T) {
 		r := httptest.NewRequest(http.MethodGet, "/v1", nil)
 		w := httptest.NewRecorder()
 
-		ctx := context.WithValue(r.Context(), middleware.XJWTClaims, middleware.JWTClaims{
+		ctx := middleware.WithJWTClaims(r.Context(), middleware.JWTClaims{
 			XUserID: uuid.New(),
 		})
 		r = r.WithContext(ctx)
@@ -189,6 +191,75 @@ func Test_Router(t *testing.T) {
 		}
 	})
 
+	t.Run("request to count records", func(t *testing.T) {
+
+		owner := middleware.JWTClaims{
+			XUserID: uuid.New(),
+		}
+		other := middleware.JWTClaims{
+			XUserID: uuid.New(),
+		}
+
+		// Seed records for the owner and for another user.
+		for _, seed := range []struct {
+			claims middleware.JWTClaims
+			title  string
+		}{
+			{owner, "alpha"},
+			{owner, "beta"},
+			{owner, "gamma"},
+			{other, "alpha"},
+		} {
+			if _, err := config.service.Create(middleware.WithJWTClaims(context.Background(), seed.claims), &service.CreateOptions{
+				Title:  seed.title,
+				UserID: seed.claims.XUserID,
+			}); err != nil {
+				t.Fatalf("failed to create a record: %v", err)
+			}
+		}
+
+		// Prepare the router.
+		router := NewHTTPRouter(&HTTPRouterConfig{
+			Service: config.service,
+			Logger:  config.log,
+		})
+
+		for target, want := range map[string]float64{
+			"/v1/count":             3,
+			"/v1/count?title=alpha": 1,
+		} {
+
+			// Prepare the r and response recorder.
+			r := httptest.NewRequest(http.MethodGet, target, nil)
+			w := httptest.NewRecorder()
+
+			r = r.WithContext(middleware.WithJWTClaims(r.Context(), owner))
+
+			// Serve the request.
+			router.ServeHTTP(w, r)
+
+			// Check the response status code.
+			if w.Code != http.StatusOK {
+				t.Logf("got response body = %v", w.Body.String())
+				t.Fatalf("expected status code %d, got %d", http.StatusOK, w.Code)
+			}
+
+			var response v1.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("failed to unmarshal the response body: %v", err)
+			}
+
+			data, ok := response.Data.(map[string]interface{})
+			if !ok {
+				t.Fatalf("expected response data to be a JSON object, got %T", response.Data)
+			}
+
+			if data["count"] != want {
+				t.Errorf("%s: expected count to be %v, got %v", target, want, data["count"])
+			}
+		}
+	})
+
 	t.Run("request to update record w/ valid id", func(t *testing.T) {
 
 		claims := middleware.JWTClaims{
@@ -196,7 +267,7 @@ func Test_Router(t *testing.T) {
 		}
 
 		// Create a record.
-		record, err := config.service.Create(context.WithValue(context.Background(), middleware.XJWTClaims, claims), &service.CreateOptions{
+		record, err := config.service.Create(middleware.WithJWTClaims(context.Background(), claims), &service.CreateOptions{
 			Title:  "test",
 			UserID: claims.XUserID,
 		})
@@ -216,7 +287,7 @@ func Test_Router(t *testing.T) {
 		r := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/v1/%s", record.ID), bytes.NewBuffer(body))
 		w := httptest.NewRecorder()
 
-		r = r.WithContext(context.WithValue(r.Context(), middleware.XJWTClaims, claims))
+		r = r.WithContext(middleware.WithJWTClaims(r.Context(), claims))
 
 		// Prepare the router.
 		router := NewHTTPRouter(&HTTPRouterConfig{
@@ -260,7 +331,7 @@ func Test_Router(t *testing.T) {
 		}
 
 		// Create a record.
-		record, err := config.service.Create(context.WithValue(context.Background(), middleware.XJWTClaims, claims), &service.CreateOptions{
+		record, err := config.service.Create(middleware.WithJWTClaims(context.Background(), claims), &service.CreateOptions{
 			Title:  "test",
 			UserID: claims.XUserID,
 		})
@@ -273,7 +344,7 @@ func Test_Router(t *testing.T) {
 		w := httptest.NewRecorder()
 
 		// Set random UserID in the request context.
-		r = r.WithContext(context.WithValue(r.Context(), middleware.XJWTClaims, claims))
+		r = r.WithContext(middleware.WithJWTClaims(r.Context(), claims))
 
 		// Prepare the router.
 		router := NewHTTPRouter(&HTTPRouterConfig{
@@ -296,4 +367,171 @@ func Test_Router(t *testing.T) {
 			t.Fatal("expected to get an error, got nil")
 		}
 	})
+	t.Run("request to delete missing record w/ idempotent deletes", func(t *testing.T) {
+
+		// Prepare the r and response recorder.
+		r := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/v1/%s", uuid.New()), nil)
+		w := httptest.NewRecorder()
+
+		// Prepare the router.
+		router := NewHTTPRouter(&HTTPRouterConfig{
+			Service:          config.service,
+			Logger:           config.log,
+			IdempotentDelete: true,
+		})
+
+		// Serve the request.
+		router.ServeHTTP(w, r)
+
+		// Retrying the delete of a missing record is a success.
+		if w.Code != http.StatusNoContent {
+			t.Logf("got response body = %v", w.Body.String())
+			t.Fatalf("expected status code %d, got %d", http.StatusNoContent, w.Code)
+		}
+	})
+
+	t.Run("request to unknown route", func(t *testing.T) {
+
+		// Prepare the r and response recorder.
+		r := httptest.NewRequest(http.MethodGet, "/v2/unknown", nil)
+		w := httptest.NewRecorder()
+
+		// Prepare the router.
+		router := NewHTTPRouter(&HTTPRouterConfig{
+			Service: config.service,
+			Logger:  config.log,
+		})
+
+		// Serve the request.
+		middleware.RequestID(nil)(router).ServeHTTP(w, r)
+
+		// Check the response status code.
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("expected status code %d, got %d", http.StatusNotFound, w.Code)
+		}
+
+		// Check the response content type.
+		if got := w.Header().Get("Content-Type"); got != "application/json" {
+			t.Fatalf("expected content type %q, got %q", "application/json", got)
+		}
+
+		// Decode the response body.
+		var response v1.Response
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("failed to decode the response body: %v", err)
+		}
+
+		// Check the response body.
+		if response.Err == nil || response.Err.Error() != v1.ErrRouteNotFound.Error() {
+			t.Fatalf("expected error %q, got %v", v1.ErrRouteNotFound, response.Err)
+		}
+		if id := w.Header().Get(string(middleware.XRequestID)); response.RequestID != id {
+			t.Fatalf("expected request id %q, got %q", id, response.RequestID)
+		}
+	})
+
+	t.Run("request to known route w/ unsupported method", func(t *testing.T) {
+
+		// Prepare the r and response recorder.
+		r := httptest.NewRequest(http.MethodPut, "/v1", nil)
+		w := httptest.NewRecorder()
+
+		// Prepare the router.
+		router := NewHTTPRouter(&HTTPRouterConfig{
+			Service: config.service,
+			Logger:  config.log,
+		})
+
+		// Serve the request.
+		router.ServeHTTP(w, r)
+
+		// Check the response status code.
+		if w.Code != http.StatusMethodNotAllowed {
+			t.Fatalf("expected status code %d, got %d", http.StatusMethodNotAllowed, w.Code)
+		}
+	})
+
+	t.Run("request to get record logs the route pattern", func(t *testing.T) {
+
+		var buffer bytes.Buffer
+
+		// Prepare the r and response recorder.
+		r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/v1/%s", uuid.New()), nil)
+		w := httptest.NewRecorder()
+
+		// Prepare the router.
+		router := NewHTTPRouter(&HTTPRouterConfig{
+			Service: config.service,
+			Logger:  config.log,
+		})
+
+		// Serve the request.
+		middleware.Logging(&middleware.LoggingConfig{
+			Logger: slog.New(slog.NewTextHandler(&buffer, nil)),
+		})(router).ServeHTTP(w, r)
+
+		if !strings.Contains(buffer.String(), "path=/v1/{id}") {
+			t.Fatalf("expected the route pattern in the log, got %q", buffer.String())
+		}
+	})
+
+	t.Run("head requests to read routes", func(t *testing.T) {
+
+		// Create a record.
+		record, err := config.service.Create(context.Background(), &service.CreateOptions{
+			Title:  "head",
+			UserID: uuid.New(),
+		})
+		if err != nil {
+			t.Fatalf("failed to create a record: %v", err)
+		}
+
+		// Serve the router over a real connection, so the server computes the headers of the GET responses.
+		ts := httptest.NewServer(NewHTTPRouter(&HTTPRouterConfig{
+			Service: config.service,
+			Logger:  config.log,
+		}))
+		defer ts.Close()
+
+		for _, path := range []string{"/v1", fmt.Sprintf("/v1/%s", record.ID)} {
+
+			get, err := http.Get(ts.URL + path)
+			if err != nil {
+				t.Fatalf("failed to send the GET request: %v", err)
+			}
+			get.Body.Close()
+
+			head, err := http.Head(ts.URL + path)
+			if err != nil {
+				t.Fatalf("failed to send the HEAD request: %v", err)
+			}
+			body, _ := io.ReadAll(head.Body)
+			head.Body.Close()
+
+			if head.StatusCode != get.StatusCode {
+				t.Fatalf("%s: expected status code %d, got %d", path, get.StatusCode, head.StatusCode)
+			}
+			if len(body) != 0 {
+				t.Fatalf("%s: expected an empty body, got %q", path, body)
+			}
+			assertSameHeaders(t, get.Header, head.Header, "Date")
+		}
+	})
+}
+
+// assertSameHeaders asserts that both sets of headers hold the same values, apart from the ignored ones.
+func assertSameHeaders(t *testing.T, want, got http.Header, ignored ...string) {
+	t.Helper()
+	for _, key := range ignored {
+		want.Del(key)
+		got.Del(key)
+	}
+	if len(want) != len(got) {
+		t.Fatalf("expected headers %v, got %v", want, got)
+	}
+	for key := range want {
+		if want.Get(key) != got.Get(key) {
+			t.Fatalf("expected header %s = %q, got %q", key, want.Get(key), got.Get(key))
+		}
+	}
 }
@@ -4,16 +4,30 @@ import (
 	"fmt"
 	"log"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/mrinalwahal/boilerplate/api/graphql"
+	rpc "github.com/mrinalwahal/boilerplate/api/grpc"
+	"github.com/mrinalwahal/boilerplate/api/grpc/recordspb"
 	"github.com/mrinalwahal/boilerplate/api/http/router"
+	"github.com/mrinalwahal/boilerplate/config"
+	"github.com/mrinalwahal/boilerplate/pkg/db/timing"
+	"github.com/mrinalwahal/boilerplate/pkg/db/unbounded"
+	"github.com/mrinalwahal/boilerplate/pkg/health"
+	logs "github.com/mrinalwahal/boilerplate/pkg/logger"
 	"github.com/mrinalwahal/boilerplate/pkg/middleware"
+	"github.com/mrinalwahal/boilerplate/pkg/server"
+	"github.com/mrinalwahal/boilerplate/pkg/version"
 	"github.com/mrinalwahal/boilerplate/records/db"
+	v1 "github.com/mrinalwahal/boilerplate/records/handlers/http/v1"
 	"github.com/mrinalwahal/boilerplate/records/service"
+	"google.golang.org/grpc"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 
@@ -27,31 +41,63 @@ func main() {
 		log.Println("Error loading .env.development file")
 	}
 
+	// Load the config file, whose log level follows the changes of the file once it's watched.
+	loaded := true
+	if err := config.Load(".", "config"); err != nil {
+		log.Println("Error loading the config file:", err)
+		loaded = false
+	}
+
 	//	Setup the logger.
-	level := slog.LevelInfo
+	level := config.Level()
 	addSource := false
 	DEBUG, err := strconv.ParseBool(os.Getenv("DEBUG"))
 	if err != nil {
 		panic(err)
 	}
 	if DEBUG {
-		level = slog.LevelDebug
+		level.Set(slog.LevelDebug)
 		addSource = true
 	}
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-		AddSource: addSource,
+
+	// The destination and the format of the logs come from the logs section of the config.
+	// Without one, the logger falls back to its defaults.
+	settings := logs.Config{
 		Level:     level,
-	}))
+		AddSource: addSource,
+	}
+	if section := config.Get().Logs; section != nil {
+		settings.Engine = section.Engine
+		settings.Address = section.Address
+		settings.Format = section.Format
+	}
+	logger, closer, err := logs.New(&settings)
+	if err != nil {
+		panic(err)
+	}
+	defer closer.Close()
 	logger = logger.
 		With("service", "record").
 		With("environment", os.Getenv("ENV"))
 
+	// Reload the log level whenever the config file changes.
+	if loaded {
+		config.Watch(func(*config.Config) {
+			logger.Info("reloaded the config", slog.String("level", level.Level().String()))
+		})
+	}
+
+	// Correlate the logs of the service and the database layers through the IDs of the request they serve.
+	layers := slog.New(logs.NewContextHandler(logger.Handler()))
+
 	//	Setup the gorm logger.
-	handler := logger.With("layer", "database").Handler()
+	//	The queries are traced at the debug level, and the handler filters them against the reloadable level,
+	//	so the database logs follow the changes of the config file like the other ones.
+	handler := layers.With("layer", "database").Handler()
 	gormLogger := slogGorm.New(
-		slogGorm.WithHandler(handler),                        // since v1.3.0
-		slogGorm.WithTraceAll(),                              // trace all messages
-		slogGorm.SetLogLevel(slogGorm.DefaultLogType, level), // set log level (default: slog.LevelInfo)
+		slogGorm.WithHandler(handler),                                  // since v1.3.0
+		slogGorm.WithTraceAll(),                                        // trace all messages
+		slogGorm.SetLogLevel(slogGorm.DefaultLogType, slog.LevelDebug), // set log level (default: slog.LevelInfo)
 	)
 
 	// Open a database connection.
@@ -62,6 +108,22 @@ func main() {
 		panic(err)
 	}
 
+	// Report the time spent in the database in the `Server-Timing` header.
+	if err := conn.Use(timing.Plugin{}); err != nil {
+		panic(err)
+	}
+
+	// Report the reads which list the records without a limit: `warn` logs them, `strict` fails them too.
+	if mode := os.Getenv("UNBOUNDED_QUERIES"); mode == "warn" || mode == "strict" {
+		if err := conn.Use(unbounded.Plugin{
+			Tables: []string{"records"},
+			Strict: mode == "strict",
+			Logger: layers.With("layer", "database"),
+		}); err != nil {
+			panic(err)
+		}
+	}
+
 	sqlDB, err := conn.DB()
 	if err != nil {
 		panic(err)
@@ -77,7 +139,8 @@ func main() {
 
 	// Connect the database layer.
 	db := db.NewSQLDB(&db.SQLDBConfig{
-		DB: conn,
+		DB:     conn,
+		Logger: layers,
 	})
 
 	// GORM provides Prometheus plugin to collect DBStats or user-defined metrics
@@ -97,53 +160,154 @@ func main() {
 	// 	}, // user defined metrics
 	// }))
 
+	// Strip or reject the HTML markup in the titles if the deployment asks for it.
+	titlePolicy := service.TitleAllowHTML
+	switch os.Getenv("TITLE_HTML") {
+	case "sanitize":
+		titlePolicy = service.TitleSanitizeHTML
+	case "reject":
+		titlePolicy = service.TitleRejectHTML
+	}
+
 	// Get the service layer.
 	service := service.NewService(&service.Config{
-		DB:     db,
-		Logger: logger,
+		DB:          db,
+		Logger:      layers,
+		TitlePolicy: titlePolicy,
 	})
 
+	// Render the response keys in camelCase if the deployment asks for it.
+	naming := v1.SnakeCase
+	if os.Getenv("JSON_FIELD_NAMING") == "camel" {
+		naming = v1.CamelCase
+	}
+
 	//	Initialize the router.
 	router := router.NewHTTPRouter(&router.HTTPRouterConfig{
-		Service: service,
-		Logger:  logger,
+		Service:     service,
+		Logger:      logger,
+		FieldNaming: naming,
+		Catalog:     v1.DefaultCatalog,
+
+		IdempotentDelete: os.Getenv("DELETE_MODE") == "idempotent",
+		Checkers: map[string]health.Checker{
+			"db": health.Ping(sqlDB),
+		},
 	})
 
 	// Prepare the middleware chain.
 	// The order of the middlewares is important.
 	// Recommended order: Request ID -> RateLimit -> CORS -> Logging -> Recover -> Auth -> Cache -> Compression
 	middlewareLogger := logger.With("protocol", "HTTP/1.0")
+
+	// The JWTs are validated with the same keys by the HTTP and the gRPC transports.
+	jwtConfig := &middleware.JWTConfig{
+		Key:          os.Getenv("JWT_SECRET"),
+		Keys:         keysFromEnv("JWT_KEYS"),
+		JWKSURL:      os.Getenv("JWT_JWKS_URL"),
+		JWKSCacheTTL: durationFromEnv("JWT_JWKS_CACHE_TTL"),
+		ExceptionalRoutes: []string{
+			"/login",
+			"/healthz",
+			"/version",
+		},
+	}
 	chain := middleware.Chain(
-		middleware.RequestID,
-		middleware.TraceID,
-		middleware.CorrelationID,
-		// TODO: middleware.RateLimit,
-		middleware.CORS(nil),
+		middleware.RequestID(&middleware.IDConfig{
+			Header: middleware.Key(os.Getenv("REQUEST_ID_HEADER")),
+			Prefix: os.Getenv("ID_HEADER_PREFIX"),
+		}),
+		middleware.TraceID(&middleware.IDConfig{
+			Header: middleware.Key(os.Getenv("TRACE_ID_HEADER")),
+			Prefix: os.Getenv("ID_HEADER_PREFIX"),
+		}),
+		middleware.CorrelationID(&middleware.IDConfig{
+			Header: middleware.Key(os.Getenv("CORRELATION_ID_HEADER")),
+			Prefix: os.Getenv("ID_HEADER_PREFIX"),
+		}),
+		middleware.ServerTiming(&middleware.ServerTimingConfig{
+			Expose: DEBUG,
+		}),
+		middleware.RequestLimits(nil),
+		middleware.Decompress(nil),
+		middleware.RateLimit(&middleware.RateLimitConfig{
+			Limit:  intFromEnv("RATE_LIMIT"),
+			Window: durationFromEnv("RATE_LIMIT_WINDOW"),
+		}),
+		// Let the browsers cache the CORS preflights for the configured duration.
+		middleware.CORS(&middleware.CORSConfig{
+			MaxAge: durationFromEnv("CORS_MAX_AGE"),
+		}),
+		middleware.Accept,
 		middleware.Recover(&middleware.RecoverConfig{
-			Logger: middlewareLogger,
+			Logger:                 middlewareLogger,
+			IncludeStackInResponse: os.Getenv("ENV") == "dev",
 		}),
 		middleware.Logging(&middleware.LoggingConfig{
 			Logger: middlewareLogger,
 		}),
-		middleware.JWT(&middleware.JWTConfig{
-			Key: os.Getenv("JWT_SECRET"),
-			ExceptionalRoutes: []string{
-				"/login",
-				"/healthz",
-			},
-		}),
+		middleware.JWT(jwtConfig),
 	)
 
 	// Prepare the base router.
 	baseRouter := http.NewServeMux()
-	baseRouter.Handle("/records/", http.StripPrefix("/records", router))
+	var records http.Handler = router
+
+	// Run every request of the records in a transaction if the deployment asks for it.
+	if os.Getenv("REQUEST_TRANSACTIONS") == "true" {
+		records = middleware.Transaction(conn)(records)
+	}
+
+	// Coalesce the concurrent identical reads of the records if the deployment asks for it.
+	if os.Getenv("COALESCE_READS") == "true" {
+		records = middleware.Singleflight(records)
+	}
+	baseRouter.Handle("/records/", http.StripPrefix("/records", records))
+	baseRouter.Handle("GET /version", version.Handler(version.Current()))
+	baseRouter.Handle("POST /graphql", graphql.NewHandler(&graphql.HandlerConfig{
+		Service: service,
+		Logger:  logger,
+	}))
 
 	//	Configure and start the server.
-	server := http.Server{
+	// The timeouts come from the server section of the config. The ones left unset fall back to the defaults of the `server` package.
+	serverConfig := server.Config{
 		Addr:     ":8080",
 		Handler:  chain(baseRouter),
 		ErrorLog: slog.NewLogLogger(logger.Handler(), slog.LevelError),
 	}
+	if section := config.Get().Server; section != nil {
+		serverConfig.ReadHeaderTimeout = section.ReadHeaderTimeout
+		serverConfig.ReadTimeout = section.ReadTimeout
+		serverConfig.WriteTimeout = section.WriteTimeout
+		serverConfig.IdleTimeout = section.IdleTimeout
+	}
+	server := server.New(&serverConfig)
+
+	// Configure and start the gRPC server for the internal service-to-service calls.
+	grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		rpc.Recover(&rpc.RecoverConfig{
+			Logger: logger.With("protocol", "gRPC"),
+		}),
+		rpc.Logging(&rpc.LoggingConfig{
+			Logger: logger.With("protocol", "gRPC"),
+		}),
+		rpc.Auth(&rpc.AuthConfig{
+			JWT: jwtConfig,
+		}),
+	))
+	recordspb.RegisterRecordServiceServer(grpcServer, rpc.NewServer(&rpc.ServerConfig{
+		Service: service,
+		Logger:  logger,
+	}))
+	go func() {
+		listener, err := net.Listen("tcp", ":9090")
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println("gRPC server is running on port 9090")
+		grpcServer.Serve(listener)
+	}()
 
 	fmt.Println("Server is running on port 8080")
 	server.ListenAndServe()
@@ -153,3 +317,49 @@ func main() {
 		panic(err)
 	}
 }
+
+// durationFromEnv parses the Go duration in the environment variable, for example `10m`.
+// It returns zero if the variable is unset.
+func durationFromEnv(name string) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		panic(fmt.Errorf("%s: %w", name, err))
+	}
+	return duration
+}
+
+// intFromEnv parses the integer in the environment variable.
+// It returns zero if the variable is unset.
+func intFromEnv(name string) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		panic(fmt.Errorf("%s: %w", name, err))
+	}
+	return n
+}
+
+// keysFromEnv parses the comma-separated `kid:secret` pairs in the environment variable, for example `2024:foo,2025:bar`.
+// It returns nil if the variable is unset.
+func keysFromEnv(name string) map[string]string {
+	value := os.Getenv(name)
+	if value == "" {
+		return nil
+	}
+	keys := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		id, key, found := strings.Cut(strings.TrimSpace(pair), ":")
+		if !found || id == "" || key == "" {
+			panic(fmt.Errorf("%s: invalid key %q", name, pair))
+		}
+		keys[id] = key
+	}
+	return keys
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/mrinalwahal/boilerplate/api/http/router"
+)
+
+// Generates the OpenAPI document of the HTTP API.
+//
+// Usage: go run ./cmd/openapi -out openapi.yaml
+func main() {
+	out := flag.String("out", "", "path of the file to write the document to (default: stdout)")
+	flag.Parse()
+
+	// The handlers are only described, never served, so the router doesn't need a service layer.
+	router := router.NewHTTPRouter(&router.HTTPRouterConfig{})
+
+	data, err := router.OpenAPI().YAML()
+	if err != nil {
+		log.Fatalf("failed to encode the document: %v", err)
+	}
+
+	if *out == "" {
+		os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(*out, data, 0644); err != nil {
+		log.Fatalf("failed to write the document: %v", err)
+	}
+}
@@ -1,16 +1,24 @@
-package main
+// Package config reads the configuration of the service from its `config.toml` file,
+// and reloads the fields which are safe to change at runtime.
+package config
 
 import (
 	"fmt"
+	"log/slog"
+	"sync"
+	"time"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
 )
 
 // Base configuration.
-type config struct {
+type Config struct {
 	Environment    *environment    `mapstructure:"environment"`
 	Database       *database       `mapstructure:"database"`
 	Authentication *authentication `mapstructure:"authentication"`
+	Logs           *logs           `mapstructure:"logs"`
+	Server         *server         `mapstructure:"server"`
 }
 
 // Environment configuration.
@@ -34,25 +42,124 @@ type authentication struct {
 	} `mapstructure:"key"`
 }
 
-var c config
+// Logs configuration.
+type logs struct {
+	Engine  string `mapstructure:"engine"`  // stdout, stderr or file
+	Address string `mapstructure:"address"` // Path of the file for the file engine
+	Format  string `mapstructure:"format"`  // json or text
+	Level   string `mapstructure:"level"`
+}
+
+// Server configuration.
+type server struct {
+	ReadHeaderTimeout time.Duration `mapstructure:"read_header_timeout"`
+	ReadTimeout       time.Duration `mapstructure:"read_timeout"`
+	WriteTimeout      time.Duration `mapstructure:"write_timeout"`
+	IdleTimeout       time.Duration `mapstructure:"idle_timeout"`
+}
+
+var (
+	mu sync.RWMutex
+	c  Config
+
+	// level is the log level of the config, kept up to date by `Watch`.
+	level = new(slog.LevelVar)
+)
+
+// Get returns a snapshot of the config.
+//
+// The snapshots aren't updated on reload, so call it again to read the latest values.
+func Get() *Config {
+	mu.RLock()
+	defer mu.RUnlock()
+	snapshot := c
+	return &snapshot
+}
+
+// Level returns the log level of the config, which follows its changes once `Watch` is called.
+// Pass it as the level of the logger, e.g. `logger.Config{Level: Level()}`.
+func Level() *slog.LevelVar {
+	return level
+}
+
+// Watch reloads the config whenever its file changes, and calls `onChange` with the reloaded config.
+//
+// Only the fields which are safe to change at runtime are reloaded, see `reload`.
+// The changes which fail to parse are ignored, and the previous values are kept.
+func Watch(onChange func(*Config)) {
+	watch(viper.GetViper(), onChange)
+}
 
-func Get() *config {
-	return &c
+// watch reloads the config whenever the file read by the supplied viper instance changes.
+func watch(v *viper.Viper, onChange func(*Config)) {
+	v.OnConfigChange(func(fsnotify.Event) {
+		var next Config
+		if err := v.Unmarshal(&next); err != nil {
+			return
+		}
+		if err := reload(&next); err != nil {
+			return
+		}
+		if onChange != nil {
+			onChange(Get())
+		}
+	})
+	v.WatchConfig()
 }
 
-func init() {
+// reload applies the fields of the changed config which are safe to change at runtime: the log level.
+// The others, like the database engine, only take effect on restart.
+// A missing or empty log level leaves the current one unchanged.
+func reload(next *Config) error {
+	if next.Logs == nil || next.Logs.Level == "" {
+		return nil
+	}
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(next.Logs.Level)); err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// Replace the section instead of updating it in place, since the earlier snapshots share it.
+	section := logs{}
+	if c.Logs != nil {
+		section = *c.Logs
+	}
+	section.Level = next.Logs.Level
+	c.Logs = &section
+	level.Set(l)
+	return nil
+}
+
+// Load reads the config from the `config.toml` file of the first of the directories which has one,
+// the working directory by default. The environment variables override the values of the file.
+func Load(paths ...string) error {
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
 	viper.SetConfigName("config")
-	viper.AddConfigPath(".")
+	for _, path := range paths {
+		viper.AddConfigPath(path)
+	}
 	viper.AutomaticEnv()
 	if err := viper.ReadInConfig(); err != nil {
-		panic(fmt.Sprintf("unable to read config file, %v", err))
+		return fmt.Errorf("unable to read config file, %w", err)
 	}
-	err := viper.Unmarshal(&c)
-	if err != nil {
-		panic(fmt.Sprintf("unable to decode into struct, %v", err))
+
+	var next Config
+	if err := viper.Unmarshal(&next); err != nil {
+		return fmt.Errorf("unable to decode into struct, %w", err)
+	}
+	if next.Logs != nil && next.Logs.Level != "" {
+		if err := level.UnmarshalText([]byte(next.Logs.Level)); err != nil {
+			return fmt.Errorf("unable to decode the log level, %w", err)
+		}
 	}
-}
 
-func main() {
-	fmt.Println(Get().Database)
+	mu.Lock()
+	defer mu.Unlock()
+	c = next
+	return nil
 }
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestWatch(t *testing.T) {
+
+	// Write the config to a temporary file, so the change doesn't touch the real one.
+	path := filepath.Join(t.TempDir(), "config.toml")
+	write := func(content string) {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write the config: %v", err)
+		}
+	}
+	write("[logs]\nlevel = \"info\"\n\n[database]\nengine = \"postgres\"\n")
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		t.Fatalf("failed to read the config: %v", err)
+	}
+
+	if err := Load(); err != nil {
+		t.Fatalf("failed to load the config: %v", err)
+	}
+
+	engine := Get().Database.Engine
+	changed := make(chan *Config, 1)
+	watch(v, func(c *Config) {
+		select {
+		case changed <- c:
+		default:
+		}
+	})
+
+	// Change the log level, along with a field which is unsafe to change at runtime.
+	write("[logs]\nlevel = \"debug\"\n\n[database]\nengine = \"sqlite\"\n")
+
+	var got *Config
+	select {
+	case got = <-changed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the config to be reloaded")
+	}
+
+	if got.Logs.Level != "debug" {
+		t.Errorf("Logs.Level = %q, want %q", got.Logs.Level, "debug")
+	}
+	if Level().Level() != slog.LevelDebug {
+		t.Errorf("Level() = %v, want %v", Level().Level(), slog.LevelDebug)
+	}
+	if got.Database.Engine != engine {
+		t.Errorf("Database.Engine = %q, want the engine read on startup %q", got.Database.Engine, engine)
+	}
+}
+
+func TestReload(t *testing.T) {
+
+	level.Set(slog.LevelWarn)
+
+	// The missing and empty levels leave the current one unchanged.
+	for _, next := range []*Config{
+		{},
+		{Logs: &logs{}},
+	} {
+		if err := reload(next); err != nil {
+			t.Fatalf("reload() error = %v, want nil", err)
+		}
+		if Level().Level() != slog.LevelWarn {
+			t.Errorf("Level() = %v, want %v", Level().Level(), slog.LevelWarn)
+		}
+	}
+
+	if err := reload(&Config{Logs: &logs{Level: "loud"}}); err == nil {
+		t.Errorf("reload() error = nil, want an error for an invalid level")
+	}
+}
+
+func TestLoad(t *testing.T) {
+
+	if err := Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	// The durations of the server section are decoded from the Go duration strings.
+	server := Get().Server
+	if server == nil || server.ReadHeaderTimeout != 5*time.Second || server.IdleTimeout != 120*time.Second {
+		t.Errorf("Get().Server = %+v, want the timeouts of config.toml", server)
+	}
+}
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Actions recorded in the audit trail.
+const (
+	AuditActionCreate   = "create"
+	AuditActionImport   = "import"
+	AuditActionUpdate   = "update"
+	AuditActionDelete   = "delete"
+	AuditActionReassign = "reassign"
+)
+
+// AuditLog is an entry of the audit trail.
+//
+// An entry is written in the same transaction as every mutation of an entity,
+// so the trail can't diverge from the data. The entries are never updated or deleted.
+type AuditLog struct {
+
+	// ID is the unique identifier of the entry.
+	// It is generated automatically when the entry is created.
+	//
+	// Example: "550e8400-e29b-41d4-a716-446655440000"
+	ID uuid.UUID `json:"id" gorm:"primaryKey;not null;type:uuid"`
+
+	// CreatedAt is the time when the mutation happened.
+	//
+	// Example: "2021-07-01T12:00:00Z"
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime;index"`
+
+	// ActorID is the ID of the user who performed the mutation.
+	// It is nil when the mutation wasn't performed on behalf of a user.
+	//
+	// Example: "550e8400-e29b-41d4-a716-446655440000"
+	ActorID uuid.UUID `json:"actor_id" gorm:"not null;type:uuid;index"`
+
+	// Action is the mutation performed on the entity.
+	//
+	// Example: "update"
+	Action string `json:"action" gorm:"not null"`
+
+	// Entity is the kind of the mutated entity.
+	//
+	// Example: "record"
+	Entity string `json:"entity" gorm:"not null"`
+
+	// EntityID is the ID of the mutated entity.
+	//
+	// Example: "550e8400-e29b-41d4-a716-446655440000"
+	EntityID uuid.UUID `json:"entity_id" gorm:"not null;type:uuid;index"`
+
+	// Diff holds the fields changed by the mutation, as a JSON object.
+	//
+	// Example: {"title": "Updated Record"}
+	//
+	// It is an optional field.
+	Diff json.RawMessage `json:"diff,omitempty" gorm:"type:jsonb"`
+}
+
+// BeforeCreate hook for gorm.
+// This function is called by gorm before creating an entry.
+//
+// It generates a new UUID for the entry.
+func (a *AuditLog) BeforeCreate(tx *gorm.DB) error {
+	a.ID = uuid.New()
+	return nil
+}
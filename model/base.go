@@ -17,9 +17,10 @@ type Base struct {
 
 	// CreatedAt is the time when the object was created.
 	// It is set automatically when the object is created.
+	// It's indexed to serve the date-range filters without scanning the whole table.
 	//
 	// Example: "2021-07-01T12:00:00Z"
-	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime;index"`
 
 	// UpdatedAt is the time when the object was last updated.
 	// It is set automatically when the object is updated.
@@ -0,0 +1,13 @@
+package model
+
+// Group is the number of records which share the same key, as returned by the aggregations.
+type Group struct {
+
+	// Key shared by the records of the group.
+	//
+	// Example: "2026-10-17"
+	Key string `json:"key"`
+
+	// Count is the number of records in the group.
+	Count int64 `json:"count"`
+}
@@ -10,12 +10,21 @@ type Record struct {
 	// Example: "Test Record"
 	//
 	// It is a required field.
-	Title string `json:"title" gorm:"not null;check:(length(title)>0)"`
+	// It's unique per user among the records which aren't deleted, so `(user_id, title)` is the natural key
+	// the upserts conflict on. The deleted records don't hold their titles, so a title can be reused once deleted.
+	Title string `json:"title" gorm:"not null;check:(length(title)>0);uniqueIndex:idx_records_user_id_title,priority:2,where:deleted_at IS NULL"`
+
+	// Description of the record.
+	//
+	// Example: "Notes about the test record."
+	//
+	// It is an optional field.
+	Description string `json:"description" gorm:"not null;default:''"`
 
 	//	ID of the user who created the record.
 	//
 	//	Example: "550e8400-e29b-41d4-a716-446655440000"
 	//
 	//	It is a required field.
-	UserID uuid.UUID `json:"user_id" gorm:"not null;type:uuid"`
+	UserID uuid.UUID `json:"user_id" gorm:"not null;type:uuid;uniqueIndex:idx_records_user_id_title,priority:1,where:deleted_at IS NULL"`
 }
@@ -0,0 +1,13 @@
+package model
+
+// Result is the outcome of a mutation, as returned by the `...Result` variants of the operations.
+type Result struct {
+
+	// Record is the record as it is after the mutation, unless the mutation deleted it.
+	Record *Record `json:"record,omitempty"`
+
+	// RowsAffected is the number of records changed by the mutation.
+	//
+	// Example: 1
+	RowsAffected int64 `json:"rows_affected"`
+}
@@ -0,0 +1,236 @@
+package breaker
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mrinalwahal/boilerplate/pkg/errs"
+	"gorm.io/gorm"
+)
+
+// ErrServiceUnavailable is returned when the breaker is open and the call is rejected without being attempted.
+var ErrServiceUnavailable = errs.Wrap(errs.Unavailable, "service unavailable")
+
+// State is the state of the circuit breaker.
+type State int
+
+const (
+
+	// Closed lets all the calls through.
+	Closed State = iota
+
+	// Open rejects all the calls until the cooldown has elapsed.
+	Open
+
+	// HalfOpen lets a single trial call through to decide whether the breaker should close again.
+	HalfOpen
+)
+
+type Config struct {
+
+	// Threshold is the number of consecutive failures after which the breaker opens.
+	// Default: `5`
+	//
+	// This field is optional.
+	Threshold int
+
+	// Cooldown is the duration for which the breaker stays open before letting a trial call through.
+	// Default: `30s`
+	//
+	// This field is optional.
+	Cooldown time.Duration
+
+	// IsFailure is the function that determines if the error returned by a call counts as a failure.
+	// Default: `IsInfrastructureFailure`
+	//
+	// This field is optional.
+	IsFailure func(error) bool
+}
+
+// Breaker is a circuit breaker which fast-fails calls to the database once it is considered unhealthy.
+//
+// After `Threshold` consecutive failures, the breaker opens and rejects all the calls with `ErrServiceUnavailable`.
+// Once the `Cooldown` has elapsed, the breaker half-opens and lets a single trial call through.
+// If the trial call succeeds, the breaker closes. Otherwise, it opens again.
+type Breaker struct {
+	mu sync.Mutex
+
+	threshold int
+	cooldown  time.Duration
+	isFailure func(error) bool
+
+	// now returns the current time. It is replaced in tests.
+	now func() time.Time
+
+	state    State
+	failures int
+	openedAt time.Time
+}
+
+// New creates a new instance of `Breaker`.
+func New(config *Config) *Breaker {
+
+	// Set the default configuration.
+	if config == nil {
+		config = &Config{}
+	}
+
+	b := Breaker{
+		threshold: config.Threshold,
+		cooldown:  config.Cooldown,
+		isFailure: config.IsFailure,
+		now:       time.Now,
+	}
+
+	if b.threshold <= 0 {
+		b.threshold = 5
+	}
+
+	if b.cooldown <= 0 {
+		b.cooldown = 30 * time.Second
+	}
+
+	if b.isFailure == nil {
+		b.isFailure = IsInfrastructureFailure
+	}
+
+	return &b
+}
+
+// State returns the current state of the breaker.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == Open && b.now().Sub(b.openedAt) >= b.cooldown {
+		return HalfOpen
+	}
+	return b.state
+}
+
+// Do runs the supplied function if the breaker allows it, and records its outcome.
+func (b *Breaker) Do(fn func() error) error {
+	done, err := b.Begin()
+	if err != nil {
+		return err
+	}
+	err = fn()
+	done(err)
+	return err
+}
+
+// Begin is the asynchronous counterpart of `Do`, for the calls which outlive the caller, like streams.
+// If the breaker allows the call, it returns the function which must be called with the outcome of the call once it completes.
+// Otherwise, it returns `ErrServiceUnavailable`.
+func (b *Breaker) Begin() (func(error), error) {
+	if !b.allow() {
+		return nil, ErrServiceUnavailable
+	}
+	return b.record, nil
+}
+
+// IsInfrastructureFailure reports whether the error was caused by the database being unhealthy,
+// like a broken connection, a timeout or a server running out of resources.
+//
+// The errors caused by the request itself never count as failures, whatever the number of them:
+// the canonical errors of `pkg/errs`, the missing records, the constraint violations
+// and the errors of the caller's own context, like its deadline being exceeded.
+func IsInfrastructureFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	for _, target := range []error{
+		errs.InvalidArgument,
+		errs.Unprocessable,
+		errs.NotFound,
+		errs.PermissionDenied,
+		errs.AlreadyExists,
+		gorm.ErrRecordNotFound,
+		gorm.ErrDuplicatedKey,
+		context.Canceled,
+		context.DeadlineExceeded,
+	} {
+		if errors.Is(err, target) {
+			return false
+		}
+	}
+
+	// A dependency which is already known to be down, like another guarded call.
+	if errors.Is(err, errs.Unavailable) {
+		return true
+	}
+
+	// Broken or refused connections, and network timeouts.
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) {
+		return true
+	}
+
+	// The errors reported by the server itself only count if their class is about its health, rather than the statement.
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && len(pgErr.Code) == 5 {
+		switch pgErr.Code[:2] {
+		case "08", // Connection exception.
+			"53", // Insufficient resources.
+			"57", // Operator intervention, including the statement timeouts.
+			"58", // System error.
+			"XX": // Internal error.
+			return true
+		}
+	}
+	return false
+}
+
+// allow checks whether a call may go through, transitioning an open breaker to half-open once the cooldown has elapsed.
+func (b *Breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case Open:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+
+		// Let a single trial call through.
+		b.state = HalfOpen
+		return true
+	case HalfOpen:
+
+		// A trial call is already in flight.
+		return false
+	}
+	return true
+}
+
+// record records the outcome of a call.
+func (b *Breaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.isFailure(err) {
+		b.state = Closed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == HalfOpen || b.failures >= b.threshold {
+		b.state = Open
+		b.openedAt = b.now()
+	}
+}
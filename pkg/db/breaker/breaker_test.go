@@ -0,0 +1,185 @@
+package breaker
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mrinalwahal/boilerplate/pkg/errs"
+	"gorm.io/gorm"
+)
+
+func TestBreaker(t *testing.T) {
+
+	errConnection := &net.OpError{Op: "dial", Net: "tcp", Err: fmt.Errorf("connection refused")}
+
+	// Returns a breaker with a controllable clock.
+	setup := func() (*Breaker, *time.Time) {
+		now := time.Now()
+		b := New(&Config{
+			Threshold: 2,
+			Cooldown:  time.Minute,
+		})
+		b.now = func() time.Time { return now }
+		return b, &now
+	}
+
+	t.Run("breaker opens after consecutive failures", func(t *testing.T) {
+
+		b, _ := setup()
+
+		for i := 0; i < 2; i++ {
+			if err := b.Do(func() error { return errConnection }); err != errConnection {
+				t.Fatalf("Do() error = %v, want %v", err, errConnection)
+			}
+		}
+
+		if b.State() != Open {
+			t.Fatalf("State() = %v, want %v", b.State(), Open)
+		}
+
+		called := false
+		if err := b.Do(func() error { called = true; return nil }); err != ErrServiceUnavailable {
+			t.Fatalf("Do() error = %v, want %v", err, ErrServiceUnavailable)
+		}
+		if called {
+			t.Fatalf("expected the call to be rejected without being attempted")
+		}
+	})
+
+	t.Run("breaker ignores non-failure errors", func(t *testing.T) {
+
+		b, _ := setup()
+
+		for i := 0; i < 5; i++ {
+			b.Do(func() error { return gorm.ErrRecordNotFound })
+		}
+
+		if b.State() != Closed {
+			t.Fatalf("State() = %v, want %v", b.State(), Closed)
+		}
+	})
+
+	t.Run("breaker never opens on the errors of the requests", func(t *testing.T) {
+
+		b, _ := setup()
+
+		for _, err := range []error{
+			errs.Wrap(errs.Unprocessable, "invalid title"),
+			errs.Wrap(errs.InvalidArgument, "invalid filters"),
+			fmt.Errorf("delete record: %w", errs.Wrap(errs.NotFound, "no rows affected")),
+			&pgconn.PgError{Code: "23505"},
+			gorm.ErrDuplicatedKey,
+			context.DeadlineExceeded,
+			fmt.Errorf("some unclassified error"),
+		} {
+			for i := 0; i < 5; i++ {
+				b.Do(func() error { return err })
+			}
+			if b.State() != Closed {
+				t.Fatalf("State() = %v after %v, want %v", b.State(), err, Closed)
+			}
+		}
+	})
+
+	t.Run("breaker closes after a successful trial call", func(t *testing.T) {
+
+		b, now := setup()
+
+		for i := 0; i < 2; i++ {
+			b.Do(func() error { return errConnection })
+		}
+
+		// Let the cooldown elapse.
+		*now = now.Add(time.Minute)
+
+		if b.State() != HalfOpen {
+			t.Fatalf("State() = %v, want %v", b.State(), HalfOpen)
+		}
+
+		if err := b.Do(func() error { return nil }); err != nil {
+			t.Fatalf("Do() error = %v, want nil", err)
+		}
+
+		if b.State() != Closed {
+			t.Fatalf("State() = %v, want %v", b.State(), Closed)
+		}
+	})
+
+	t.Run("breaker re-opens after a failed trial call", func(t *testing.T) {
+
+		b, now := setup()
+
+		for i := 0; i < 2; i++ {
+			b.Do(func() error { return errConnection })
+		}
+
+		// Let the cooldown elapse.
+		*now = now.Add(time.Minute)
+
+		b.Do(func() error { return errConnection })
+
+		if b.State() != Open {
+			t.Fatalf("State() = %v, want %v", b.State(), Open)
+		}
+	})
+}
+
+func TestIsInfrastructureFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "no error",
+			want: false,
+		},
+		{
+			name: "refused connection",
+			err:  fmt.Errorf("query: %w", &net.OpError{Op: "dial", Net: "tcp", Err: fmt.Errorf("connection refused")}),
+			want: true,
+		},
+		{
+			name: "bad connection",
+			err:  driver.ErrBadConn,
+			want: true,
+		},
+		{
+			name: "statement timeout",
+			err:  &pgconn.PgError{Code: "57014"},
+			want: true,
+		},
+		{
+			name: "too many connections",
+			err:  &pgconn.PgError{Code: "53300"},
+			want: true,
+		},
+		{
+			name: "unique violation",
+			err:  &pgconn.PgError{Code: "23505"},
+			want: false,
+		},
+		{
+			name: "canonical error",
+			err:  errs.Wrap(errs.PermissionDenied, "permission denied"),
+			want: false,
+		},
+		{
+			name: "deadline of the caller",
+			err:  fmt.Errorf("query: %w", context.DeadlineExceeded),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsInfrastructureFailure(tt.err); got != tt.want {
+				t.Errorf("IsInfrastructureFailure() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
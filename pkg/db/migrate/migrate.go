@@ -0,0 +1,134 @@
+// Package migrate applies ordered SQL migrations and tracks the applied versions in the `schema_migrations` table.
+//
+// It works with any database gorm supports, so the schema doesn't have to be managed with `AutoMigrate` alone.
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrDuplicateVersion = fmt.Errorf("duplicate migration version")
+var ErrInvalidVersion = fmt.Errorf("invalid migration version")
+
+// Migration is a versioned change of the schema.
+type Migration struct {
+
+	// Version identifies the migration. The migrations are applied in the lexical order of their versions.
+	//
+	// Example: "20240409234208"
+	Version string
+
+	// Up is the SQL which applies the migration.
+	Up string
+}
+
+// schemaMigration is a row of the `schema_migrations` table.
+type schemaMigration struct {
+	Version   string    `gorm:"primaryKey"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// TableName returns the name of the table which tracks the applied migrations.
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migrate applies the pending migrations in the order of their versions and returns the versions it applied.
+//
+// Each migration runs in its own transaction along with the row recording it, so a failed migration is neither
+// applied nor recorded, and the ones before it stay applied. The migrations already recorded are skipped,
+// which makes it safe to run on every start.
+func Migrate(ctx context.Context, conn *gorm.DB, up []Migration) ([]string, error) {
+	conn = conn.WithContext(ctx)
+
+	migrations := make([]Migration, len(up))
+	copy(migrations, up)
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	for i, migration := range migrations {
+		if migration.Version == "" {
+			return nil, ErrInvalidVersion
+		}
+		if i > 0 && migrations[i-1].Version == migration.Version {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateVersion, migration.Version)
+		}
+	}
+
+	if err := conn.AutoMigrate(&schemaMigration{}); err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	if err := conn.Model(&schemaMigration{}).Pluck("version", &versions).Error; err != nil {
+		return nil, err
+	}
+	done := make(map[string]bool, len(versions))
+	for _, version := range versions {
+		done[version] = true
+	}
+
+	applied := []string{}
+	for _, migration := range migrations {
+		if done[migration.Version] {
+			continue
+		}
+		err := conn.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(migration.Up).Error; err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{
+				Version:   migration.Version,
+				AppliedAt: time.Now().UTC(),
+			}).Error
+		})
+		if err != nil {
+			return applied, fmt.Errorf("apply migration %s: %w", migration.Version, err)
+		}
+		applied = append(applied, migration.Version)
+	}
+	return applied, nil
+}
+
+// Load reads the migrations from the `.sql` files in the root of the file system.
+//
+// The version is the part of the file name before the first underscore, for example `20240409234208` for
+// `20240409234208_init.sql`. The files annotated for goose only contribute their `-- +goose Up` section.
+func Load(fsys fs.FS) ([]Migration, error) {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []Migration
+	for _, name := range names {
+		version, _, _ := strings.Cut(strings.TrimSuffix(path.Base(name), ".sql"), "_")
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, Migration{
+			Version: version,
+			Up:      up(string(content)),
+		})
+	}
+	return migrations, nil
+}
+
+// up returns the `-- +goose Up` section of the migration, or all of it if it isn't annotated.
+func up(content string) string {
+	_, section, found := strings.Cut(content, "-- +goose Up")
+	if !found {
+		return content
+	}
+	section, _, _ = strings.Cut(section, "-- +goose Down")
+	return strings.TrimSpace(section)
+}
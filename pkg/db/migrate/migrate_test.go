@@ -0,0 +1,134 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// open opens an in-memory database connection with SQLite.
+func open(t *testing.T) *gorm.DB {
+	conn, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open the database connection: %v", err)
+	}
+	sqlDB, err := conn.DB()
+	if err != nil {
+		t.Fatalf("failed to get the database connection: %v", err)
+	}
+
+	// Every connection to an unshared in-memory database opens a new database.
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() {
+		sqlDB.Close()
+	})
+	return conn
+}
+
+func TestMigrate(t *testing.T) {
+
+	ctx := context.Background()
+	conn := open(t)
+
+	// The migrations are supplied out of order on purpose.
+	migrations := []Migration{
+		{Version: "2", Up: `ALTER TABLE notes ADD COLUMN body TEXT NOT NULL DEFAULT ''`},
+		{Version: "1", Up: `CREATE TABLE notes (id INTEGER PRIMARY KEY)`},
+	}
+
+	t.Run("apply pending migrations in order", func(t *testing.T) {
+
+		applied, err := Migrate(ctx, conn, migrations)
+		if err != nil {
+			t.Fatalf("Migrate() error = %v", err)
+		}
+
+		if !reflect.DeepEqual(applied, []string{"1", "2"}) {
+			t.Fatalf("expected migrations [1 2] to be applied, got %v", applied)
+		}
+
+		if !conn.Migrator().HasColumn("notes", "body") {
+			t.Fatalf("expected the migrations to change the schema")
+		}
+	})
+
+	t.Run("re-run w/o re-applying migrations", func(t *testing.T) {
+
+		// Re-applying the migrations would fail, since the table and the column already exist.
+		applied, err := Migrate(ctx, conn, migrations)
+		if err != nil {
+			t.Fatalf("Migrate() error = %v", err)
+		}
+
+		if len(applied) != 0 {
+			t.Fatalf("expected no migrations to be applied, got %v", applied)
+		}
+
+		var count int64
+		if err := conn.Table("schema_migrations").Count(&count).Error; err != nil {
+			t.Fatalf("failed to count the applied migrations: %v", err)
+		}
+		if count != 2 {
+			t.Fatalf("expected 2 recorded migrations, got %d", count)
+		}
+	})
+
+	t.Run("apply failing migration", func(t *testing.T) {
+
+		applied, err := Migrate(ctx, conn, append(migrations, Migration{
+			Version: "3",
+			Up:      `ALTER TABLE missing ADD COLUMN body TEXT`,
+		}))
+		if err == nil {
+			t.Fatalf("expected an error, got nil")
+		}
+
+		if len(applied) != 0 {
+			t.Fatalf("expected no migrations to be applied, got %v", applied)
+		}
+
+		var count int64
+		if err := conn.Table("schema_migrations").Where("version = ?", "3").Count(&count).Error; err != nil {
+			t.Fatalf("failed to count the applied migrations: %v", err)
+		}
+		if count != 0 {
+			t.Fatalf("expected the failed migration not to be recorded")
+		}
+	})
+
+	t.Run("apply migrations w/ duplicate versions", func(t *testing.T) {
+
+		_, err := Migrate(ctx, open(t), []Migration{
+			{Version: "1", Up: `SELECT 1`},
+			{Version: "1", Up: `SELECT 1`},
+		})
+		if !errors.Is(err, ErrDuplicateVersion) {
+			t.Fatalf("expected error %v, got %v", ErrDuplicateVersion, err)
+		}
+	})
+}
+
+func TestLoad(t *testing.T) {
+
+	migrations, err := Load(fstest.MapFS{
+		"20240409234208_init.sql":  {Data: []byte("-- +goose Up\nCREATE TABLE notes (id INTEGER);\n\n-- +goose Down\nDROP TABLE notes;\n")},
+		"20240410000000_plain.sql": {Data: []byte("CREATE INDEX idx_notes_id ON notes (id);")},
+		"README.md":                {Data: []byte("# Migrations")},
+	})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := []Migration{
+		{Version: "20240409234208", Up: "CREATE TABLE notes (id INTEGER);"},
+		{Version: "20240410000000", Up: "CREATE INDEX idx_notes_id ON notes (id);"},
+	}
+	if !reflect.DeepEqual(migrations, want) {
+		t.Fatalf("expected migrations %v, got %v", want, migrations)
+	}
+}
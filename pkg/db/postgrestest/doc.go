@@ -0,0 +1,8 @@
+// Package postgrestest opens PostgreSQL databases for the integration tests,
+// so the features SQLite lacks, like `jsonb` and the serializable isolation level, are actually exercised.
+//
+// It's only built with the `integration` build tag, which also selects the integration tests.
+// They need a Docker daemon, unless the `POSTGRES_DSN` environment variable points them to a running server.
+//
+// Example: `go test -tags integration ./records/db/...`
+package postgrestest
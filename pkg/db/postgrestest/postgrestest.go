@@ -0,0 +1,84 @@
+//go:build integration
+
+package postgrestest
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+	gormpostgres "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// Image is the PostgreSQL image the containers are started from.
+const Image = "postgres:16-alpine"
+
+// Open opens a connection to a PostgreSQL database, which is closed when the test completes.
+//
+// It connects to the server of the `POSTGRES_DSN` environment variable if it's set,
+// and otherwise starts a throwaway container, terminated along with the connection.
+func Open(t testing.TB) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("POSTGRES_DSN")
+	if dsn == "" {
+		dsn = start(t)
+	}
+
+	conn, err := gorm.Open(gormpostgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open the database connection: %v", err)
+	}
+
+	// Cleanup the environment after the test is complete.
+	t.Cleanup(func() {
+		sqlDB, err := conn.DB()
+		if err != nil {
+			t.Errorf("failed to get the database connection: %v", err)
+			return
+		}
+		if err := sqlDB.Close(); err != nil {
+			t.Errorf("failed to close the database connection: %v", err)
+		}
+	})
+
+	return conn
+}
+
+// start starts a PostgreSQL container and returns its connection string.
+func start(t testing.TB) string {
+	t.Helper()
+
+	ctx := context.Background()
+	container, err := postgres.Run(ctx, Image,
+		postgres.WithDatabase("boilerplate"),
+		postgres.WithUsername("postgres"),
+		postgres.WithPassword("postgres"),
+
+		// The server restarts once after the initialization, so it's only ready on the second log.
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(time.Minute),
+		),
+	)
+	if err != nil {
+		t.Fatalf("failed to start the postgres container: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := container.Terminate(context.Background()); err != nil {
+			t.Errorf("failed to terminate the postgres container: %v", err)
+		}
+	})
+
+	dsn, err := container.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		t.Fatalf("failed to get the connection string: %v", err)
+	}
+	return dsn
+}
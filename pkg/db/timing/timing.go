@@ -0,0 +1,75 @@
+// Package timing instruments gorm to report the time spent in the database in the `Server-Timing` header.
+package timing
+
+import (
+	"time"
+
+	"github.com/mrinalwahal/boilerplate/pkg/middleware"
+	"gorm.io/gorm"
+)
+
+// Metric is the name of the `Server-Timing` metric the time spent in the database is added to.
+const Metric = "db"
+
+// startKey is the key of the start of the statement in the gorm instance.
+const startKey = "timing:start"
+
+// Plugin adds the duration of every statement to the `db` metric of the request it runs for.
+//
+// The statements must be run with the request context, for example through `db.WithContext(ctx)`.
+//
+// Example:
+//
+//	conn.Use(timing.Plugin{})
+type Plugin struct{}
+
+// Name returns the name of the plugin.
+//
+// This method is required to implement the `gorm.Plugin` interface.
+func (Plugin) Name() string {
+	return "timing"
+}
+
+// Initialize registers the callbacks which time the statements.
+//
+// This method is required to implement the `gorm.Plugin` interface.
+func (Plugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	for _, err := range []error{
+		callbacks.Create().Before("gorm:create").Register("timing:before_create", before),
+		callbacks.Create().After("gorm:create").Register("timing:after_create", after),
+		callbacks.Query().Before("gorm:query").Register("timing:before_query", before),
+		callbacks.Query().After("gorm:query").Register("timing:after_query", after),
+		callbacks.Update().Before("gorm:update").Register("timing:before_update", before),
+		callbacks.Update().After("gorm:update").Register("timing:after_update", after),
+		callbacks.Delete().Before("gorm:delete").Register("timing:before_delete", before),
+		callbacks.Delete().After("gorm:delete").Register("timing:after_delete", after),
+		callbacks.Row().Before("gorm:row").Register("timing:before_row", before),
+		callbacks.Row().After("gorm:row").Register("timing:after_row", after),
+		callbacks.Raw().Before("gorm:raw").Register("timing:before_raw", before),
+		callbacks.Raw().After("gorm:raw").Register("timing:after_raw", after),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// before records the start of the statement.
+func before(db *gorm.DB) {
+	db.InstanceSet(startKey, time.Now())
+}
+
+// after adds the duration of the statement to the request.
+func after(db *gorm.DB) {
+	value, exists := db.InstanceGet(startKey)
+	if !exists {
+		return
+	}
+	start, ok := value.(time.Time)
+	if !ok || db.Statement.Context == nil {
+		return
+	}
+	middleware.AddServerTiming(db.Statement.Context, Metric, time.Since(start))
+}
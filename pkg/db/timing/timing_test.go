@@ -0,0 +1,43 @@
+package timing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/mrinalwahal/boilerplate/pkg/middleware"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestPlugin(t *testing.T) {
+
+	// Open an in-memory database connection with SQLite.
+	conn, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open the database connection: %v", err)
+	}
+	if err := conn.Use(Plugin{}); err != nil {
+		t.Fatalf("failed to register the plugin: %v", err)
+	}
+
+	// Initialize test request and response recorder.
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+
+	// Serve the request with a handler which queries the database.
+	middleware.ServerTiming(&middleware.ServerTimingConfig{
+		Expose: true,
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var one int
+		if err := conn.WithContext(r.Context()).Raw("SELECT 1").Scan(&one).Error; err != nil {
+			t.Errorf("failed to query the database: %v", err)
+		}
+	})).ServeHTTP(w, r)
+
+	header := w.Header().Get(string(middleware.ServerTimingHeader))
+	if !regexp.MustCompile(`^db;dur=\d+\.\d{2}, total;dur=\d+\.\d{2}$`).MatchString(header) {
+		t.Errorf("unexpected %s header %q", middleware.ServerTimingHeader, header)
+	}
+}
@@ -0,0 +1,142 @@
+// Package unbounded instruments gorm to catch the reads which list the rows of a table without a `LIMIT`,
+// so the accidental full-table scans introduced by new code surface before they reach a large table.
+package unbounded
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"reflect"
+	"slices"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/utils"
+)
+
+// ErrUnbounded is returned by the unbounded reads in the strict mode.
+var ErrUnbounded = errors.New("unbounded query: the rows must be listed with a limit")
+
+// allowKey is the key of the setting which exempts a statement from the check.
+const allowKey = "unbounded:allow"
+
+// Plugin reports the statements which list the rows of the watched tables without a `LIMIT`.
+//
+// The reads of a single row, the counts and the aggregates grouped with `GROUP BY` aren't reported,
+// nor are the raw SQL statements.
+//
+// Example:
+//
+//	conn.Use(unbounded.Plugin{Tables: []string{"records"}, Strict: true})
+type Plugin struct {
+
+	// Tables are the tables whose unbounded reads are reported.
+	// Default: every table
+	//
+	// This field is optional.
+	Tables []string
+
+	// Strict fails the unbounded reads with `ErrUnbounded` instead of only logging a warning.
+	// Default: `false`
+	//
+	// This field is optional.
+	Strict bool
+
+	// Logger is used to report the unbounded reads.
+	// Default: `slog.Default()`
+	//
+	// This field is optional.
+	Logger *slog.Logger
+}
+
+// Name returns the name of the plugin.
+//
+// This method is required to implement the `gorm.Plugin` interface.
+func (Plugin) Name() string {
+	return "unbounded"
+}
+
+// Initialize registers the callbacks which check the reads.
+//
+// This method is required to implement the `gorm.Plugin` interface.
+func (p Plugin) Initialize(db *gorm.DB) error {
+	if p.Logger == nil {
+		p.Logger = slog.Default()
+	}
+
+	callbacks := db.Callback()
+	for _, err := range []error{
+		callbacks.Query().Before("gorm:query").Register("unbounded:query", p.check),
+		callbacks.Row().Before("gorm:row").Register("unbounded:row", p.check),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Allow exempts the statements of the returned instance from the check,
+// for the reads which list all the rows on purpose, like the exports streaming them.
+func Allow(db *gorm.DB) *gorm.DB {
+	return db.Set(allowKey, true)
+}
+
+// check reports the statement if it lists the rows of a watched table without a `LIMIT`.
+func (p Plugin) check(db *gorm.DB) {
+	if db.Error != nil || !p.unbounded(db) {
+		return
+	}
+
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	p.Logger.LogAttrs(ctx, slog.LevelWarn, "unbounded query",
+		slog.String("table", db.Statement.Table),
+		slog.String("caller", utils.FileWithLineNum()),
+		slog.Bool("strict", p.Strict),
+	)
+
+	if p.Strict {
+		db.AddError(ErrUnbounded)
+	}
+}
+
+// unbounded checks whether the statement lists the rows of a watched table without a `LIMIT`.
+func (p Plugin) unbounded(db *gorm.DB) bool {
+	stmt := db.Statement
+	if stmt.SQL.Len() > 0 {
+		return false
+	}
+	if allowed, ok := db.Get(allowKey); ok && allowed.(bool) {
+		return false
+	}
+	if len(p.Tables) > 0 && !slices.Contains(p.Tables, stmt.Table) {
+		return false
+	}
+
+	// The `Rows` reads stream a list and the `Row` ones read a single row,
+	// while the `Find` and `Scan` reads only list the rows into a slice.
+	if rows, ok := db.Get("rows"); ok {
+		if !rows.(bool) {
+			return false
+		}
+	} else if stmt.Dest != nil {
+		if kind := reflect.Indirect(reflect.ValueOf(stmt.Dest)).Kind(); kind != reflect.Slice && kind != reflect.Array {
+			return false
+		}
+	}
+
+	// The aggregates return a row per group.
+	if _, grouped := stmt.Clauses["GROUP BY"]; grouped {
+		return false
+	}
+
+	if c, exists := stmt.Clauses["LIMIT"]; exists {
+		if limit, ok := c.Expression.(clause.Limit); ok && limit.Limit != nil {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,153 @@
+package unbounded
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mrinalwahal/boilerplate/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// open opens an in-memory database with the plugin registered.
+func open(t *testing.T, plugin Plugin) *gorm.DB {
+
+	// Open an in-memory database connection with SQLite.
+	conn, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open the database connection: %v", err)
+	}
+
+	// Migrate the schema.
+	if err := conn.AutoMigrate(&model.Record{}, &model.AuditLog{}); err != nil {
+		t.Fatalf("failed to migrate the schema: %v", err)
+	}
+
+	if err := conn.Use(plugin); err != nil {
+		t.Fatalf("failed to register the plugin: %v", err)
+	}
+
+	// Seed the database with a sample record.
+	if err := conn.Create(&model.Record{Title: "Record 1", UserID: uuid.New()}).Error; err != nil {
+		t.Fatalf("failed to seed the database: %v", err)
+	}
+	return conn
+}
+
+func TestPlugin(t *testing.T) {
+
+	t.Run("strict", func(t *testing.T) {
+
+		conn := open(t, Plugin{
+			Tables: []string{"records"},
+			Strict: true,
+			Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		})
+
+		tests := []struct {
+			name    string
+			query   func() error
+			wantErr bool
+		}{
+			{
+				name: "reject find w/o limit",
+				query: func() error {
+					var records []model.Record
+					return conn.Find(&records).Error
+				},
+				wantErr: true,
+			},
+			{
+				name: "reject rows w/o limit",
+				query: func() error {
+					rows, err := conn.Model(&model.Record{}).Rows()
+					if err == nil {
+						rows.Close()
+					}
+					return err
+				},
+				wantErr: true,
+			},
+			{
+				name: "allow find w/ limit",
+				query: func() error {
+					var records []model.Record
+					return conn.Limit(10).Find(&records).Error
+				},
+			},
+			{
+				name: "allow first",
+				query: func() error {
+					var record model.Record
+					return conn.First(&record).Error
+				},
+			},
+			{
+				name: "allow count",
+				query: func() error {
+					var count int64
+					return conn.Model(&model.Record{}).Count(&count).Error
+				},
+			},
+			{
+				name: "allow aggregate",
+				query: func() error {
+					var groups []model.Group
+					return conn.Model(&model.Record{}).Select("title AS key, count(*) AS count").Group("title").Scan(&groups).Error
+				},
+			},
+			{
+				name: "allow rows w/o limit when exempted",
+				query: func() error {
+					rows, err := Allow(conn).Model(&model.Record{}).Rows()
+					if err == nil {
+						rows.Close()
+					}
+					return err
+				},
+			},
+			{
+				name: "allow find w/o limit on an unwatched table",
+				query: func() error {
+					var logs []model.AuditLog
+					return conn.Find(&logs).Error
+				},
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				err := tt.query()
+				if tt.wantErr != errors.Is(err, ErrUnbounded) {
+					t.Errorf("query error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if !tt.wantErr && err != nil {
+					t.Errorf("query error = %v", err)
+				}
+			})
+		}
+	})
+
+	t.Run("warn", func(t *testing.T) {
+
+		var buffer bytes.Buffer
+		conn := open(t, Plugin{
+			Logger: slog.New(slog.NewTextHandler(&buffer, nil)),
+		})
+
+		var records []model.Record
+		if err := conn.Find(&records).Error; err != nil {
+			t.Fatalf("query error = %v", err)
+		}
+		if len(records) != 1 {
+			t.Errorf("expected 1 record, got %d", len(records))
+		}
+		if !strings.Contains(buffer.String(), "unbounded query") || !strings.Contains(buffer.String(), "table=records") {
+			t.Errorf("expected the unbounded query in the log, got %q", buffer.String())
+		}
+	})
+}
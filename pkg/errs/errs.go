@@ -0,0 +1,58 @@
+package errs
+
+import (
+	"errors"
+)
+
+// The canonical errors classify the failures across the packages.
+// The per-package sentinels wrap one of them, so the transports can map any of them to a status code
+// by its class rather than by enumerating the sentinels of every layer.
+var (
+
+	// InvalidArgument is the class of the errors caused by malformed input.
+	InvalidArgument = errors.New("invalid argument")
+
+	// Unprocessable is the class of the errors caused by well-formed input which breaks a business rule.
+	Unprocessable = errors.New("unprocessable")
+
+	// NotFound is the class of the errors caused by a missing resource.
+	NotFound = errors.New("not found")
+
+	// AlreadyExists is the class of the errors caused by a resource which conflicts with an existing one.
+	AlreadyExists = errors.New("already exists")
+
+	// PermissionDenied is the class of the errors caused by a requester who isn't allowed to perform the operation.
+	PermissionDenied = errors.New("permission denied")
+
+	// Unavailable is the class of the errors caused by a dependency which is temporarily down.
+	Unavailable = errors.New("unavailable")
+)
+
+// Wrap returns a new error with the message which matches the canonical error with `errors.Is`.
+// The message of the canonical error isn't part of the message of the returned error.
+func Wrap(canonical error, message string) error {
+	return &wrapped{
+		canonical: canonical,
+		message:   message,
+	}
+}
+
+// Is reports whether any error in the tree of the error matches the target.
+// It's a shorthand for `errors.Is`, so the callers don't have to import both packages.
+func Is(err, target error) bool {
+	return errors.Is(err, target)
+}
+
+// wrapped is an error with its own message which unwraps to a canonical error.
+type wrapped struct {
+	canonical error
+	message   string
+}
+
+func (e *wrapped) Error() string {
+	return e.message
+}
+
+func (e *wrapped) Unwrap() error {
+	return e.canonical
+}
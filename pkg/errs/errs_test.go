@@ -0,0 +1,83 @@
+package errs_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/mrinalwahal/boilerplate/pkg/db/breaker"
+	"github.com/mrinalwahal/boilerplate/pkg/errs"
+	"github.com/mrinalwahal/boilerplate/records/db"
+	"github.com/mrinalwahal/boilerplate/records/service"
+)
+
+func TestWrap(t *testing.T) {
+
+	err := errs.Wrap(errs.NotFound, "record not found")
+
+	if err.Error() != "record not found" {
+		t.Errorf("Error() = %q, want %q", err.Error(), "record not found")
+	}
+	if !errs.Is(err, errs.NotFound) {
+		t.Errorf("Is() = false, want true")
+	}
+	if errs.Is(err, errs.InvalidArgument) {
+		t.Errorf("Is() = true for another canonical error, want false")
+	}
+
+	// Distinct sentinels of the same class don't match each other.
+	if errors.Is(err, errs.Wrap(errs.NotFound, "record not found")) {
+		t.Errorf("errors.Is() = true for a distinct sentinel, want false")
+	}
+}
+
+func TestIs_CrossPackage(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		target error
+	}{
+		{
+			name:   "db invalid options",
+			err:    db.ErrInvalidOptions,
+			target: errs.InvalidArgument,
+		},
+		{
+			name:   "service invalid options",
+			err:    service.ErrInvalidOptions,
+			target: errs.InvalidArgument,
+		},
+		{
+			name:   "db invalid title",
+			err:    db.ErrInvalidTitle,
+			target: errs.Unprocessable,
+		},
+		{
+			name:   "service no fields to update",
+			err:    service.ErrNoFieldsToUpdate,
+			target: errs.Unprocessable,
+		},
+		{
+			name:   "db no rows affected wrapped with context",
+			err:    fmt.Errorf("delete record: %w", db.ErrNoRowsAffected),
+			target: errs.NotFound,
+		},
+		{
+			name:   "service quota exceeded",
+			err:    service.ErrQuotaExceeded,
+			target: errs.PermissionDenied,
+		},
+		{
+			name:   "breaker service unavailable",
+			err:    breaker.ErrServiceUnavailable,
+			target: errs.Unavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !errs.Is(tt.err, tt.target) {
+				t.Errorf("Is(%v, %v) = false, want true", tt.err, tt.target)
+			}
+		})
+	}
+}
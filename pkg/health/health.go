@@ -0,0 +1,144 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// The statuses of the dependencies and of the service as a whole.
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+)
+
+// Checker checks the health of a dependency, like the database, a cache or a queue.
+type Checker interface {
+	Check(context.Context) error
+}
+
+// CheckerFunc is an adapter to use an ordinary function as a `Checker`.
+type CheckerFunc func(context.Context) error
+
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Pinger is implemented by the connections which can be pinged, like `*sql.DB`.
+type Pinger interface {
+	PingContext(context.Context) error
+}
+
+// Ping returns the checker which pings the connection.
+func Ping(conn Pinger) Checker {
+	return CheckerFunc(conn.PingContext)
+}
+
+// Check is the result of the check of a dependency.
+type Check struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Report is the health of the service and of each of its dependencies.
+type Report struct {
+	Status string           `json:"status"`
+	Checks map[string]Check `json:"checks"`
+}
+
+type Config struct {
+
+	// Checkers are the checkers of the dependencies, by the name of the dependency.
+	// Default: `nil`
+	//
+	// This field is optional.
+	Checkers map[string]Checker
+
+	// Timeout is the time each checker has to report, after which its dependency is considered unavailable.
+	// Default: `2s`
+	//
+	// This field is optional.
+	Timeout time.Duration
+}
+
+// Handler returns the handler which runs all the checkers concurrently and responds with the report.
+//
+// The service is available only if all its dependencies are, in which case it responds with `200 OK`.
+// Otherwise, it responds with `503 Service Unavailable`, so the load balancers stop routing to the instance.
+func Handler(config *Config) http.Handler {
+
+	// Set the default configuration.
+	if config == nil {
+		config = &Config{}
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 2 * time.Second
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := Run(r.Context(), config.Checkers, config.Timeout)
+
+		status := http.StatusOK
+		if report.Status != StatusOK {
+			status = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(report)
+	})
+}
+
+// Run runs the checkers concurrently, each one with its own timeout, and reports their results.
+func Run(ctx context.Context, checkers map[string]Checker, timeout time.Duration) *Report {
+	report := Report{
+		Status: StatusOK,
+		Checks: make(map[string]Check, len(checkers)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, checker := range checkers {
+		wg.Add(1)
+		go func(name string, checker Checker) {
+			defer wg.Done()
+			check := Check{Status: StatusOK}
+			if err := run(ctx, checker, timeout); err != nil {
+				check = Check{
+					Status: StatusUnavailable,
+					Error:  err.Error(),
+				}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = check
+			if check.Status != StatusOK {
+				report.Status = StatusUnavailable
+			}
+		}(name, checker)
+	}
+	wg.Wait()
+	return &report
+}
+
+// run runs the checker, giving up once the timeout expires even if the checker ignores its context.
+func run(ctx context.Context, checker Checker, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Buffered, so the checker can exit even once nobody waits for it anymore.
+	result := make(chan error, 1)
+	go func() {
+		result <- checker.Check(ctx)
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,116 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandler(t *testing.T) {
+
+	// Checkers of the dependencies.
+	healthy := CheckerFunc(func(ctx context.Context) error {
+		return nil
+	})
+	failing := CheckerFunc(func(ctx context.Context) error {
+		return errors.New("connection refused")
+	})
+	hanging := CheckerFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	tests := []struct {
+		name       string
+		checkers   map[string]Checker
+		wantStatus int
+		wantReport Report
+	}{
+		{
+			name:       "report available w/o checkers",
+			wantStatus: http.StatusOK,
+			wantReport: Report{
+				Status: StatusOK,
+				Checks: map[string]Check{},
+			},
+		},
+		{
+			name: "report available w/ healthy dependencies",
+			checkers: map[string]Checker{
+				"db":    healthy,
+				"cache": healthy,
+			},
+			wantStatus: http.StatusOK,
+			wantReport: Report{
+				Status: StatusOK,
+				Checks: map[string]Check{
+					"db":    {Status: StatusOK},
+					"cache": {Status: StatusOK},
+				},
+			},
+		},
+		{
+			name: "report unavailable w/ failing dependency",
+			checkers: map[string]Checker{
+				"db":    healthy,
+				"queue": failing,
+			},
+			wantStatus: http.StatusServiceUnavailable,
+			wantReport: Report{
+				Status: StatusUnavailable,
+				Checks: map[string]Check{
+					"db":    {Status: StatusOK},
+					"queue": {Status: StatusUnavailable, Error: "connection refused"},
+				},
+			},
+		},
+		{
+			name: "report unavailable w/ timed out dependency",
+			checkers: map[string]Checker{
+				"cache": hanging,
+			},
+			wantStatus: http.StatusServiceUnavailable,
+			wantReport: Report{
+				Status: StatusUnavailable,
+				Checks: map[string]Check{
+					"cache": {Status: StatusUnavailable, Error: context.DeadlineExceeded.Error()},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Initialize test request and response recorder.
+			r := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+			w := httptest.NewRecorder()
+
+			// Serve the request.
+			Handler(&Config{
+				Checkers: tt.checkers,
+				Timeout:  10 * time.Millisecond,
+			}).ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("ServeHTTP() = %v, want %v", w.Code, tt.wantStatus)
+			}
+
+			var report Report
+			if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+				t.Fatalf("failed to decode the report: %v", err)
+			}
+			if report.Status != tt.wantReport.Status || len(report.Checks) != len(tt.wantReport.Checks) {
+				t.Fatalf("expected report %+v, got %+v", tt.wantReport, report)
+			}
+			for name, check := range tt.wantReport.Checks {
+				if report.Checks[name] != check {
+					t.Errorf("expected check %q to be %+v, got %+v", name, check, report.Checks[name])
+				}
+			}
+		})
+	}
+}
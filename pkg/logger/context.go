@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/mrinalwahal/boilerplate/pkg/middleware"
+)
+
+// ContextHandler is the `slog.Handler` which adds the IDs of the request in the context to the log records.
+//
+// The IDs are written to the context by the `RequestID`, `TraceID` and `CorrelationID` middlewares,
+// so the records logged by the different layers while serving the same request share them,
+// as long as they are logged with the request context. For example, with `slog.Logger.LogAttrs(ctx, ...)`.
+type ContextHandler struct {
+	slog.Handler
+}
+
+// NewContextHandler wraps the supplied handler in a `ContextHandler`.
+func NewContextHandler(handler slog.Handler) *ContextHandler {
+	return &ContextHandler{Handler: handler}
+}
+
+// Handle adds the `request_id`, `trace_id` and `correlation_id` attributes found in the context to the record.
+func (h *ContextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id, exists := middleware.RequestIDFromContext(ctx); exists {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+	if id, exists := middleware.TraceIDFromContext(ctx); exists {
+		record.AddAttrs(slog.String("trace_id", id))
+	}
+	if id, exists := middleware.CorrelationIDFromContext(ctx); exists {
+		record.AddAttrs(slog.String("correlation_id", id))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return NewContextHandler(h.Handler.WithAttrs(attrs))
+}
+
+func (h *ContextHandler) WithGroup(name string) slog.Handler {
+	return NewContextHandler(h.Handler.WithGroup(name))
+}
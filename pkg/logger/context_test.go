@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mrinalwahal/boilerplate/pkg/middleware"
+)
+
+func TestContextHandler(t *testing.T) {
+
+	t.Run("log w/ the ids of the request", func(t *testing.T) {
+
+		var buffer bytes.Buffer
+		logger := slog.New(NewContextHandler(slog.NewTextHandler(&buffer, nil))).With("layer", "service")
+
+		// Initialize test request and response recorder.
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		w := httptest.NewRecorder()
+
+		// Serve the request.
+		middleware.Chain(
+			middleware.RequestID(nil),
+			middleware.CorrelationID(nil),
+		)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			logger.InfoContext(r.Context(), "handling request")
+		})).ServeHTTP(w, r)
+
+		for _, header := range []middleware.Key{middleware.XRequestID, middleware.XCorrelationID} {
+			id := w.Header().Get(string(header))
+			if id == "" || !strings.Contains(buffer.String(), id) {
+				t.Errorf("expected the %s %q in the log, got %q", header, id, buffer.String())
+			}
+		}
+		if !strings.Contains(buffer.String(), "layer=service") {
+			t.Errorf("expected the logger attributes to be kept, got %q", buffer.String())
+		}
+	})
+
+	t.Run("log w/o a request", func(t *testing.T) {
+
+		var buffer bytes.Buffer
+		slog.New(NewContextHandler(slog.NewTextHandler(&buffer, nil))).Info("standalone")
+
+		if strings.Contains(buffer.String(), "request_id") {
+			t.Errorf("expected no request id in the log, got %q", buffer.String())
+		}
+	})
+}
@@ -0,0 +1,135 @@
+// Package logger builds the `log/slog` logger of the service from its configuration.
+package logger
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+)
+
+var ErrUnsupportedEngine = fmt.Errorf("unsupported logs engine")
+var ErrUnsupportedFormat = fmt.Errorf("unsupported logs format")
+var ErrAddressRequired = fmt.Errorf("logs address is required")
+
+// Engines which the logs can be written to.
+const (
+	EngineStdout = "stdout"
+	EngineStderr = "stderr"
+	EngineFile   = "file"
+)
+
+// Formats which the logs can be written in.
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+type Config struct {
+
+	// Engine is the destination of the logs: `stdout`, `stderr` or `file`.
+	// Default: `stdout`
+	//
+	// This field is optional.
+	Engine string
+
+	// Address is the location of the logs for the engines which need one.
+	// For the `file` engine, it's the path of the file the logs are appended to.
+	//
+	// This field is mandatory for the `file` engine.
+	Address string
+
+	// Format of the log records: `json` or `text`.
+	// Default: `json`
+	//
+	// This field is optional.
+	Format string
+
+	// Level is the minimum level of the logged records.
+	// Pass a `*slog.LevelVar` to change it while the logger is in use.
+	// Default: `slog.LevelInfo`
+	//
+	// This field is optional.
+	Level slog.Leveler
+
+	// AddSource adds the source code position of the log statement to the records.
+	// Default: `false`
+	//
+	// This field is optional.
+	AddSource bool
+
+	// Writer overrides the destination chosen by the engine.
+	//
+	// It's the hook for the engines which ship the logs over the network, such as a future `http` engine
+	// posting the records to Loki: implement the shipping as an `io.Writer` and set it here.
+	// Default: nil
+	//
+	// This field is optional.
+	Writer io.Writer
+}
+
+// New creates the logger described by the configuration.
+//
+// The returned closer releases the destination of the logs, for example the file of the `file` engine,
+// and must be called once the logger is no longer used.
+func New(config *Config) (*slog.Logger, io.Closer, error) {
+
+	// Set the default configuration.
+	if config == nil {
+		config = &Config{}
+	}
+
+	if config.Engine == "" {
+		config.Engine = EngineStdout
+	}
+
+	if config.Format == "" {
+		config.Format = FormatJSON
+	}
+
+	// Resolve the destination of the logs.
+	var closer io.Closer = nop{}
+	destination := config.Writer
+	if destination == nil {
+		switch config.Engine {
+		case EngineStdout:
+			destination = os.Stdout
+		case EngineStderr:
+			destination = os.Stderr
+		case EngineFile:
+			if config.Address == "" {
+				return nil, nil, ErrAddressRequired
+			}
+			file, err := os.OpenFile(config.Address, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+			if err != nil {
+				return nil, nil, err
+			}
+			destination, closer = file, file
+		default:
+			return nil, nil, fmt.Errorf("%w: %s", ErrUnsupportedEngine, config.Engine)
+		}
+	}
+
+	options := &slog.HandlerOptions{
+		AddSource: config.AddSource,
+		Level:     config.Level,
+	}
+
+	var handler slog.Handler
+	switch config.Format {
+	case FormatJSON:
+		handler = slog.NewJSONHandler(destination, options)
+	case FormatText:
+		handler = slog.NewTextHandler(destination, options)
+	default:
+		closer.Close()
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, config.Format)
+	}
+
+	return slog.New(handler), closer, nil
+}
+
+// nop is the closer of the destinations which aren't owned by the logger.
+type nop struct{}
+
+func (nop) Close() error { return nil }
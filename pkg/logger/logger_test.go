@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+
+	t.Run("log in json format", func(t *testing.T) {
+
+		var buffer bytes.Buffer
+		logger, closer, err := New(&Config{
+			Format: FormatJSON,
+			Writer: &buffer,
+		})
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		defer closer.Close()
+
+		logger.Info("hello", "key", "value")
+
+		var record map[string]any
+		if err := json.Unmarshal(buffer.Bytes(), &record); err != nil {
+			t.Fatalf("expected a JSON record, got %q", buffer.String())
+		}
+		if record["msg"] != "hello" || record["key"] != "value" {
+			t.Errorf("unexpected record: %v", record)
+		}
+	})
+
+	t.Run("log in text format", func(t *testing.T) {
+
+		var buffer bytes.Buffer
+		logger, closer, err := New(&Config{
+			Format: FormatText,
+			Writer: &buffer,
+		})
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		defer closer.Close()
+
+		logger.Info("hello", "key", "value")
+
+		if !strings.Contains(buffer.String(), "msg=hello key=value") {
+			t.Errorf("expected a text record, got %q", buffer.String())
+		}
+	})
+
+	t.Run("log to a file", func(t *testing.T) {
+
+		path := filepath.Join(t.TempDir(), "service.log")
+		logger, closer, err := New(&Config{
+			Engine:  EngineFile,
+			Address: path,
+			Format:  FormatText,
+		})
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+
+		logger.Info("hello")
+		if err := closer.Close(); err != nil {
+			t.Fatalf("failed to close the file: %v", err)
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read the file: %v", err)
+		}
+		if !strings.Contains(string(content), "msg=hello") {
+			t.Errorf("expected the record in the file, got %q", content)
+		}
+	})
+
+	t.Run("log to a file w/o address", func(t *testing.T) {
+
+		if _, _, err := New(&Config{Engine: EngineFile}); !errors.Is(err, ErrAddressRequired) {
+			t.Errorf("New() error = %v, want %v", err, ErrAddressRequired)
+		}
+	})
+
+	t.Run("unsupported engine", func(t *testing.T) {
+
+		if _, _, err := New(&Config{Engine: "http"}); !errors.Is(err, ErrUnsupportedEngine) {
+			t.Errorf("New() error = %v, want %v", err, ErrUnsupportedEngine)
+		}
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+
+		if _, _, err := New(&Config{Format: "xml"}); !errors.Is(err, ErrUnsupportedFormat) {
+			t.Errorf("New() error = %v, want %v", err, ErrUnsupportedFormat)
+		}
+	})
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// acceptable lists the media ranges which match the responses of the handlers.
+var acceptable = map[string]bool{
+	"*/*":                    true,
+	"application/*":          true,
+	"application/json":       true,
+	"application/x-protobuf": true,
+}
+
+// Accept middleware rejects the requests whose `Accept` header explicitly excludes JSON and protobuf,
+// with `406 Not Acceptable`, since they're the only representations the handlers produce.
+//
+// It's lenient: the requests without the header, or with a header which can't be parsed, are served as usual.
+func Accept(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if header := r.Header.Values("Accept"); len(header) > 0 && !accepts(strings.Join(header, ",")) {
+			http.Error(w, "only application/json and application/x-protobuf responses are available", http.StatusNotAcceptable)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// accepts checks whether the value of the `Accept` header allows a JSON or a protobuf response.
+func accepts(header string) bool {
+	parsed := false
+	for _, item := range strings.Split(header, ",") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		mediatype, params, err := mime.ParseMediaType(item)
+		if err != nil {
+			continue
+		}
+		parsed = true
+
+		// A zero quality value marks the media range as not acceptable.
+		if q, exists := params["q"]; exists {
+			if value, err := strconv.ParseFloat(q, 64); err == nil && value <= 0 {
+				continue
+			}
+		}
+		if acceptable[mediatype] {
+			return true
+		}
+	}
+	return !parsed
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAccept(t *testing.T) {
+
+	// Initialize a dummy handler.
+	handler := Accept(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name       string
+		accept     string
+		wantStatus int
+	}{
+		{
+			name:       "serve request w/o accept header",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "serve request accepting json",
+			accept:     "application/json",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "serve request accepting anything",
+			accept:     "*/*",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "serve request preferring xml but accepting json",
+			accept:     "application/xml, application/json;q=0.5",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "serve request accepting only protobuf",
+			accept:     "application/x-protobuf",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "serve request w/ malformed accept header",
+			accept:     "text/html;;;",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "reject request accepting only xml",
+			accept:     "application/xml",
+			wantStatus: http.StatusNotAcceptable,
+		},
+		{
+			name:       "reject request refusing json",
+			accept:     "application/json;q=0",
+			wantStatus: http.StatusNotAcceptable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Initialize test request and response recorder.
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			w := httptest.NewRecorder()
+
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+
+			// Serve the request.
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("ServeHTTP() = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
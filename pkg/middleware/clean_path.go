@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"net/http"
+	"path"
+	"strings"
+)
+
+type CleanPathConfig struct {
+
+	// Rewrite is the flag that determines if the request path should be rewritten internally
+	// instead of redirecting the client to the canonical path.
+	// Default: `false`
+	//
+	// This field is optional.
+	Rewrite bool
+}
+
+// CleanPath middleware normalizes the request path to a canonical form.
+//
+// It strips the trailing slashes, collapses repeated slashes and resolves the `.` and `..` elements.
+// For example, `/v1/` and `/v1//` are both normalized to `/v1`.
+//
+// By default, the client is redirected to the canonical path with `301 Moved Permanently` for GET and HEAD requests,
+// and with `308 Permanent Redirect` for all other methods so that the method and body are preserved.
+// If `Rewrite` is set, the request is served with the canonical path without redirecting the client.
+func CleanPath(config *CleanPathConfig) Middleware {
+
+	// Set the default configuration.
+	if config == nil {
+		config = &CleanPathConfig{}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			canonical := cleanPath(r.URL.Path)
+			if canonical == r.URL.Path {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if config.Rewrite {
+				r = r.Clone(r.Context())
+				r.URL.Path = canonical
+				r.URL.RawPath = ""
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			status := http.StatusPermanentRedirect
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				status = http.StatusMovedPermanently
+			}
+
+			location := canonical
+			if r.URL.RawQuery != "" {
+				location += "?" + r.URL.RawQuery
+			}
+			http.Redirect(w, r, location, status)
+		})
+	}
+}
+
+// cleanPath returns the canonical form of the supplied path.
+func cleanPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	return path.Clean(p)
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCleanPath(t *testing.T) {
+
+	// Initialize a dummy handler that echoes the path it received.
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(r.URL.Path))
+	})
+
+	t.Run("canonical path is served as is", func(t *testing.T) {
+
+		// Initialize test request and response recorder.
+		r := httptest.NewRequest(http.MethodGet, "/v1", nil)
+		w := httptest.NewRecorder()
+
+		// Serve the request.
+		CleanPath(nil)(handler).ServeHTTP(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("ServeHTTP() = %v, want %v", w.Code, http.StatusOK)
+		}
+	})
+
+	t.Run("redirect mode w/ GET request", func(t *testing.T) {
+
+		// Initialize test request and response recorder.
+		r := httptest.NewRequest(http.MethodGet, "/v1/?limit=1", nil)
+		w := httptest.NewRecorder()
+
+		// Serve the request.
+		CleanPath(nil)(handler).ServeHTTP(w, r)
+
+		if w.Code != http.StatusMovedPermanently {
+			t.Errorf("ServeHTTP() = %v, want %v", w.Code, http.StatusMovedPermanently)
+		}
+		if location := w.Header().Get("Location"); location != "/v1?limit=1" {
+			t.Errorf("Location = %v, want %v", location, "/v1?limit=1")
+		}
+	})
+
+	t.Run("redirect mode w/ POST request", func(t *testing.T) {
+
+		// Initialize test request and response recorder.
+		r := httptest.NewRequest(http.MethodPost, "/v1//", nil)
+		w := httptest.NewRecorder()
+
+		// Serve the request.
+		CleanPath(nil)(handler).ServeHTTP(w, r)
+
+		if w.Code != http.StatusPermanentRedirect {
+			t.Errorf("ServeHTTP() = %v, want %v", w.Code, http.StatusPermanentRedirect)
+		}
+		if location := w.Header().Get("Location"); location != "/v1" {
+			t.Errorf("Location = %v, want %v", location, "/v1")
+		}
+	})
+
+	t.Run("rewrite mode", func(t *testing.T) {
+
+		// Initialize test request and response recorder.
+		r := httptest.NewRequest(http.MethodPost, "/v1/", nil)
+		w := httptest.NewRecorder()
+
+		// Serve the request.
+		CleanPath(&CleanPathConfig{
+			Rewrite: true,
+		})(handler).ServeHTTP(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("ServeHTTP() = %v, want %v", w.Code, http.StatusOK)
+		}
+		if body := w.Body.String(); body != "/v1" {
+			t.Errorf("path = %v, want %v", body, "/v1")
+		}
+	})
+}
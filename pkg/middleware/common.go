@@ -4,6 +4,9 @@ import (
 	"net/http"
 )
 
+// Key is the name of an HTTP header read or written by the middlewares.
+//
+// Values stored in the request context use the unexported `ctxKey` type instead.
 type Key string
 
 type Middleware func(http.Handler) http.Handler
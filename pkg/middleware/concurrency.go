@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+)
+
+type ConcurrencyConfig struct {
+
+	// Limit is the maximum number of requests that can be processed concurrently.
+	//
+	// This field is mandatory.
+	Limit int
+
+	// Timeout is the maximum duration a request waits in the queue for a slot to free up.
+	// If it is zero, the requests exceeding the limit are rejected immediately.
+	// Default: `0`
+	//
+	// This field is optional.
+	Timeout time.Duration
+}
+
+// Concurrency middleware bounds the number of requests that are processed concurrently.
+//
+// The requests exceeding the limit are rejected with `503 Service Unavailable`,
+// either immediately or after waiting for the configured timeout.
+func Concurrency(config *ConcurrencyConfig) Middleware {
+
+	// Validate the configuration.
+	if config == nil || config.Limit <= 0 {
+		panic("middleware: concurrency: limit is required")
+	}
+
+	// Buffered channel acting as a semaphore.
+	semaphore := make(chan struct{}, config.Limit)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			if !acquire(r, semaphore, config.Timeout) {
+				w.Header().Set("Retry-After", "1")
+				http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+				return
+			}
+			defer func() { <-semaphore }()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// acquire acquires a slot in the semaphore, waiting for up to the supplied timeout.
+func acquire(r *http.Request, semaphore chan struct{}, timeout time.Duration) bool {
+	select {
+	case semaphore <- struct{}{}:
+		return true
+	default:
+	}
+
+	if timeout <= 0 {
+		return false
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case semaphore <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-r.Context().Done():
+		return false
+	}
+}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestConcurrency(t *testing.T) {
+
+	// Saturates the middleware with a single in-flight request and returns the function which completes it.
+	saturate := func(handler http.Handler) (release func()) {
+		entered := make(chan struct{})
+		done := make(chan struct{})
+		go func() {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			handler.ServeHTTP(httptest.NewRecorder(), r.WithContext(withEntered(r, entered)))
+			close(done)
+		}()
+		<-entered
+		return func() { <-done }
+	}
+
+	t.Run("reject request over the limit", func(t *testing.T) {
+
+		unblock := make(chan struct{})
+		handler := Concurrency(&ConcurrencyConfig{
+			Limit: 1,
+		})(blocking(unblock))
+
+		release := saturate(handler)
+
+		// Initialize test request and response recorder.
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		w := httptest.NewRecorder()
+
+		// Serve the request.
+		handler.ServeHTTP(w, r)
+
+		if w.Code != http.StatusServiceUnavailable {
+			t.Errorf("ServeHTTP() = %v, want %v", w.Code, http.StatusServiceUnavailable)
+		}
+
+		close(unblock)
+		release()
+	})
+
+	t.Run("queue request until a slot frees up", func(t *testing.T) {
+
+		unblock := make(chan struct{})
+		handler := Concurrency(&ConcurrencyConfig{
+			Limit:   1,
+			Timeout: time.Second,
+		})(blocking(unblock))
+
+		release := saturate(handler)
+
+		// Free up the slot shortly after the queued request arrives.
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			close(unblock)
+		}()
+
+		// Initialize test request and response recorder.
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		w := httptest.NewRecorder()
+
+		// Serve the request.
+		handler.ServeHTTP(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("ServeHTTP() = %v, want %v", w.Code, http.StatusOK)
+		}
+
+		release()
+	})
+}
+
+// enteredKey is the key used to store the channel signalling that a request reached the handler.
+type enteredKey struct{}
+
+// withEntered returns the request context carrying the supplied channel.
+func withEntered(r *http.Request, entered chan struct{}) context.Context {
+	return context.WithValue(r.Context(), enteredKey{}, entered)
+}
+
+// blocking returns a handler which blocks until the supplied channel is closed.
+func blocking(unblock chan struct{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if entered, exists := r.Context().Value(enteredKey{}).(chan struct{}); exists {
+			close(entered)
+		}
+		<-unblock
+		w.WriteHeader(http.StatusOK)
+	})
+}
@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+)
+
+// ctxKey is the type of the keys used to store values in the request context.
+//
+// It is unexported, so the keys can't collide with the ones set by other packages.
+type ctxKey int
+
+const (
+	jwtClaimsKey ctxKey = iota
+	requestIDKey
+	traceIDKey
+	correlationIDKey
+	routeKey
+	timingsKey
+	transactionKey
+	tenantKey
+)
+
+// WithJWTClaims returns a copy of the context which carries the supplied JWT claims.
+func WithJWTClaims(ctx context.Context, claims JWTClaims) context.Context {
+	return context.WithValue(ctx, jwtClaimsKey, claims)
+}
+
+// JWTClaimsFromContext returns the JWT claims stored in the context by the `JWT` middleware, if any.
+func JWTClaimsFromContext(ctx context.Context) (JWTClaims, bool) {
+	claims, exists := ctx.Value(jwtClaimsKey).(JWTClaims)
+	return claims, exists
+}
+
+// RequestIDFromContext returns the request ID stored in the context by the `RequestID` middleware, if any.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, exists := ctx.Value(requestIDKey).(string)
+	return id, exists
+}
+
+// TraceIDFromContext returns the trace ID stored in the context by the `TraceID` middleware, if any.
+func TraceIDFromContext(ctx context.Context) (string, bool) {
+	id, exists := ctx.Value(traceIDKey).(string)
+	return id, exists
+}
+
+// CorrelationIDFromContext returns the correlation ID stored in the context by the `CorrelationID` middleware, if any.
+func CorrelationIDFromContext(ctx context.Context) (string, bool) {
+	id, exists := ctx.Value(correlationIDKey).(string)
+	return id, exists
+}
+
+// route holds the pattern of the route which matched the request.
+//
+// It's stored in the context as a pointer, so the router deep in the chain can fill it in
+// for the middlewares which wrap the router, like `Logging`.
+type route struct {
+	pattern string
+}
+
+// withRoute returns a copy of the context which can carry the pattern of the matched route.
+func withRoute(ctx context.Context) context.Context {
+	if _, exists := ctx.Value(routeKey).(*route); exists {
+		return ctx
+	}
+	return context.WithValue(ctx, routeKey, &route{})
+}
+
+// SetRoutePattern records the pattern of the route which matched the request, for example `/v1/{id}`.
+//
+// The method and the host of the `http.ServeMux` patterns are stripped.
+// It's a no-op unless a middleware which reads the pattern, like `Logging`, wraps the router.
+func SetRoutePattern(ctx context.Context, pattern string) {
+	if r, exists := ctx.Value(routeKey).(*route); exists {
+		if _, path, found := strings.Cut(pattern, " "); found {
+			pattern = path
+		}
+		if i := strings.Index(pattern, "/"); i > 0 {
+			pattern = pattern[i:]
+		}
+		r.pattern = pattern
+	}
+}
+
+// RoutePatternFromContext returns the pattern of the route recorded by the router, if any.
+func RoutePatternFromContext(ctx context.Context) (string, bool) {
+	r, exists := ctx.Value(routeKey).(*route)
+	if !exists || r.pattern == "" {
+		return "", false
+	}
+	return r.pattern, true
+}
@@ -3,7 +3,9 @@ package middleware
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type CORSConfig struct {
@@ -32,6 +34,13 @@ type CORSConfig struct {
 	//
 	// This field is optional.
 	AllowCredentials bool
+
+	// MaxAge is how long the browsers can cache the results of a preflight request.
+	// It's sent in the `Access-Control-Max-Age` header of the preflight responses, in seconds.
+	// Default: `0` (the header is omitted and the browsers apply their own default)
+	//
+	// This field is optional.
+	MaxAge time.Duration
 }
 
 // CORS middleware adds the CORS headers to the response.
@@ -68,8 +77,35 @@ func CORS(config *CORSConfig) Middleware {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Add("Access-Control-Allow-Origin", strings.Join(config.AllowedOrigins, ","))
 			w.Header().Add("Access-Control-Allow-Credentials", fmt.Sprint(config.AllowCredentials))
-			w.Header().Add("Access-Control-Allow-Headers", strings.Join(config.AllowedHeaders, ","))
-			w.Header().Add("Access-Control-Allow-Methods", strings.Join(config.AllowedMethods, ","))
+
+			// Echo the method and the headers requested by a preflight, if they are allowed.
+			// The disallowed ones are omitted, so the browser fails the actual request.
+			if method := r.Header.Get("Access-Control-Request-Method"); r.Method == http.MethodOptions && method != "" {
+				w.Header().Add("Vary", "Access-Control-Request-Method")
+				w.Header().Add("Vary", "Access-Control-Request-Headers")
+
+				if allowed(config.AllowedMethods, method, false) {
+					w.Header().Add("Access-Control-Allow-Methods", method)
+				}
+
+				var headers []string
+				for _, header := range strings.Split(r.Header.Get("Access-Control-Request-Headers"), ",") {
+					header = strings.TrimSpace(header)
+					if header != "" && allowed(config.AllowedHeaders, header, true) {
+						headers = append(headers, header)
+					}
+				}
+				if len(headers) > 0 {
+					w.Header().Add("Access-Control-Allow-Headers", strings.Join(headers, ","))
+				}
+
+				if config.MaxAge > 0 {
+					w.Header().Add("Access-Control-Max-Age", strconv.Itoa(int(config.MaxAge.Seconds())))
+				}
+			} else {
+				w.Header().Add("Access-Control-Allow-Headers", strings.Join(config.AllowedHeaders, ","))
+				w.Header().Add("Access-Control-Allow-Methods", strings.Join(config.AllowedMethods, ","))
+			}
 
 			if r.Method == http.MethodOptions {
 				http.Error(w, http.StatusText(http.StatusNoContent), http.StatusNoContent)
@@ -79,3 +115,14 @@ func CORS(config *CORSConfig) Middleware {
 		})
 	}
 }
+
+// allowed checks whether the value is in the allowlist.
+// The methods are case-sensitive, while the header names aren't.
+func allowed(allowlist []string, value string, foldCase bool) bool {
+	for _, item := range allowlist {
+		if item == value || (foldCase && strings.EqualFold(item, value)) {
+			return true
+		}
+	}
+	return false
+}
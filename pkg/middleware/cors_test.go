@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCORS(t *testing.T) {
+
+	// Initialize the middleware.
+	cors := CORS(&CORSConfig{
+		AllowedMethods: []string{"GET", "POST"},
+		AllowedHeaders: []string{"Content-Type", "Authorization"},
+		MaxAge:         10 * time.Minute,
+	})
+
+	tests := []struct {
+		name string
+
+		// method and headers requested by the preflight.
+		method  string
+		headers string
+
+		// wantMethods and wantHeaders are the expected values of the `Access-Control-Allow-*` headers.
+		// An empty value means the header must be omitted.
+		wantMethods string
+		wantHeaders string
+	}{
+		{
+			name:        "allowed preflight",
+			method:      "POST",
+			headers:     "content-type, Authorization",
+			wantMethods: "POST",
+			wantHeaders: "content-type,Authorization",
+		},
+		{
+			name:        "preflight w/ partially disallowed headers",
+			method:      "GET",
+			headers:     "Authorization, X-Forbidden",
+			wantMethods: "GET",
+			wantHeaders: "Authorization",
+		},
+		{
+			name:        "preflight w/ disallowed method",
+			method:      "DELETE",
+			headers:     "X-Forbidden",
+			wantMethods: "",
+			wantHeaders: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Initialize test request and response recorder.
+			r := httptest.NewRequest(http.MethodOptions, "/v1", nil)
+			r.Header.Set("Origin", "https://example.com")
+			r.Header.Set("Access-Control-Request-Method", tt.method)
+			r.Header.Set("Access-Control-Request-Headers", tt.headers)
+			w := httptest.NewRecorder()
+
+			// Serve the request.
+			cors(http.NotFoundHandler()).ServeHTTP(w, r)
+
+			if w.Code != http.StatusNoContent {
+				t.Fatalf("ServeHTTP() = %v, want %v", w.Code, http.StatusNoContent)
+			}
+
+			if got := w.Header().Get("Access-Control-Allow-Methods"); got != tt.wantMethods {
+				t.Errorf("Access-Control-Allow-Methods = %q, want %q", got, tt.wantMethods)
+			}
+
+			if got := w.Header().Get("Access-Control-Allow-Headers"); got != tt.wantHeaders {
+				t.Errorf("Access-Control-Allow-Headers = %q, want %q", got, tt.wantHeaders)
+			}
+
+			if got := w.Header().Get("Access-Control-Max-Age"); got != "600" {
+				t.Errorf("Access-Control-Max-Age = %q, want %q", got, "600")
+			}
+		})
+	}
+
+	t.Run("actual request", func(t *testing.T) {
+
+		r := httptest.NewRequest(http.MethodGet, "/v1", nil)
+		w := httptest.NewRecorder()
+
+		cors(http.NotFoundHandler()).ServeHTTP(w, r)
+
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("ServeHTTP() = %v, want %v", w.Code, http.StatusNotFound)
+		}
+
+		if got := w.Header().Get("Access-Control-Max-Age"); got != "" {
+			t.Errorf("expected no Access-Control-Max-Age header, got %q", got)
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+)
+
+type DecompressConfig struct {
+
+	// MaxSize is the maximum size of a decompressed request body, in bytes.
+	// Default: `1048576`
+	//
+	// This field is optional.
+	MaxSize int64
+}
+
+// gzipBody is the decompressed request body, which closes both the gzip reader and the original body.
+type gzipBody struct {
+	*gzip.Reader
+
+	//	Original, compressed body of the request.
+	body io.ReadCloser
+}
+
+func (b *gzipBody) Close() error {
+	b.Reader.Close()
+	return b.body.Close()
+}
+
+// Decompress middleware transparently decompresses the request bodies sent with `Content-Encoding: gzip`,
+// so the handlers read them as if they were sent uncompressed.
+//
+// The decompressed body is capped at the configured size, so a small compressed body can't expand
+// into an arbitrarily large one. Reading past the cap fails with an `*http.MaxBytesError`.
+// The bodies which aren't valid gzip streams are rejected with `400 Bad Request`,
+// and the ones in any other encoding with `415 Unsupported Media Type`.
+func Decompress(config *DecompressConfig) Middleware {
+
+	// Set the default configuration.
+	if config == nil {
+		config = &DecompressConfig{}
+	}
+
+	if config.MaxSize <= 0 {
+		config.MaxSize = 1 << 20
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding"))) {
+			case "", "identity":
+				next.ServeHTTP(w, r)
+				return
+			case "gzip", "x-gzip":
+			default:
+				http.Error(w, "unsupported content encoding", http.StatusUnsupportedMediaType)
+				return
+			}
+
+			reader, err := gzip.NewReader(r.Body)
+			if err != nil {
+				http.Error(w, "invalid gzip body", http.StatusBadRequest)
+				return
+			}
+
+			// The handlers see the decompressed body, whose length isn't known upfront.
+			r.Body = http.MaxBytesReader(w, &gzipBody{Reader: reader, body: r.Body}, config.MaxSize)
+			r.Header.Del("Content-Encoding")
+			r.Header.Del("Content-Length")
+			r.ContentLength = -1
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDecompress(t *testing.T) {
+
+	// compress returns the body compressed with gzip.
+	compress := func(body []byte) []byte {
+		var buffer bytes.Buffer
+		writer := gzip.NewWriter(&buffer)
+		writer.Write(body)
+		writer.Close()
+		return buffer.Bytes()
+	}
+
+	tests := []struct {
+		name     string
+		body     []byte
+		encoding string
+
+		// wantStatus is the status code we expect in response.
+		wantStatus int
+
+		// wantBody is the body we expect the handler to read.
+		wantBody string
+
+		// wantTooLarge is whether we expect the handler to fail reading the body past the cap.
+		wantTooLarge bool
+	}{
+		{
+			name:       "uncompressed body",
+			body:       []byte(`{"title": "Test Record"}`),
+			wantStatus: http.StatusOK,
+			wantBody:   `{"title": "Test Record"}`,
+		},
+		{
+			name:       "gzipped body",
+			body:       compress([]byte(`{"title": "Test Record"}`)),
+			encoding:   "gzip",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"title": "Test Record"}`,
+		},
+		{
+			name:         "gzipped body exceeding the decompressed size",
+			body:         compress(bytes.Repeat([]byte("a"), 1<<20)),
+			encoding:     "gzip",
+			wantStatus:   http.StatusOK,
+			wantTooLarge: true,
+		},
+		{
+			name:       "invalid gzipped body",
+			body:       []byte(`{"title": "Test Record"}`),
+			encoding:   "gzip",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unsupported encoding",
+			body:       []byte(`{"title": "Test Record"}`),
+			encoding:   "br",
+			wantStatus: http.StatusUnsupportedMediaType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			var got []byte
+			var err error
+			handler := Decompress(&DecompressConfig{
+				MaxSize: 1024,
+			})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Content-Encoding") != "" {
+					t.Errorf("expected no content encoding, got %q", r.Header.Get("Content-Encoding"))
+				}
+				got, err = io.ReadAll(r.Body)
+			}))
+
+			// Initialize test request and response recorder.
+			r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(tt.body))
+			if tt.encoding != "" {
+				r.Header.Set("Content-Encoding", tt.encoding)
+			}
+			w := httptest.NewRecorder()
+
+			// Serve the request.
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("ServeHTTP() = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var tooLarge *http.MaxBytesError
+			if tt.wantTooLarge != errors.As(err, &tooLarge) {
+				t.Fatalf("io.ReadAll() error = %v, wantTooLarge %v", err, tt.wantTooLarge)
+			}
+			if !tt.wantTooLarge && strings.TrimSpace(string(got)) != tt.wantBody {
+				t.Errorf("body = %q, want %q", got, tt.wantBody)
+			}
+		})
+	}
+}
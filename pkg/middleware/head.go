@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// sniffLen is the number of leading bytes of the body `http.DetectContentType` considers.
+const sniffLen = 512
+
+// headWriter is the response writer which discards the body of the response and counts its length.
+type headWriter struct {
+	http.ResponseWriter
+
+	//	Status code written by the handler.
+	status int
+
+	//	Length of the discarded body.
+	length int
+
+	//	Leading bytes of the discarded body, used to detect its content type.
+	sniffed []byte
+}
+
+// Unwrap returns the original response writer.
+func (w *headWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// WriteHeader records the status code, which is written once the length of the body is known.
+func (w *headWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+// Write discards the body and counts its length.
+func (w *headWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.length += len(b)
+	if n := sniffLen - len(w.sniffed); n > 0 {
+		w.sniffed = append(w.sniffed, b[:min(n, len(b))]...)
+	}
+	return len(b), nil
+}
+
+// Head middleware serves the `HEAD` requests with the handler of the `GET` ones.
+//
+// The handler runs as usual, but its body is discarded and only its length is sent in the `Content-Length` header,
+// along with its detected type, so the headers match the ones of the `GET` response. Other requests pass through untouched.
+func Head(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		hw := &headWriter{ResponseWriter: w}
+		next.ServeHTTP(hw, r)
+
+		if hw.status == 0 {
+			hw.status = http.StatusOK
+		}
+
+		// The responses which can't have a body don't declare its length or type either.
+		// The type is detected the same way the server does for the `GET` responses.
+		// Link: https://www.rfc-editor.org/rfc/rfc9110#section-8.6
+		if hw.status >= http.StatusOK && hw.status != http.StatusNoContent && hw.status != http.StatusNotModified {
+			if w.Header().Get("Content-Length") == "" {
+				w.Header().Set("Content-Length", strconv.Itoa(hw.length))
+			}
+			if _, exists := w.Header()["Content-Type"]; !exists && hw.length > 0 {
+				w.Header().Set("Content-Type", http.DetectContentType(hw.sniffed))
+			}
+		}
+		w.WriteHeader(hw.status)
+	})
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHead(t *testing.T) {
+
+	handler := Head(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"message":"ok"}`))
+	}))
+
+	t.Run("serve head request w/o body", func(t *testing.T) {
+
+		// Initialize test request and response recorder.
+		r := httptest.NewRequest(http.MethodHead, "/", nil)
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("ServeHTTP() = %v, want %v", w.Code, http.StatusOK)
+		}
+		if w.Body.Len() != 0 {
+			t.Errorf("expected an empty body, got %q", w.Body.String())
+		}
+		if got := w.Header().Get("Content-Length"); got != "16" {
+			t.Errorf("Content-Length = %q, want %q", got, "16")
+		}
+		if got := w.Header().Get("Content-Type"); got != "application/json" {
+			t.Errorf("Content-Type = %q, want %q", got, "application/json")
+		}
+	})
+
+	t.Run("serve get request untouched", func(t *testing.T) {
+
+		// Initialize test request and response recorder.
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, r)
+
+		if w.Body.String() != `{"message":"ok"}` {
+			t.Errorf("unexpected body %q", w.Body.String())
+		}
+	})
+
+	t.Run("serve head request w/ not modified status", func(t *testing.T) {
+
+		handler := Head(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotModified)
+		}))
+
+		// Initialize test request and response recorder.
+		r := httptest.NewRequest(http.MethodHead, "/", nil)
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, r)
+
+		if w.Code != http.StatusNotModified {
+			t.Errorf("ServeHTTP() = %v, want %v", w.Code, http.StatusNotModified)
+		}
+		if got := w.Header().Get("Content-Length"); got != "" {
+			t.Errorf("expected no Content-Length, got %q", got)
+		}
+	})
+}
@@ -0,0 +1,180 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// jwksMinRefreshInterval is the minimum time between two fetches of the key set triggered by an unknown key ID,
+// so the tokens with made-up key IDs can't make the middleware hammer the key set endpoint.
+const jwksMinRefreshInterval = 10 * time.Second
+
+// jwksFetchTimeout is the timeout of a fetch of the key set.
+// The fetch isn't bound to the request which triggered it, since its keys serve the other requests too.
+const jwksFetchTimeout = 5 * time.Second
+
+// jwks is a JSON Web Key Set fetched from a URL and cached in memory.
+type jwks struct {
+
+	// URL of the key set.
+	url string
+
+	// ttl is for how long the fetched keys are used before they're fetched again.
+	ttl time.Duration
+
+	// client is the HTTP client that will be used to fetch the key set.
+	client *http.Client
+
+	// group coalesces the concurrent fetches of the key set into one.
+	group singleflight.Group
+
+	mu        sync.Mutex
+	keys      map[string]any
+	fetchedAt time.Time
+
+	// attemptedAt is when the last fetch, successful or not, completed.
+	// The fetches are throttled on it, so a failing key set endpoint isn't hammered either.
+	attemptedAt time.Time
+}
+
+// key returns the verification key with the supplied ID.
+//
+// The key set is fetched again if it has expired, or if the key is unknown and the key set may have been rotated since.
+// An expired key keeps being served while the key set is fetched again in the background,
+// so the requests only wait for the fetches of the keys which aren't cached.
+func (s *jwks) key(ctx context.Context, id string) (any, error) {
+	s.mu.Lock()
+	key, exists := s.keys[id]
+	age := time.Since(s.fetchedAt)
+	throttled := time.Since(s.attemptedAt) <= jwksMinRefreshInterval
+	s.mu.Unlock()
+
+	switch {
+	case exists && age > s.ttl:
+		if !throttled {
+			s.group.DoChan("", s.fetch)
+		}
+		return key, nil
+	case exists:
+		return key, nil
+	case throttled:
+		return nil, fmt.Errorf("unknown key id %q", id)
+	}
+
+	// Wait for the fetch, unless the request goes away first.
+	select {
+	case result := <-s.group.DoChan("", s.fetch):
+		if result.Err != nil {
+			return nil, result.Err
+		}
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	s.mu.Lock()
+	key, exists = s.keys[id]
+	s.mu.Unlock()
+	if !exists {
+		return nil, fmt.Errorf("unknown key id %q", id)
+	}
+	return key, nil
+}
+
+// fetch downloads the key set and replaces the cached keys.
+//
+// It must only be called through the group, so the concurrent fetches are coalesced.
+// The attempt is recorded once it completes, rather than when it starts, so the concurrent requests join it instead of being throttled.
+func (s *jwks) fetch() (any, error) {
+	defer func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.attemptedAt = time.Now()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), jwksFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the key set: %w", err)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the key set: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch the key set: unexpected status %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("failed to decode the key set: %w", err)
+	}
+
+	keys := make(map[string]any, len(set.Keys))
+	for _, item := range set.Keys {
+		key, err := item.key()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode the key %q: %w", item.ID, err)
+		}
+
+		// Skip the keys of the types which can't be used to validate the JWTs.
+		if key != nil {
+			keys[item.ID] = key
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+	s.fetchedAt = time.Now()
+	return nil, nil
+}
+
+// jwk is a JSON Web Key, as defined in RFC 7517.
+type jwk struct {
+	ID   string `json:"kid"`
+	Type string `json:"kty"`
+
+	// N and E are the modulus and the exponent of the RSA keys.
+	N string `json:"n"`
+	E string `json:"e"`
+
+	// K is the value of the symmetric keys.
+	K string `json:"k"`
+}
+
+// key decodes the verification key, either an `*rsa.PublicKey` or a `[]byte` secret.
+// It returns nil for the unsupported key types.
+func (k jwk) key() (any, error) {
+	switch k.Type {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "oct":
+		return base64.RawURLEncoding.DecodeString(k.K)
+	}
+	return nil, nil
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestJWKS_Key(t *testing.T) {
+
+	// Starts a key set endpoint which only responds once it's released.
+	setup := func(t *testing.T) (*jwks, chan struct{}) {
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+			json.NewEncoder(w).Encode(map[string]any{
+				"keys": []map[string]string{
+					{
+						"kid": "fresh",
+						"kty": "oct",
+						"k":   base64.RawURLEncoding.EncodeToString([]byte("secret")),
+					},
+				},
+			})
+		}))
+		t.Cleanup(server.Close)
+		return &jwks{
+			url:    server.URL,
+			ttl:    time.Minute,
+			client: server.Client(),
+		}, release
+	}
+
+	// Waits until the key set is fetched again.
+	fetched := func(t *testing.T, s *jwks) {
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+			s.mu.Lock()
+			_, exists := s.keys["fresh"]
+			s.mu.Unlock()
+			if exists {
+				return
+			}
+		}
+		t.Fatal("expected the key set to be fetched again")
+	}
+
+	t.Run("serve expired key while the key set is fetched again", func(t *testing.T) {
+
+		s, release := setup(t)
+		s.keys = map[string]any{"stale": []byte("secret")}
+		s.fetchedAt = time.Now().Add(-time.Hour)
+
+		// The endpoint hangs, so the key must be served from the cache.
+		key, err := s.key(context.Background(), "stale")
+		if err != nil || key == nil {
+			t.Fatalf("key() = %v, %v, want the cached key", key, err)
+		}
+
+		close(release)
+		fetched(t, s)
+	})
+
+	t.Run("fetch outlives the request which triggered it", func(t *testing.T) {
+
+		s, release := setup(t)
+
+		// The request goes away while waiting for the fetch.
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		if _, err := s.key(ctx, "fresh"); err != context.DeadlineExceeded {
+			t.Fatalf("key() error = %v, want %v", err, context.DeadlineExceeded)
+		}
+
+		close(release)
+		fetched(t, s)
+	})
+
+	t.Run("throttle the fetches while the key set endpoint fails", func(t *testing.T) {
+
+		var fetches atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fetches.Add(1)
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		t.Cleanup(server.Close)
+
+		s := &jwks{
+			url:       server.URL,
+			ttl:       time.Minute,
+			client:    server.Client(),
+			keys:      map[string]any{"stale": []byte("secret")},
+			fetchedAt: time.Now().Add(-time.Hour),
+		}
+
+		// The unknown key IDs only trigger the first fetch.
+		for i := 0; i < 5; i++ {
+			if _, err := s.key(context.Background(), "unknown"); err == nil {
+				t.Fatalf("key() error = nil, want an error")
+			}
+		}
+
+		// The expired key is still served, without fetching again.
+		if key, err := s.key(context.Background(), "stale"); err != nil || key == nil {
+			t.Fatalf("key() = %v, %v, want the cached key", key, err)
+		}
+
+		if got := fetches.Load(); got != 1 {
+			t.Errorf("expected the key set to be fetched once, got %d", got)
+		}
+	})
+}
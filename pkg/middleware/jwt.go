@@ -2,21 +2,26 @@ package middleware
 
 import (
 	"context"
+	"crypto/rsa"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/golang-jwt/jwt"
 	"github.com/google/uuid"
 )
 
-// XJWTClaims is the key used to store the claims of the JWT in the context.
+// JWTClaims are the claims of the JWT stored in the request context by the `JWT` middleware.
 //
 // The claims are used to store the information about the authenticated user.
-const XJWTClaims Key = "x-jwt-claims"
-
 type JWTClaims struct {
 	jwt.StandardClaims
 	XUserID uuid.UUID `json:"x-user-id"`
+
+	// Scope and Scopes are the scopes granted to the JWT.
+	// Both the OAuth 2.0 `scope` claim and the `scopes` claim are accepted, in either the space-delimited or the array form.
+	Scope  Scopes `json:"scope,omitempty"`
+	Scopes Scopes `json:"scopes,omitempty"`
 }
 
 func (c JWTClaims) Valid() error {
@@ -56,10 +61,31 @@ type JWTConfig struct {
 	Audience string
 
 	// Key is the secret key that will be used to validate the JWT.
+	// It validates the JWTs without a `kid` header, or all of them if neither `Keys` nor `JWKSURL` is set.
 	//
-	// This field is mandatory.
+	// This field is mandatory, unless `Keys` or `JWKSURL` is set.
 	Key string
 
+	// Keys are the secret keys that will be used to validate the JWT, by their ID.
+	// The JWT is validated with the key named by its `kid` header, so the signing key can be rotated
+	// without downtime by accepting both the old and the new key for a while.
+	// Default: `nil`
+	//
+	// This field is optional.
+	Keys map[string]string
+
+	// JWKSURL is the URL of the JSON Web Key Set to look up the keys, which aren't in `Keys`, from.
+	// Default: ``
+	//
+	// This field is optional.
+	JWKSURL string
+
+	// JWKSCacheTTL is for how long the fetched key set is cached before it's fetched again.
+	// Default: `5m`
+	//
+	// This field is optional.
+	JWKSCacheTTL time.Duration
+
 	// ExceptionalRoutes is the list of routes that will be excluded from the JWT validation.
 	// For example, you can exclude the login route from the JWT validation.
 	//
@@ -85,26 +111,12 @@ func JWT(config *JWTConfig) Middleware {
 		panic("failed to initialize the JWT middleware: missing configuration")
 	}
 
-	if config.Key == "" {
-		panic("failed to initialize the JWT middleware: missing key")
-	}
-
-	//
-	// Set default values.
-	//
-
-	if config.Prefix == "" {
-		config.Prefix = "Bearer"
-	}
-
-	if config.Algorithm == "" {
-		config.Algorithm = "HS256"
-	}
-
 	if config.Header == "" {
 		config.Header = "Authorization"
 	}
 
+	parser := NewJWTParser(config)
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
@@ -129,25 +141,130 @@ func JWT(config *JWTConfig) Middleware {
 			}
 
 			// Parse the JWT and extract the claims.
-			var claims JWTClaims
-			token, err := jwt.ParseWithClaims(header, &claims, func(token *jwt.Token) (interface{}, error) {
-				return []byte(config.Key), nil
-			})
-
+			claims, err := parser.Parse(r.Context(), header)
 			if err != nil {
-				http.Error(w, fmt.Sprintf("failed to parse the JWT: %s", err), http.StatusUnauthorized)
-				return
-			}
-
-			if !token.Valid {
-				http.Error(w, "supplied JWT is invalid", http.StatusUnauthorized)
+				http.Error(w, err.Error(), http.StatusUnauthorized)
 				return
 			}
 
 			// Write the claims to the request context.
-			r = r.WithContext(context.WithValue(r.Context(), XJWTClaims, claims))
+			r = r.WithContext(WithJWTClaims(r.Context(), claims))
 
 			next.ServeHTTP(w, r)
 		})
 	}
 }
+
+// JWTParser validates the JWTs with the keys of a `JWTConfig`.
+//
+// It's what the `JWT` middleware validates the JWTs with, so the transports other than HTTP
+// can use it to validate them identically.
+type JWTParser struct {
+	config *JWTConfig
+
+	//	Key set fetched from `JWKSURL`, if it's set.
+	set *jwks
+}
+
+// NewJWTParser creates a new instance of `JWTParser`.
+//
+// Only the keys of the configuration are used: `Key`, `Keys` and `JWKSURL`, along with `JWKSCacheTTL`.
+func NewJWTParser(config *JWTConfig) *JWTParser {
+
+	// Validate the configuration.
+	if config == nil {
+		panic("failed to initialize the JWT parser: missing configuration")
+	}
+
+	if config.Key == "" && len(config.Keys) == 0 && config.JWKSURL == "" {
+		panic("failed to initialize the JWT parser: missing key")
+	}
+
+	//
+	// Set default values.
+	//
+
+	if config.Prefix == "" {
+		config.Prefix = "Bearer"
+	}
+
+	if config.Algorithm == "" {
+		config.Algorithm = "HS256"
+	}
+
+	if config.JWKSCacheTTL == 0 {
+		config.JWKSCacheTTL = 5 * time.Minute
+	}
+
+	parser := JWTParser{
+		config: config,
+	}
+	if config.JWKSURL != "" {
+		parser.set = &jwks{
+			url:    config.JWKSURL,
+			ttl:    config.JWKSCacheTTL,
+			client: &http.Client{Timeout: 5 * time.Second},
+		}
+	}
+	return &parser
+}
+
+// Parse validates the supplied JWT, without its prefix, and returns its claims.
+func (p *JWTParser) Parse(ctx context.Context, token string) (JWTClaims, error) {
+	return parseJWT(token, p.config.keyfunc(ctx, p.set))
+}
+
+// parseJWT validates the supplied JWT with the key selected by the keyfunc and returns its claims.
+func parseJWT(token string, keyfunc jwt.Keyfunc) (JWTClaims, error) {
+	var claims JWTClaims
+	parsed, err := jwt.ParseWithClaims(token, &claims, keyfunc)
+
+	if err != nil {
+		return claims, fmt.Errorf("failed to parse the JWT: %s", err)
+	}
+
+	if !parsed.Valid {
+		return claims, fmt.Errorf("supplied JWT is invalid")
+	}
+
+	return claims, nil
+}
+
+// keyfunc returns the function which selects the key to validate the JWT with, by its `kid` header.
+func (config *JWTConfig) keyfunc(ctx context.Context, set *jwks) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		id, _ := token.Header["kid"].(string)
+
+		var key any
+		switch {
+		case id == "" || (len(config.Keys) == 0 && set == nil):
+			if config.Key == "" {
+				return nil, fmt.Errorf("missing key id")
+			}
+			key = []byte(config.Key)
+		case config.Keys[id] != "":
+			key = []byte(config.Keys[id])
+		case set != nil:
+			var err error
+			if key, err = set.key(ctx, id); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unknown key id %q", id)
+		}
+
+		// Refuse the JWTs signed with an algorithm of another family than the key's,
+		// like an HMAC signed with a public RSA key used as the secret.
+		switch key.(type) {
+		case []byte:
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, fmt.Errorf("unexpected signing method %q", token.Method.Alg())
+			}
+		case *rsa.PublicKey:
+			if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+				return nil, fmt.Errorf("unexpected signing method %q", token.Method.Alg())
+			}
+		}
+		return key, nil
+	}
+}
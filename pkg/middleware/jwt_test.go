@@ -1,6 +1,11 @@
 package middleware
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -38,7 +43,7 @@ func TestJWT(t *testing.T) {
 		router.Handle("/protected", middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
 			// Read the claims from the request context.
-			claims, exists := r.Context().Value(XJWTClaims).(JWTClaims)
+			claims, exists := JWTClaimsFromContext(r.Context())
 			if !exists {
 				http.Error(w, "failed to parse the claims", http.StatusUnauthorized)
 				return
@@ -68,3 +73,149 @@ func TestJWT(t *testing.T) {
 		}
 	})
 }
+
+func TestJWT_Keys(t *testing.T) {
+
+	// Initialize the JWT middleware with the keys before and after the rotation.
+	middleware := JWT(&JWTConfig{
+		Keys: map[string]string{
+			"old": "old-secret",
+			"new": "new-secret",
+		},
+	})
+	handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// sign signs a dummy JWT with the key and the key ID.
+	sign := func(id, key string) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, JWTClaims{
+			XUserID: uuid.New(),
+		})
+		token.Header["kid"] = id
+		signed, err := token.SignedString([]byte(key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+
+	tests := []struct {
+		name       string
+		token      string
+		wantStatus int
+	}{
+		{
+			name:       "validate jwt signed w/ old key",
+			token:      sign("old", "old-secret"),
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "validate jwt signed w/ new key",
+			token:      sign("new", "new-secret"),
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "reject jwt signed w/ unknown key id",
+			token:      sign("unknown", "old-secret"),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "reject jwt signed w/ key of another key id",
+			token:      sign("new", "old-secret"),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "reject jwt w/o key id",
+			token:      sign("", "old-secret"),
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Initialize test request and response recorder.
+			r := httptest.NewRequest(http.MethodGet, "/protected", nil)
+			w := httptest.NewRecorder()
+
+			r.Header.Add("Authorization", "Bearer "+tt.token)
+
+			// Serve the request.
+			handler.ServeHTTP(w, r)
+
+			// Validate the status code.
+			if status := w.Code; status != tt.wantStatus {
+				t.Logf("Response: %s", w.Body.String())
+				t.Errorf("ServeHTTP() = %v, want %v", status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestJWT_JWKS(t *testing.T) {
+
+	// Generate the signing key and serve its public half in a key set.
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fetches int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{
+				{
+					"kid": "rsa",
+					"kty": "RSA",
+					"n":   base64.RawURLEncoding.EncodeToString(private.PublicKey.N.Bytes()),
+					"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(private.PublicKey.E)).Bytes()),
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	// Initialize the JWT middleware.
+	middleware := JWT(&JWTConfig{
+		JWKSURL: server.URL,
+	})
+	handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// serve serves a request carrying the JWT and returns the status code.
+	serve := func(token *jwt.Token, key any) int {
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := httptest.NewRequest(http.MethodGet, "/protected", nil)
+		r.Header.Add("Authorization", "Bearer "+signed)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	t.Run("validate jwt signed w/ key from key set", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			token := jwt.NewWithClaims(jwt.SigningMethodRS256, JWTClaims{XUserID: uuid.New()})
+			token.Header["kid"] = "rsa"
+			if status := serve(token, private); status != http.StatusOK {
+				t.Fatalf("ServeHTTP() = %v, want %v", status, http.StatusOK)
+			}
+		}
+
+		// The key set is cached.
+		if fetches != 1 {
+			t.Errorf("expected the key set to be fetched once, got %d", fetches)
+		}
+	})
+
+	t.Run("reject jwt w/ unknown key id", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, JWTClaims{XUserID: uuid.New()})
+		token.Header["kid"] = "unknown"
+		if status := serve(token, private); status != http.StatusUnauthorized {
+			t.Fatalf("ServeHTTP() = %v, want %v", status, http.StatusUnauthorized)
+		}
+	})
+}
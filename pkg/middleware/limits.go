@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+)
+
+type RequestLimitsConfig struct {
+
+	// MaxURLLength is the maximum length of the request URI, including the query string.
+	// Default: `8192`
+	//
+	// This field is optional.
+	MaxURLLength int
+
+	// MaxHeaderBytes is the maximum total size of the request headers, counting their names and values.
+	// Default: `16384`
+	//
+	// This field is optional.
+	MaxHeaderBytes int
+
+	// MaxQueryParams is the maximum number of query parameters, counting each value of a repeated key.
+	// Default: `32`
+	//
+	// This field is optional.
+	MaxQueryParams int
+
+	// MaxQueryValues is the maximum number of values of a single query parameter, like `?id=1&id=2`.
+	// Default: `8`
+	//
+	// This field is optional.
+	MaxQueryValues int
+}
+
+// RequestLimits middleware rejects the requests whose URL or headers exceed the configured limits
+// with `431 Request Header Fields Too Large`, and the ones with too many query parameters,
+// or too many values of a single one, with `400 Bad Request`.
+//
+// The `http.Server` only enforces a coarse limit on the whole header block, so this middleware
+// keeps the oversized values away from the parsing and logging paths, and the query parameters
+// away from the filter parsers and the binders of the handlers.
+func RequestLimits(config *RequestLimitsConfig) Middleware {
+
+	// Set the default configuration.
+	if config == nil {
+		config = &RequestLimitsConfig{}
+	}
+
+	if config.MaxURLLength <= 0 {
+		config.MaxURLLength = 8192
+	}
+
+	if config.MaxHeaderBytes <= 0 {
+		config.MaxHeaderBytes = 16384
+	}
+
+	if config.MaxQueryParams <= 0 {
+		config.MaxQueryParams = 32
+	}
+
+	if config.MaxQueryValues <= 0 {
+		config.MaxQueryValues = 8
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if len(r.URL.RequestURI()) > config.MaxURLLength {
+				http.Error(w, "request url too long", http.StatusRequestHeaderFieldsTooLarge)
+				return
+			}
+
+			size := 0
+			for name, values := range r.Header {
+				for _, value := range values {
+
+					// Count the separator and the line break of each header line.
+					size += len(name) + len(value) + 4
+				}
+			}
+			if size > config.MaxHeaderBytes {
+				http.Error(w, "request headers too large", http.StatusRequestHeaderFieldsTooLarge)
+				return
+			}
+
+			if raw := r.URL.RawQuery; raw != "" {
+
+				// Count the parameters before parsing them, so an excessive query is never parsed.
+				if strings.Count(raw, "&")+1 > config.MaxQueryParams {
+					http.Error(w, "too many query parameters", http.StatusBadRequest)
+					return
+				}
+				for _, values := range r.URL.Query() {
+					if len(values) > config.MaxQueryValues {
+						http.Error(w, "too many values of a query parameter", http.StatusBadRequest)
+						return
+					}
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
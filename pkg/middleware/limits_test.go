@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestLimits(t *testing.T) {
+
+	// Initialize the middleware with small limits.
+	limits := RequestLimits(&RequestLimitsConfig{
+		MaxURLLength:   64,
+		MaxHeaderBytes: 256,
+		MaxQueryParams: 3,
+		MaxQueryValues: 2,
+	})
+
+	tests := []struct {
+		name string
+
+		// request returns the request to serve.
+		request func() *http.Request
+
+		// wantStatus is the status code we expect in response.
+		wantStatus int
+	}{
+		{
+			name: "request within the limits",
+			request: func() *http.Request {
+				r := httptest.NewRequest(http.MethodGet, "/v1?limit=10", nil)
+				r.Header.Set("Accept", "application/json")
+				return r
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "request w/ long query string",
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/v1?search="+strings.Repeat("a", 64), nil)
+			},
+			wantStatus: http.StatusRequestHeaderFieldsTooLarge,
+		},
+		{
+			name: "request w/ oversized header",
+			request: func() *http.Request {
+				r := httptest.NewRequest(http.MethodGet, "/v1", nil)
+				r.Header.Set("X-Custom", strings.Repeat("a", 512))
+				return r
+			},
+			wantStatus: http.StatusRequestHeaderFieldsTooLarge,
+		},
+		{
+			name: "request w/ many small headers",
+			request: func() *http.Request {
+				r := httptest.NewRequest(http.MethodGet, "/v1", nil)
+				for i := 0; i < 32; i++ {
+					r.Header.Add("X-Custom", "value")
+				}
+				return r
+			},
+			wantStatus: http.StatusRequestHeaderFieldsTooLarge,
+		},
+		{
+			name: "request w/ query parameters at the limit",
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/v1?a=1&b=2&b=3", nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "request w/ too many query parameters",
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/v1?a=1&b=2&c=3&d=4", nil)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "request w/ too many values of a query parameter",
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/v1?id=1&id=2&id=3", nil)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			w := httptest.NewRecorder()
+
+			// Serve the request.
+			limits(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})).ServeHTTP(w, tt.request())
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("ServeHTTP() = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -9,6 +11,9 @@ import (
 	"github.com/mrinalwahal/boilerplate/pkg/writer"
 )
 
+// unknownRequestID is the placeholder logged for the requests which don't carry a request ID.
+const unknownRequestID = "unknown"
+
 type LoggingConfig struct {
 
 	// Logger is the `log/slog` instance that will be used to log messages.
@@ -52,6 +57,9 @@ func Logging(config *LoggingConfig) Middleware {
 			// Like we do it in the `RequestID` middleware.
 			//
 
+			// Let the router record the pattern of the matched route.
+			r = r.WithContext(withRoute(r.Context()))
+
 			writer := writer.NewWriter(w)
 			next.ServeHTTP(writer, r)
 
@@ -60,13 +68,26 @@ func Logging(config *LoggingConfig) Middleware {
 			// For our use case, we are going to log the request.
 			//
 
+			// The request ID is absent if the `RequestID` middleware isn't chained before this one.
+			requestID, exists := RequestIDFromContext(r.Context())
+			if !exists {
+				requestID = unknownRequestID
+			}
+
+			// Log the pattern of the matched route instead of the raw path, when the router recorded it,
+			// so the IDs in the path don't explode the cardinality of the logged paths.
+			path, exists := RoutePatternFromContext(r.Context())
+			if !exists {
+				path = r.URL.Path
+			}
+
 			attributes := []slog.Attr{
 				{Key: "timestamp", Value: slog.StringValue(start.String())},
-				{Key: "request_id", Value: slog.StringValue(r.Context().Value(XRequestID).(string))},
+				{Key: "request_id", Value: slog.StringValue(requestID)},
 				{Key: "status", Value: slog.IntValue(writer.Status())},
 				{Key: "hostname", Value: slog.StringValue(r.Host)},
 				{Key: "method", Value: slog.StringValue(r.Method)},
-				{Key: "path", Value: slog.StringValue(r.URL.Path)},
+				{Key: "path", Value: slog.StringValue(path)},
 			}
 
 			if config.LogLatency {
@@ -80,7 +101,13 @@ func Logging(config *LoggingConfig) Middleware {
 				// attributes = append(attributes, slog.Attr{Key: "error", Value: slog.StringValue(writer.Error())})
 
 			} else {
-				config.Logger.LogAttrs(r.Context(), slog.LevelInfo, fmt.Sprintf("incoming %s request to %s", r.Method, r.URL.Path), attributes...)
+
+				// The requests abandoned by their clients are noise rather than failures of the server.
+				level := slog.LevelInfo
+				if errors.Is(r.Context().Err(), context.Canceled) {
+					level = slog.LevelDebug
+				}
+				config.Logger.LogAttrs(r.Context(), level, fmt.Sprintf("incoming %s request to %s", r.Method, path), attributes...)
 			}
 		})
 	}
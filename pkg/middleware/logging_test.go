@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLogging(t *testing.T) {
+
+	t.Run("log standalone without a request id", func(t *testing.T) {
+
+		var buffer bytes.Buffer
+
+		// Initialize test request and response recorder.
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		w := httptest.NewRecorder()
+
+		// Serve the request.
+		Logging(&LoggingConfig{
+			Logger: slog.New(slog.NewTextHandler(&buffer, nil)),
+		})(http.NotFoundHandler()).ServeHTTP(w, r)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("ServeHTTP() = %v, want %v", w.Code, http.StatusNotFound)
+		}
+
+		if !strings.Contains(buffer.String(), "request_id="+unknownRequestID) {
+			t.Errorf("expected the placeholder request id in the log, got %q", buffer.String())
+		}
+	})
+
+	t.Run("log request abandoned by the client at debug level", func(t *testing.T) {
+
+		var buffer bytes.Buffer
+
+		// Initialize a test request whose client went away.
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		r := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+
+		// Serve the request.
+		Logging(&LoggingConfig{
+			Logger: slog.New(slog.NewTextHandler(&buffer, nil)),
+		})(http.NotFoundHandler()).ServeHTTP(w, r)
+
+		if buffer.Len() != 0 {
+			t.Errorf("expected no log at the info level, got %q", buffer.String())
+		}
+
+		// Serve the request again, logging at the debug level.
+		Logging(&LoggingConfig{
+			Logger: slog.New(slog.NewTextHandler(&buffer, &slog.HandlerOptions{Level: slog.LevelDebug})),
+		})(http.NotFoundHandler()).ServeHTTP(httptest.NewRecorder(), r)
+
+		if !strings.Contains(buffer.String(), "level=DEBUG") {
+			t.Errorf("expected the request logged at the debug level, got %q", buffer.String())
+		}
+	})
+
+	t.Run("log request with a request id", func(t *testing.T) {
+
+		var buffer bytes.Buffer
+
+		// Initialize test request and response recorder.
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		w := httptest.NewRecorder()
+
+		// Serve the request.
+		Chain(
+			RequestID(nil),
+			Logging(&LoggingConfig{
+				Logger: slog.New(slog.NewTextHandler(&buffer, nil)),
+			}),
+		)(http.NotFoundHandler()).ServeHTTP(w, r)
+
+		id := w.Header().Get(string(XRequestID))
+		if id == "" || !strings.Contains(buffer.String(), "request_id="+id) {
+			t.Errorf("expected the request id %q in the log, got %q", id, buffer.String())
+		}
+	})
+
+	t.Run("log the pattern of the matched route", func(t *testing.T) {
+
+		var buffer bytes.Buffer
+
+		// Initialize test request and response recorder.
+		r := httptest.NewRequest(http.MethodGet, "/v1/0f8fad5b-d9cb-469f-a165-70867728950e", nil)
+		w := httptest.NewRecorder()
+
+		// Serve the request with a router which records the pattern.
+		Logging(&LoggingConfig{
+			Logger: slog.New(slog.NewTextHandler(&buffer, nil)),
+		})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			SetRoutePattern(r.Context(), "GET /v1/{id}")
+		})).ServeHTTP(w, r)
+
+		if !strings.Contains(buffer.String(), "path=/v1/{id}") {
+			t.Errorf("expected the route pattern in the log, got %q", buffer.String())
+		}
+		if strings.Contains(buffer.String(), r.URL.Path) {
+			t.Errorf("expected the raw path to be left out of the log, got %q", buffer.String())
+		}
+	})
+}
@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// X-Request-Nonce is the key used to store the nonce in the request header.
+//
+// The nonce is a value the client generates uniquely for each request, so a captured request can't be replayed.
+const XRequestNonce Key = "X-Request-Nonce"
+
+// NonceStore remembers the nonces which were already used.
+type NonceStore interface {
+
+	// Use records the nonce for the supplied duration, and reports whether it was unused until now.
+	Use(ctx context.Context, nonce string, ttl time.Duration) (bool, error)
+}
+
+// MemoryNonceStore is a `NonceStore` which keeps the nonces in memory.
+//
+// The nonces aren't shared across the instances of the service, so it only suits a single instance.
+type MemoryNonceStore struct {
+	mu sync.Mutex
+
+	//	Expiry of each used nonce.
+	expiries map[string]time.Time
+
+	//	Time of the last sweep of the expired nonces.
+	swept time.Time
+}
+
+// NewMemoryNonceStore creates an empty `MemoryNonceStore`.
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{
+		expiries: make(map[string]time.Time),
+		swept:    time.Now(),
+	}
+}
+
+// Use records the nonce for the supplied duration, and reports whether it was unused until now.
+//
+// This method is required to implement the `NonceStore` interface.
+func (s *MemoryNonceStore) Use(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	// Forget the expired nonces, at most once per window, so the sweep doesn't slow down every request.
+	if now.Sub(s.swept) > ttl {
+		for key, expiry := range s.expiries {
+			if now.After(expiry) {
+				delete(s.expiries, key)
+			}
+		}
+		s.swept = now
+	}
+
+	if expiry, exists := s.expiries[nonce]; exists && now.Before(expiry) {
+		return false, nil
+	}
+	s.expiries[nonce] = now.Add(ttl)
+	return true, nil
+}
+
+type NonceConfig struct {
+
+	// Store remembers the used nonces.
+	// Default: `NewMemoryNonceStore()`
+	//
+	// This field is optional.
+	Store NonceStore
+
+	// Window is the duration a nonce can't be reused for.
+	// Default: `5m`
+	//
+	// This field is optional.
+	Window time.Duration
+}
+
+// Nonce middleware rejects the replayed requests of the sensitive mutating endpoints.
+//
+// Their requests must carry an `X-Request-Nonce` header, unique per user within the window,
+// otherwise they're rejected with `400 Bad Request`. A reused nonce is rejected with `409 Conflict`.
+// The safe methods, like `GET`, aren't checked.
+//
+// Unlike an idempotency key, which replays the response of a retried request, a nonce never lets the request
+// through twice. It must be chained after the `JWT` middleware, so the nonces are scoped to the users.
+func Nonce(config *NonceConfig) Middleware {
+
+	// Set the default configuration.
+	if config == nil {
+		config = &NonceConfig{}
+	}
+
+	if config.Store == nil {
+		config.Store = NewMemoryNonceStore()
+	}
+
+	if config.Window <= 0 {
+		config.Window = 5 * time.Minute
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			nonce := r.Header.Get(string(XRequestNonce))
+			if nonce == "" {
+				http.Error(w, "missing request nonce", http.StatusBadRequest)
+				return
+			}
+
+			// Scope the nonce to the user, so the users can't burn each other's nonces.
+			var user string
+			if claims, exists := JWTClaimsFromContext(r.Context()); exists {
+				user = claims.XUserID.String()
+			}
+
+			unused, err := config.Store.Use(r.Context(), user+":"+nonce, config.Window)
+			if err != nil {
+				http.Error(w, "failed to check the request nonce", http.StatusInternalServerError)
+				return
+			}
+			if !unused {
+				http.Error(w, "request nonce already used", http.StatusConflict)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
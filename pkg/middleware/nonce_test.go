@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNonce(t *testing.T) {
+
+	alice, bob := uuid.New(), uuid.New()
+
+	// Serve the requests in sequence through the same middleware, so the nonces of the earlier ones are remembered.
+	handler := Nonce(&NonceConfig{
+		Window: time.Minute,
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		name       string
+		method     string
+		user       uuid.UUID
+		nonce      string
+		wantStatus int
+	}{
+		{
+			name:       "fresh nonce",
+			method:     http.MethodPost,
+			user:       alice,
+			nonce:      "n-1",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "replayed nonce",
+			method:     http.MethodPost,
+			user:       alice,
+			nonce:      "n-1",
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "nonce of another user",
+			method:     http.MethodPost,
+			user:       bob,
+			nonce:      "n-1",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "missing nonce",
+			method:     http.MethodDelete,
+			user:       alice,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "safe method w/o nonce",
+			method:     http.MethodGet,
+			user:       alice,
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Initialize test request and response recorder.
+			r := httptest.NewRequest(tt.method, "/", nil)
+			if tt.nonce != "" {
+				r.Header.Set(string(XRequestNonce), tt.nonce)
+			}
+			r = r.WithContext(WithJWTClaims(r.Context(), JWTClaims{
+				XUserID: tt.user,
+			}))
+			w := httptest.NewRecorder()
+
+			// Serve the request.
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("ServeHTTP() = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestMemoryNonceStore(t *testing.T) {
+
+	store := NewMemoryNonceStore()
+	ctx := context.Background()
+
+	if unused, err := store.Use(ctx, "nonce", 10*time.Millisecond); err != nil || !unused {
+		t.Fatalf("store.Use() = %v, %v, want true", unused, err)
+	}
+	if unused, _ := store.Use(ctx, "nonce", 10*time.Millisecond); unused {
+		t.Fatalf("store.Use() = %v, want false within the window", unused)
+	}
+
+	// The nonce can be used again once the window is over.
+	time.Sleep(20 * time.Millisecond)
+	if unused, _ := store.Use(ctx, "nonce", 10*time.Millisecond); !unused {
+		t.Fatalf("store.Use() = %v, want true after the window", unused)
+	}
+}
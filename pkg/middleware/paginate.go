@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+type PaginateConfig struct {
+
+	// LimitParam is the name of the query parameter holding the number of items to return.
+	// Default: `limit`
+	//
+	// This field is optional.
+	LimitParam string
+
+	// SkipParam is the name of the query parameter holding the number of items to skip.
+	// Default: `skip`
+	//
+	// This field is optional.
+	SkipParam string
+
+	// MaxLimit is the maximum number of items a client can request. Larger limits are clamped to it.
+	// Default: `100`
+	//
+	// This field is optional.
+	MaxLimit int
+
+	// MaxLimitByScope raises the maximum limit for the requests whose JWT grants the scope, like the administrators.
+	// The highest limit among the granted scopes wins over `MaxLimit`.
+	// Default: `nil`
+	//
+	// This field is optional.
+	MaxLimitByScope map[string]int
+
+	// MaxSkip is the maximum number of items a client can skip. Larger offsets are clamped to it.
+	// Default: `10000`
+	//
+	// This field is optional.
+	MaxSkip int
+}
+
+// Paginate middleware sanitizes the pagination parameters in the query before they reach the handler.
+//
+// Negative values are coerced to zero and values over the configured bounds are clamped to them.
+// Non-numeric values are rejected with `400 Bad Request`.
+func Paginate(config *PaginateConfig) Middleware {
+
+	// Set the default configuration.
+	if config == nil {
+		config = &PaginateConfig{}
+	}
+
+	if config.LimitParam == "" {
+		config.LimitParam = "limit"
+	}
+
+	if config.SkipParam == "" {
+		config.SkipParam = "skip"
+	}
+
+	if config.MaxLimit <= 0 {
+		config.MaxLimit = 100
+	}
+
+	if config.MaxSkip <= 0 {
+		config.MaxSkip = 10000
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query := r.URL.Query()
+
+			bounds := map[string]int{
+				config.LimitParam: config.maxLimit(r),
+				config.SkipParam:  config.MaxSkip,
+			}
+
+			changed := false
+			for param, bound := range bounds {
+				raw := query.Get(param)
+				if raw == "" {
+					continue
+				}
+
+				value, err := strconv.Atoi(raw)
+				if err != nil {
+					http.Error(w, fmt.Sprintf("invalid %s: must be a number", param), http.StatusBadRequest)
+					return
+				}
+
+				clamped := min(max(value, 0), bound)
+				if clamped != value || len(query[param]) > 1 {
+					query.Set(param, strconv.Itoa(clamped))
+					changed = true
+				}
+			}
+
+			if changed {
+				r = r.Clone(r.Context())
+				r.URL.RawQuery = query.Encode()
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// maxLimit returns the maximum limit for the scopes granted to the JWT of the request.
+func (config *PaginateConfig) maxLimit(r *http.Request) int {
+	limit := config.MaxLimit
+	claims, exists := JWTClaimsFromContext(r.Context())
+	if !exists {
+		return limit
+	}
+	for scope, scoped := range config.MaxLimitByScope {
+		if scoped > limit && claims.HasScopes(scope) {
+			limit = scoped
+		}
+	}
+	return limit
+}
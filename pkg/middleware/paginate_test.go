@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPaginate(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		query     string
+		claims    *JWTClaims
+		want      int
+		wantLimit string
+		wantSkip  string
+	}{
+		{
+			name:      "coerce negative values to zero",
+			query:     "limit=-5&skip=-1",
+			want:      http.StatusOK,
+			wantLimit: "0",
+			wantSkip:  "0",
+		},
+		{
+			name:      "clamp oversized values",
+			query:     "limit=1000&skip=50000",
+			want:      http.StatusOK,
+			wantLimit: "10",
+			wantSkip:  "100",
+		},
+		{
+			name:      "keep values within bounds",
+			query:     "limit=5&skip=20&search=test",
+			want:      http.StatusOK,
+			wantLimit: "5",
+			wantSkip:  "20",
+		},
+		{
+			name:      "clamp oversized limit to the highest cap of the granted scopes",
+			query:     "limit=1000",
+			claims:    &JWTClaims{Scope: Scopes{"ops", "admin"}},
+			want:      http.StatusOK,
+			wantLimit: "50",
+		},
+		{
+			name:      "keep limit within the cap of the granted scope",
+			query:     "limit=30",
+			claims:    &JWTClaims{Scope: Scopes{"admin"}},
+			want:      http.StatusOK,
+			wantLimit: "30",
+		},
+		{
+			name:      "clamp limit to the default cap without a scope",
+			query:     "limit=30",
+			claims:    &JWTClaims{Scope: Scopes{"records:read"}},
+			want:      http.StatusOK,
+			wantLimit: "10",
+		},
+		{
+			name:  "reject non-numeric limit",
+			query: "limit=ten",
+			want:  http.StatusBadRequest,
+		},
+		{
+			name:  "reject non-numeric skip",
+			query: "skip=1.5",
+			want:  http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			var limit, skip string
+
+			// Initialize test request and response recorder.
+			r := httptest.NewRequest(http.MethodGet, "/?"+tt.query, nil)
+			if tt.claims != nil {
+				r = r.WithContext(WithJWTClaims(r.Context(), *tt.claims))
+			}
+			w := httptest.NewRecorder()
+
+			// Serve the request.
+			Paginate(&PaginateConfig{
+				MaxLimit: 10,
+				MaxLimitByScope: map[string]int{
+					"admin": 50,
+					"ops":   20,
+				},
+				MaxSkip: 100,
+			})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				limit = r.URL.Query().Get("limit")
+				skip = r.URL.Query().Get("skip")
+			})).ServeHTTP(w, r)
+
+			if w.Code != tt.want {
+				t.Fatalf("ServeHTTP() = %v, want %v", w.Code, tt.want)
+			}
+			if limit != tt.wantLimit {
+				t.Errorf("limit = %q, want %q", limit, tt.wantLimit)
+			}
+			if skip != tt.wantSkip {
+				t.Errorf("skip = %q, want %q", skip, tt.wantSkip)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type RateLimitConfig struct {
+
+	// Limit is the maximum number of requests a client can make in a window.
+	// If it is zero, the requests are not limited.
+	// Default: `0`
+	//
+	// This field is optional.
+	Limit int
+
+	// Window is the duration of the windows the requests are counted in.
+	// Default: `1m`
+	//
+	// This field is optional.
+	Window time.Duration
+
+	// Key returns the key identifying the client of the request, whose requests are counted together.
+	// Default: the IP address of the client
+	//
+	// This field is optional.
+	Key func(r *http.Request) string
+}
+
+// limiter counts the requests of the clients in fixed windows, shared by all the clients.
+type limiter struct {
+	mu sync.Mutex
+
+	//	Start of the current window.
+	start time.Time
+
+	//	Number of the requests of each client in the current window.
+	counts map[string]int
+}
+
+// take counts a request of the client, and returns the number of its requests in the current window
+// along with the end of the window.
+func (l *limiter) take(key string, window time.Duration) (int, time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Forget the counts of the past window, so the clients which went away don't pile up.
+	if start := time.Now().Truncate(window); !start.Equal(l.start) {
+		l.start = start
+		clear(l.counts)
+	}
+	l.counts[key]++
+	return l.counts[key], l.start.Add(window)
+}
+
+// RateLimit middleware limits the number of requests a client can make in fixed windows.
+//
+// Every response carries the `RateLimit-Limit`, `RateLimit-Remaining` and `RateLimit-Reset` headers
+// of the IETF draft, so the clients can throttle themselves before they hit the limit.
+// The requests over the limit are rejected with `429 Too Many Requests` and a `Retry-After` header.
+func RateLimit(config *RateLimitConfig) Middleware {
+
+	// Set the default configuration.
+	if config == nil {
+		config = &RateLimitConfig{}
+	}
+
+	if config.Window <= 0 {
+		config.Window = time.Minute
+	}
+
+	if config.Key == nil {
+		config.Key = clientIP
+	}
+
+	l := &limiter{
+		counts: make(map[string]int),
+	}
+
+	return func(next http.Handler) http.Handler {
+		if config.Limit <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			count, end := l.take(config.Key(r), config.Window)
+
+			// The reset is rounded up, so the clients never retry before the window ends.
+			reset := strconv.Itoa(int((time.Until(end) + time.Second - 1) / time.Second))
+
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(config.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(max(config.Limit-count, 0)))
+			w.Header().Set("RateLimit-Reset", reset)
+
+			if count > config.Limit {
+				w.Header().Set("Retry-After", reset)
+				http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientIP returns the IP address of the client of the request.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
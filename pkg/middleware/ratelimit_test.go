@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+
+	t.Run("decrement the remaining requests across successive requests", func(t *testing.T) {
+
+		handler := RateLimit(&RateLimitConfig{
+			Limit:  2,
+			Window: time.Hour,
+		})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+		for i, want := range []struct {
+			status    int
+			remaining string
+		}{
+			{status: http.StatusOK, remaining: "1"},
+			{status: http.StatusOK, remaining: "0"},
+			{status: http.StatusTooManyRequests, remaining: "0"},
+		} {
+
+			// Initialize test request and response recorder.
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			w := httptest.NewRecorder()
+
+			// Serve the request.
+			handler.ServeHTTP(w, r)
+
+			if w.Code != want.status {
+				t.Fatalf("request %d: ServeHTTP() = %v, want %v", i, w.Code, want.status)
+			}
+			if got := w.Header().Get("RateLimit-Limit"); got != "2" {
+				t.Errorf("request %d: RateLimit-Limit = %q, want %q", i, got, "2")
+			}
+			if got := w.Header().Get("RateLimit-Remaining"); got != want.remaining {
+				t.Errorf("request %d: RateLimit-Remaining = %q, want %q", i, got, want.remaining)
+			}
+			reset, err := strconv.Atoi(w.Header().Get("RateLimit-Reset"))
+			if err != nil || reset <= 0 || reset > int(time.Hour/time.Second) {
+				t.Errorf("request %d: RateLimit-Reset = %q, want the seconds until the window ends", i, w.Header().Get("RateLimit-Reset"))
+			}
+			if want.status == http.StatusTooManyRequests && w.Header().Get("Retry-After") == "" {
+				t.Errorf("request %d: expected the Retry-After header", i)
+			}
+		}
+	})
+
+	t.Run("count the requests of the clients separately", func(t *testing.T) {
+
+		handler := RateLimit(&RateLimitConfig{
+			Limit:  1,
+			Window: time.Hour,
+		})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+		for _, addr := range []string{"192.0.2.1:1234", "192.0.2.2:1234"} {
+
+			// Initialize test request and response recorder.
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = addr
+			w := httptest.NewRecorder()
+
+			// Serve the request.
+			handler.ServeHTTP(w, r)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("%s: ServeHTTP() = %v, want %v", addr, w.Code, http.StatusOK)
+			}
+		}
+	})
+
+	t.Run("skip the limits w/o a limit", func(t *testing.T) {
+
+		handler := RateLimit(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+		// Initialize test request and response recorder.
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		w := httptest.NewRecorder()
+
+		// Serve the request.
+		handler.ServeHTTP(w, r)
+
+		if got := w.Header().Get("RateLimit-Limit"); got != "" {
+			t.Errorf("RateLimit-Limit = %q, want none", got)
+		}
+	})
+}
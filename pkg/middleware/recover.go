@@ -1,8 +1,10 @@
 package middleware
 
 import (
+	"fmt"
 	"log/slog"
 	"net/http"
+	"runtime/debug"
 )
 
 type RecoverConfig struct {
@@ -12,10 +14,29 @@ type RecoverConfig struct {
 	//
 	// This field is optional.
 	Logger *slog.Logger
+
+	// IncludeStackInResponse writes the panic and its stack trace in the response body.
+	// Only enable it in development, since the trace exposes the internals of the service.
+	// Default: `false`
+	//
+	// This field is optional.
+	IncludeStackInResponse bool
 }
 
 // Recover is a middleware that recovers from the panics.
+//
+// The stack trace is always logged. The response carries either the stack trace,
+// if `IncludeStackInResponse` is enabled, or a generic message with the request ID.
+//
+// If the panic value is an `http.Handler`, it's a deliberate abort rather than a crash:
+// the value renders the response itself and no stack trace is logged.
 func Recover(config *RecoverConfig) Middleware {
+
+	// Set the default configuration.
+	if config == nil {
+		config = &RecoverConfig{}
+	}
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			defer func() {
@@ -26,15 +47,35 @@ func Recover(config *RecoverConfig) Middleware {
 						panic(err)
 					}
 
+					// The panics with a value which renders its own response, like the `Response` of the v1 handlers,
+					// deliberately cut the control flow short, so they're rendered as is instead of as a crash.
+					if response, ok := err.(http.Handler); ok {
+						if config.Logger != nil {
+							config.Logger.LogAttrs(r.Context(), slog.LevelDebug, "panic rendered as response")
+						}
+						response.ServeHTTP(w, r)
+						return
+					}
+
+					stack := debug.Stack()
 					if config.Logger != nil {
 						config.Logger.LogAttrs(r.Context(), slog.LevelError, "panic recovered", slog.Attr{
 							Key:   "panic error",
 							Value: slog.AnyValue(err),
-						})
+						}, slog.String("stack", string(stack)))
 					}
 
 					if r.Header.Get("Connection") != "Upgrade" {
-						w.WriteHeader(http.StatusInternalServerError)
+						if config.IncludeStackInResponse {
+							http.Error(w, fmt.Sprintf("panic: %v\n\n%s", err, stack), http.StatusInternalServerError)
+							return
+						}
+
+						id, exists := RequestIDFromContext(r.Context())
+						if !exists {
+							id = unknownRequestID
+						}
+						http.Error(w, fmt.Sprintf("internal server error (request id: %s)", id), http.StatusInternalServerError)
 					}
 				}
 			}()
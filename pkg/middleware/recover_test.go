@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRecover(t *testing.T) {
+
+	// Handler which always panics.
+	panicking := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("something went wrong")
+	})
+
+	t.Run("include stack in response", func(t *testing.T) {
+
+		var buffer bytes.Buffer
+
+		// Initialize test request and response recorder.
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		w := httptest.NewRecorder()
+
+		// Serve the request.
+		Recover(&RecoverConfig{
+			Logger:                 slog.New(slog.NewTextHandler(&buffer, nil)),
+			IncludeStackInResponse: true,
+		})(panicking).ServeHTTP(w, r)
+
+		if w.Code != http.StatusInternalServerError {
+			t.Fatalf("ServeHTTP() = %v, want %v", w.Code, http.StatusInternalServerError)
+		}
+
+		body := w.Body.String()
+		if !strings.Contains(body, "something went wrong") || !strings.Contains(body, "goroutine") {
+			t.Errorf("expected the panic and its stack trace in the response, got %q", body)
+		}
+
+		if !strings.Contains(buffer.String(), "stack=") {
+			t.Errorf("expected the stack trace in the log, got %q", buffer.String())
+		}
+	})
+
+	t.Run("hide stack from response", func(t *testing.T) {
+
+		var buffer bytes.Buffer
+
+		// Initialize test request and response recorder.
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		w := httptest.NewRecorder()
+
+		// Serve the request.
+		Chain(
+			RequestID(nil),
+			Recover(&RecoverConfig{
+				Logger: slog.New(slog.NewTextHandler(&buffer, nil)),
+			}),
+		)(panicking).ServeHTTP(w, r)
+
+		if w.Code != http.StatusInternalServerError {
+			t.Fatalf("ServeHTTP() = %v, want %v", w.Code, http.StatusInternalServerError)
+		}
+
+		body := w.Body.String()
+		if strings.Contains(body, "something went wrong") || strings.Contains(body, "goroutine") {
+			t.Errorf("expected no panic details in the response, got %q", body)
+		}
+
+		id := w.Header().Get(string(XRequestID))
+		if id == "" || !strings.Contains(body, id) {
+			t.Errorf("expected the request id %q in the response, got %q", id, body)
+		}
+
+		if !strings.Contains(buffer.String(), "stack=") {
+			t.Errorf("expected the stack trace in the log, got %q", buffer.String())
+		}
+	})
+
+	t.Run("render panic value which is a handler", func(t *testing.T) {
+
+		var buffer bytes.Buffer
+
+		// Initialize test request and response recorder.
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		w := httptest.NewRecorder()
+
+		// Serve the request.
+		Recover(&RecoverConfig{
+			Logger: slog.New(slog.NewTextHandler(&buffer, nil)),
+		})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "conflict", http.StatusConflict)
+			}))
+		})).ServeHTTP(w, r)
+
+		if w.Code != http.StatusConflict {
+			t.Fatalf("ServeHTTP() = %v, want %v", w.Code, http.StatusConflict)
+		}
+
+		if strings.Contains(buffer.String(), "stack") {
+			t.Errorf("expected no stack trace in the log, got %q", buffer.String())
+		}
+	})
+}
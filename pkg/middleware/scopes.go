@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// Scopes are the scopes granted to a JWT.
+//
+// They unmarshal from both the space-delimited string form, e.g. `"records:read records:write"`,
+// and the array form, e.g. `["records:read", "records:write"]`.
+type Scopes []string
+
+func (s *Scopes) UnmarshalJSON(data []byte) error {
+	var delimited string
+	if err := json.Unmarshal(data, &delimited); err == nil {
+		*s = strings.Fields(delimited)
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*s = list
+	return nil
+}
+
+// HasScopes reports whether the claims grant all the supplied scopes.
+func (c JWTClaims) HasScopes(scopes ...string) bool {
+	granted := make(map[string]bool, len(c.Scope)+len(c.Scopes))
+
+	// Range over both the claims separately: appending one to the other could write into the spare capacity
+	// of its backing array, which is shared by the goroutines reading the claims from the context.
+	for _, scope := range c.Scope {
+		granted[scope] = true
+	}
+	for _, scope := range c.Scopes {
+		granted[scope] = true
+	}
+	for _, scope := range scopes {
+		if !granted[scope] {
+			return false
+		}
+	}
+	return true
+}
+
+// RequireScopes is a middleware that allows the request only if its JWT grants all the supplied scopes.
+//
+// It reads the claims written by the `JWT` middleware, so it must be chained after it.
+// The requests without claims are rejected with `401 Unauthorized`,
+// and the ones missing any of the scopes with `403 Forbidden`.
+func RequireScopes(scopes ...string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, exists := JWTClaimsFromContext(r.Context())
+			if !exists {
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+
+			if !claims.HasScopes(scopes...) {
+				http.Error(w, "insufficient scopes", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
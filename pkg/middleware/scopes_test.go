@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+)
+
+func TestRequireScopes(t *testing.T) {
+
+	tests := []struct {
+		name string
+
+		// scopes granted by the JWT, in either the string or the array form.
+		claims jwt.MapClaims
+
+		// wantStatus is the status code we expect in response.
+		wantStatus int
+	}{
+		{
+			name:       "sufficient scopes in space-delimited form",
+			claims:     jwt.MapClaims{"scope": "records:read records:write profile"},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "sufficient scopes in array form",
+			claims:     jwt.MapClaims{"scopes": []string{"records:read", "records:write"}},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "sufficient scopes split across both claims",
+			claims:     jwt.MapClaims{"scope": "records:read", "scopes": []string{"records:write"}},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "insufficient scopes",
+			claims:     jwt.MapClaims{"scope": "records:read"},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "no scopes",
+			claims:     jwt.MapClaims{},
+			wantStatus: http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Sign a JWT carrying the scopes.
+			tt.claims["x-user-id"] = uuid.New().String()
+			signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, tt.claims).SignedString([]byte("secret"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Initialize test request and response recorder.
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Authorization", "Bearer "+signed)
+			w := httptest.NewRecorder()
+
+			// Serve the request.
+			Chain(
+				JWT(&JWTConfig{Key: "secret"}),
+				RequireScopes("records:read", "records:write"),
+			)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})).ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("ServeHTTP() = %v, want %v: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+		})
+	}
+
+	t.Run("request w/o jwt claims", func(t *testing.T) {
+
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		w := httptest.NewRecorder()
+
+		RequireScopes("records:read")(http.NotFoundHandler()).ServeHTTP(w, r)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("ServeHTTP() = %v, want %v", w.Code, http.StatusUnauthorized)
+		}
+	})
+}
+
+func TestHasScopes_SpareCapacity(t *testing.T) {
+
+	// The scope claim has spare capacity, like a slice grown by `append`.
+	scope := make(Scopes, 1, 2)
+	scope[0] = "records:read"
+	claims := JWTClaims{
+		Scope:  scope,
+		Scopes: Scopes{"records:write"},
+	}
+
+	if !claims.HasScopes("records:read", "records:write") {
+		t.Fatalf("HasScopes() = false, want true")
+	}
+
+	// The backing array of the claim, shared with the other readers of the claims, must be left untouched.
+	if spare := scope[:2][1]; spare != "" {
+		t.Errorf("HasScopes() wrote %q into the spare capacity of the claim", spare)
+	}
+}
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// X-Forwarded-Proto is the request header set by the TLS-terminating proxies to the protocol used by the client.
+const XForwardedProto Key = "X-Forwarded-Proto"
+
+type SecureConfig struct {
+
+	// HSTSMaxAge is the duration for which the browsers should only access the server over HTTPS.
+	// Default: `365 * 24h`
+	//
+	// This field is optional.
+	HSTSMaxAge time.Duration
+
+	// HSTSIncludeSubdomains is the flag that determines if the HSTS policy also applies to the subdomains.
+	// Default: `false`
+	//
+	// This field is optional.
+	HSTSIncludeSubdomains bool
+
+	// FrameOptions is the value of the `X-Frame-Options` header.
+	// Default: `DENY`
+	//
+	// This field is optional.
+	FrameOptions string
+
+	// ReferrerPolicy is the value of the `Referrer-Policy` header.
+	// Default: `strict-origin-when-cross-origin`
+	//
+	// This field is optional.
+	ReferrerPolicy string
+
+	// RedirectHTTPS is the flag that determines if the plain HTTP requests should be redirected to HTTPS.
+	// The protocol is read from the `X-Forwarded-Proto` header, so enable it only behind a TLS-terminating proxy.
+	// Default: `false`
+	//
+	// This field is optional.
+	RedirectHTTPS bool
+}
+
+// SecureHeaders middleware adds the security headers to the response.
+//
+// It sets the `Strict-Transport-Security`, `X-Content-Type-Options`, `X-Frame-Options` and `Referrer-Policy` headers.
+// If `RedirectHTTPS` is set, the plain HTTP requests are redirected to HTTPS with `301 Moved Permanently`
+// for GET and HEAD requests, and with `308 Permanent Redirect` for all other methods.
+func SecureHeaders(config *SecureConfig) Middleware {
+
+	// Set the default configuration.
+	if config == nil {
+		config = &SecureConfig{}
+	}
+
+	if config.HSTSMaxAge <= 0 {
+		config.HSTSMaxAge = 365 * 24 * time.Hour
+	}
+
+	if config.FrameOptions == "" {
+		config.FrameOptions = "DENY"
+	}
+
+	if config.ReferrerPolicy == "" {
+		config.ReferrerPolicy = "strict-origin-when-cross-origin"
+	}
+
+	hsts := fmt.Sprintf("max-age=%d", int64(config.HSTSMaxAge.Seconds()))
+	if config.HSTSIncludeSubdomains {
+		hsts += "; includeSubDomains"
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			if config.RedirectHTTPS && r.Header.Get(string(XForwardedProto)) == "http" {
+				status := http.StatusPermanentRedirect
+				if r.Method == http.MethodGet || r.Method == http.MethodHead {
+					status = http.StatusMovedPermanently
+				}
+				http.Redirect(w, r, "https://"+r.Host+r.URL.RequestURI(), status)
+				return
+			}
+
+			w.Header().Set("Strict-Transport-Security", hsts)
+			w.Header().Set("X-Content-Type-Options", "nosniff")
+			w.Header().Set("X-Frame-Options", config.FrameOptions)
+			w.Header().Set("Referrer-Policy", config.ReferrerPolicy)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSecureHeaders(t *testing.T) {
+
+	t.Run("set the default headers", func(t *testing.T) {
+
+		// Initialize test request and response recorder.
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		w := httptest.NewRecorder()
+
+		// Serve the request.
+		SecureHeaders(nil)(http.NotFoundHandler()).ServeHTTP(w, r)
+
+		headers := map[string]string{
+			"Strict-Transport-Security": "max-age=31536000",
+			"X-Content-Type-Options":    "nosniff",
+			"X-Frame-Options":           "DENY",
+			"Referrer-Policy":           "strict-origin-when-cross-origin",
+		}
+		for name, want := range headers {
+			if got := w.Header().Get(name); got != want {
+				t.Errorf("%s = %q, want %q", name, got, want)
+			}
+		}
+	})
+
+	t.Run("set the configured headers", func(t *testing.T) {
+
+		// Initialize test request and response recorder.
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		w := httptest.NewRecorder()
+
+		// Serve the request.
+		SecureHeaders(&SecureConfig{
+			HSTSMaxAge:            time.Hour,
+			HSTSIncludeSubdomains: true,
+			FrameOptions:          "SAMEORIGIN",
+			ReferrerPolicy:        "no-referrer",
+		})(http.NotFoundHandler()).ServeHTTP(w, r)
+
+		headers := map[string]string{
+			"Strict-Transport-Security": "max-age=3600; includeSubDomains",
+			"X-Frame-Options":           "SAMEORIGIN",
+			"Referrer-Policy":           "no-referrer",
+		}
+		for name, want := range headers {
+			if got := w.Header().Get(name); got != want {
+				t.Errorf("%s = %q, want %q", name, got, want)
+			}
+		}
+	})
+
+	tests := []struct {
+		name     string
+		method   string
+		proto    string
+		want     int
+		location string
+	}{
+		{
+			name:     "redirect GET request over HTTP",
+			method:   http.MethodGet,
+			proto:    "http",
+			want:     http.StatusMovedPermanently,
+			location: "https://example.com/v1?limit=1",
+		},
+		{
+			name:     "redirect POST request over HTTP",
+			method:   http.MethodPost,
+			proto:    "http",
+			want:     http.StatusPermanentRedirect,
+			location: "https://example.com/v1?limit=1",
+		},
+		{
+			name:   "serve request over HTTPS",
+			method: http.MethodGet,
+			proto:  "https",
+			want:   http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Initialize test request and response recorder.
+			r := httptest.NewRequest(tt.method, "http://example.com/v1?limit=1", nil)
+			r.Header.Set(string(XForwardedProto), tt.proto)
+			w := httptest.NewRecorder()
+
+			// Serve the request.
+			SecureHeaders(&SecureConfig{
+				RedirectHTTPS: true,
+			})(http.NotFoundHandler()).ServeHTTP(w, r)
+
+			if w.Code != tt.want {
+				t.Errorf("ServeHTTP() = %v, want %v", w.Code, tt.want)
+			}
+			if got := w.Header().Get("Location"); got != tt.location {
+				t.Errorf("Location = %q, want %q", got, tt.location)
+			}
+		})
+	}
+}
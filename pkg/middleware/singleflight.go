@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// recorded is the response of a handler, recorded to be replayed to all the coalesced requests.
+type recorded struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *recorded) Header() http.Header {
+	return r.header
+}
+
+func (r *recorded) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+func (r *recorded) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.body.Write(b)
+}
+
+// replay writes the recorded response to the supplied writer.
+func (r *recorded) replay(w http.ResponseWriter) {
+	for key, values := range r.header {
+		w.Header()[key] = append([]string(nil), values...)
+	}
+	status := r.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	w.Write(r.body.Bytes())
+}
+
+// Singleflight middleware coalesces the concurrent identical `GET` requests into a single call of the handler,
+// whose response is shared by all of them. It shields the database from the thundering herds on the hot records.
+//
+// The requests are identical if they're made by the same user, identified by the JWT claims or else the
+// `Authorization` header, for the same path and query, with the same `Accept` and `Accept-Language` headers.
+// It must be chained after the `JWT` middleware, so the responses are never shared across users.
+//
+// The shared call isn't cancelled when the request which started it is, since the others still wait for it.
+func Singleflight(next http.Handler) http.Handler {
+	var group singleflight.Group
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		result, _, _ := group.Do(flightKey(r), func() (any, error) {
+			response := &recorded{header: make(http.Header)}
+			next.ServeHTTP(response, r.WithContext(context.WithoutCancel(r.Context())))
+			return response, nil
+		})
+		result.(*recorded).replay(w)
+	})
+}
+
+// flightKey identifies the requests which can share a response.
+func flightKey(r *http.Request) string {
+	user := r.Header.Get("Authorization")
+	if claims, exists := JWTClaimsFromContext(r.Context()); exists {
+		user = claims.XUserID.String()
+	}
+	return strings.Join([]string{
+		user,
+		r.URL.RequestURI(),
+		r.Header.Get("Accept"),
+		r.Header.Get("Accept-Language"),
+	}, "\n")
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestSingleflight(t *testing.T) {
+
+	t.Run("coalesce concurrent identical requests", func(t *testing.T) {
+
+		// Initialize a slow handler which counts its invocations.
+		var calls atomic.Int32
+		release := make(chan struct{})
+		handler := Singleflight(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			<-release
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"title":"hot"}`))
+		}))
+
+		const requests = 10
+		ctx := WithJWTClaims(httptest.NewRequest(http.MethodGet, "/", nil).Context(), JWTClaims{
+			XUserID: uuid.New(),
+		})
+
+		var wg sync.WaitGroup
+		recorders := make([]*httptest.ResponseRecorder, requests)
+		for i := range recorders {
+			recorders[i] = httptest.NewRecorder()
+			wg.Add(1)
+			go func(w *httptest.ResponseRecorder) {
+				defer wg.Done()
+				r := httptest.NewRequest(http.MethodGet, "/v1/hot?limit=1", nil).WithContext(ctx)
+				handler.ServeHTTP(w, r)
+			}(recorders[i])
+		}
+
+		// Let all the requests join the flight before the handler returns.
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		if got := calls.Load(); got != 1 {
+			t.Errorf("expected a single invocation of the handler, got %d", got)
+		}
+		for _, w := range recorders {
+			if w.Code != http.StatusOK || w.Body.String() != `{"title":"hot"}` || w.Header().Get("Content-Type") != "application/json" {
+				t.Errorf("expected the shared response, got %d %q %v", w.Code, w.Body.String(), w.Header())
+			}
+		}
+	})
+
+	t.Run("serve requests of different users separately", func(t *testing.T) {
+
+		var calls atomic.Int32
+		handler := Singleflight(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			claims, _ := JWTClaimsFromContext(r.Context())
+			w.Write([]byte(claims.XUserID.String()))
+		}))
+
+		for i := 0; i < 2; i++ {
+			user := uuid.New()
+			r := httptest.NewRequest(http.MethodGet, "/v1/hot", nil)
+			r = r.WithContext(WithJWTClaims(r.Context(), JWTClaims{XUserID: user}))
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Body.String() != user.String() {
+				t.Errorf("expected the response of user %s, got %q", user, w.Body.String())
+			}
+		}
+		if got := calls.Load(); got != 2 {
+			t.Errorf("expected an invocation per user, got %d", got)
+		}
+	})
+
+	t.Run("pass through non-GET requests", func(t *testing.T) {
+
+		var calls atomic.Int32
+		handler := Singleflight(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+		for i := 0; i < 2; i++ {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1", nil))
+			if w.Code != http.StatusCreated {
+				t.Errorf("ServeHTTP() = %v, want %v", w.Code, http.StatusCreated)
+			}
+		}
+		if got := calls.Load(); got != 2 {
+			t.Errorf("expected an invocation per request, got %d", got)
+		}
+	})
+}
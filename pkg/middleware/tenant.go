@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ErrUnknownTenant is returned by the tenant resolvers for the slugs which don't belong to any tenant.
+var ErrUnknownTenant = errors.New("unknown tenant")
+
+// TenantResolver resolves the slug of a tenant, like `acme`, to its ID.
+type TenantResolver interface {
+
+	// ResolveTenant returns the ID of the tenant with the supplied slug,
+	// or `ErrUnknownTenant` if there's no such tenant.
+	ResolveTenant(ctx context.Context, slug string) (uuid.UUID, error)
+}
+
+// TenantResolverFunc adapts an ordinary function to the `TenantResolver` interface.
+type TenantResolverFunc func(ctx context.Context, slug string) (uuid.UUID, error)
+
+// ResolveTenant calls f(ctx, slug).
+func (f TenantResolverFunc) ResolveTenant(ctx context.Context, slug string) (uuid.UUID, error) {
+	return f(ctx, slug)
+}
+
+type TenantConfig struct {
+
+	// Resolver resolves the slugs of the tenants to their IDs.
+	//
+	// This field is mandatory.
+	Resolver TenantResolver
+
+	// Domain is the parent domain of the subdomains of the tenants, e.g. `example.com`
+	// for the tenant `acme` served at `acme.example.com`.
+	//
+	// This field is mandatory.
+	Domain string
+}
+
+// WithTenantID returns a copy of the context which carries the supplied tenant ID.
+func WithTenantID(ctx context.Context, id uuid.UUID) context.Context {
+	return context.WithValue(ctx, tenantKey, id)
+}
+
+// TenantIDFromContext returns the tenant ID stored in the context by the `Tenant` middleware, if any.
+func TenantIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	id, exists := ctx.Value(tenantKey).(uuid.UUID)
+	return id, exists
+}
+
+// Tenant middleware resolves the tenant of the request from the subdomain of its `Host` header
+// and stores its ID in the request context.
+//
+// The requests to the hosts outside the configured domain, to the bare domain itself
+// and to the subdomains of unknown tenants are rejected with `404 Not Found`.
+func Tenant(config *TenantConfig) Middleware {
+
+	// Validate the configuration.
+	if config == nil || config.Resolver == nil || config.Domain == "" {
+		panic("middleware: tenant: resolver and domain are required")
+	}
+	suffix := "." + strings.ToLower(strings.Trim(config.Domain, "."))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			slug, ok := subdomain(r.Host, suffix)
+			if !ok {
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+				return
+			}
+
+			id, err := config.Resolver.ResolveTenant(r.Context(), slug)
+			if errors.Is(err, ErrUnknownTenant) {
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+				return
+			}
+			if err != nil {
+				http.Error(w, "failed to resolve the tenant", http.StatusInternalServerError)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithTenantID(r.Context(), id)))
+		})
+	}
+}
+
+// subdomain extracts the single-label subdomain of the host under the domain with the supplied suffix,
+// ignoring the port.
+func subdomain(host, suffix string) (string, bool) {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	slug, found := strings.CutSuffix(host, suffix)
+	if !found || slug == "" || strings.Contains(slug, ".") {
+		return "", false
+	}
+	return slug, true
+}
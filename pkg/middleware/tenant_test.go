@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestTenant(t *testing.T) {
+
+	acme := uuid.New()
+	resolver := TenantResolverFunc(func(ctx context.Context, slug string) (uuid.UUID, error) {
+		switch slug {
+		case "acme":
+			return acme, nil
+		case "broken":
+			return uuid.Nil, errors.New("connection refused")
+		}
+		return uuid.Nil, ErrUnknownTenant
+	})
+
+	tests := []struct {
+		name       string
+		host       string
+		wantStatus int
+		wantTenant uuid.UUID
+	}{
+		{
+			name:       "valid subdomain",
+			host:       "acme.example.com",
+			wantStatus: http.StatusOK,
+			wantTenant: acme,
+		},
+		{
+			name:       "valid subdomain w/ port and mixed case",
+			host:       "ACME.Example.com:8080",
+			wantStatus: http.StatusOK,
+			wantTenant: acme,
+		},
+		{
+			name:       "unknown subdomain",
+			host:       "globex.example.com",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "bare domain",
+			host:       "example.com",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "nested subdomain",
+			host:       "www.acme.example.com",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "different domain",
+			host:       "acme.example.org",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "failing resolver",
+			host:       "broken.example.com",
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			var got uuid.UUID
+			handler := Tenant(&TenantConfig{
+				Resolver: resolver,
+				Domain:   "example.com",
+			})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, _ = TenantIDFromContext(r.Context())
+			}))
+
+			// Initialize test request and response recorder.
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Host = tt.host
+			w := httptest.NewRecorder()
+
+			// Serve the request.
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("ServeHTTP() = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if got != tt.wantTenant {
+				t.Errorf("TenantIDFromContext() = %v, want %v", got, tt.wantTenant)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// X-Request-Timeout is the key used to read the client's timeout from the request header.
+//
+// The timeout is a Go duration string. For example, `2s` or `500ms`.
+const XRequestTimeout Key = "X-Request-Timeout"
+
+type TimeoutConfig struct {
+
+	// Default is the timeout applied to the requests that don't declare one in the `X-Request-Timeout` header.
+	// If it is zero, such requests are not bound by a deadline.
+	// Default: `0`
+	//
+	// This field is optional.
+	Default time.Duration
+
+	// Max is the maximum timeout a client can declare in the `X-Request-Timeout` header.
+	// Longer timeouts are clamped to it.
+	// Default: `30s`
+	//
+	// This field is optional.
+	Max time.Duration
+}
+
+// Timeout middleware attaches a deadline to the request context.
+//
+// Clients can declare their own timeout in the `X-Request-Timeout` header, which is capped at the configured maximum.
+func Timeout(config *TimeoutConfig) Middleware {
+
+	// Set the default configuration.
+	if config == nil {
+		config = &TimeoutConfig{}
+	}
+
+	if config.Max <= 0 {
+		config.Max = 30 * time.Second
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			timeout := config.Default
+
+			// Read the timeout declared by the client.
+			if header := r.Header.Get(string(XRequestTimeout)); header != "" {
+				declared, err := time.ParseDuration(header)
+				if err != nil || declared <= 0 {
+					http.Error(w, "invalid request timeout", http.StatusBadRequest)
+					return
+				}
+				timeout = declared
+			}
+
+			if timeout > config.Max {
+				timeout = config.Max
+			}
+
+			if timeout > 0 {
+				ctx, cancel := context.WithTimeout(r.Context(), timeout)
+				defer cancel()
+				r = r.WithContext(ctx)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTimeout(t *testing.T) {
+
+	// Returns a handler which writes the remaining time until the deadline to the supplied variable.
+	remaining := func(got *time.Duration) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			deadline, exists := r.Context().Deadline()
+			if !exists {
+				t.Errorf("expected a deadline on the request context")
+				return
+			}
+			*got = time.Until(deadline)
+			w.WriteHeader(http.StatusOK)
+		})
+	}
+
+	t.Run("header shortens the deadline", func(t *testing.T) {
+
+		var got time.Duration
+
+		// Initialize test request and response recorder.
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set(string(XRequestTimeout), "2s")
+		w := httptest.NewRecorder()
+
+		// Serve the request.
+		Timeout(&TimeoutConfig{
+			Default: time.Minute,
+			Max:     time.Minute,
+		})(remaining(&got)).ServeHTTP(w, r)
+
+		if got <= 0 || got > 2*time.Second {
+			t.Errorf("remaining = %v, want at most %v", got, 2*time.Second)
+		}
+	})
+
+	t.Run("header over the max is clamped", func(t *testing.T) {
+
+		var got time.Duration
+
+		// Initialize test request and response recorder.
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set(string(XRequestTimeout), "1h")
+		w := httptest.NewRecorder()
+
+		// Serve the request.
+		Timeout(&TimeoutConfig{
+			Max: 5 * time.Second,
+		})(remaining(&got)).ServeHTTP(w, r)
+
+		if got <= 0 || got > 5*time.Second {
+			t.Errorf("remaining = %v, want at most %v", got, 5*time.Second)
+		}
+	})
+
+	t.Run("invalid header is rejected", func(t *testing.T) {
+
+		// Initialize test request and response recorder.
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set(string(XRequestTimeout), "soon")
+		w := httptest.NewRecorder()
+
+		// Serve the request.
+		Timeout(nil)(http.NotFoundHandler()).ServeHTTP(w, r)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("ServeHTTP() = %v, want %v", w.Code, http.StatusBadRequest)
+		}
+	})
+}
@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Server-Timing is the response header carrying the durations spent serving the request.
+//
+// Link: https://www.w3.org/TR/server-timing/
+const ServerTimingHeader Key = "Server-Timing"
+
+// timings holds the start of the request and the durations of the instrumented operations.
+//
+// It's stored in the context as a pointer, so the instrumented layers deep in the chain can add to it.
+type timings struct {
+	start time.Time
+
+	mu        sync.Mutex
+	names     []string
+	durations map[string]time.Duration
+}
+
+// RequestStartFromContext returns the time the request was received at, as recorded by the `ServerTiming` middleware.
+func RequestStartFromContext(ctx context.Context) (time.Time, bool) {
+	t, exists := ctx.Value(timingsKey).(*timings)
+	if !exists {
+		return time.Time{}, false
+	}
+	return t.start, true
+}
+
+// AddServerTiming adds the duration to the named metric of the request, for example `db`.
+//
+// The durations of the same metric are summed up.
+// It's a no-op unless the `ServerTiming` middleware wraps the handler.
+func AddServerTiming(ctx context.Context, name string, duration time.Duration) {
+	t, exists := ctx.Value(timingsKey).(*timings)
+	if !exists {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, seen := t.durations[name]; !seen {
+		t.names = append(t.names, name)
+	}
+	t.durations[name] += duration
+}
+
+// header formats the metrics and the total duration so far as a `Server-Timing` header value.
+//
+// Example: `db;dur=1.25, total;dur=3.50`
+func (t *timings) header() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	metrics := make([]string, 0, len(t.names)+1)
+	for _, name := range t.names {
+		metrics = append(metrics, metric(name, t.durations[name]))
+	}
+	metrics = append(metrics, metric("total", time.Since(t.start)))
+	return strings.Join(metrics, ", ")
+}
+
+// metric formats the duration of the metric in milliseconds.
+func metric(name string, duration time.Duration) string {
+	return name + ";dur=" + strconv.FormatFloat(float64(duration)/float64(time.Millisecond), 'f', 2, 64)
+}
+
+// timingWriter is the response writer which sets the `Server-Timing` header right before the headers are sent.
+type timingWriter struct {
+	http.ResponseWriter
+
+	//	Timings of the request.
+	timings *timings
+
+	//	Whether the headers have been sent.
+	wroteHeader bool
+}
+
+// Unwrap returns the original response writer.
+func (w *timingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *timingWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.Header().Set(string(ServerTimingHeader), w.timings.header())
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *timingWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+type ServerTimingConfig struct {
+
+	// Expose is the flag that determines if the `Server-Timing` header should be sent to the clients.
+	// The header reveals how long the internals took, so it's meant for debugging.
+	// The start of the request is recorded in the context either way.
+	// Default: `false`
+	//
+	// This field is optional.
+	Expose bool
+}
+
+// ServerTiming middleware records the start of the request in the context,
+// and sends the durations spent serving it in the `Server-Timing` header, so they show up in the browser devtools.
+//
+// The header carries the `total` duration until the response headers were sent, and the metrics added
+// by the instrumented layers with `AddServerTiming`, like the time spent in the database.
+func ServerTiming(config *ServerTimingConfig) Middleware {
+
+	// Set the default configuration.
+	if config == nil {
+		config = &ServerTimingConfig{}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t := &timings{
+				start:     time.Now(),
+				durations: map[string]time.Duration{},
+			}
+			r = r.WithContext(context.WithValue(r.Context(), timingsKey, t))
+
+			if !config.Expose {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			tw := &timingWriter{ResponseWriter: w, timings: t}
+			next.ServeHTTP(tw, r)
+
+			// Send the headers of the responses without a body.
+			if !tw.wroteHeader {
+				tw.WriteHeader(http.StatusOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestServerTiming(t *testing.T) {
+
+	t.Run("send total and instrumented durations", func(t *testing.T) {
+
+		// Initialize test request and response recorder.
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		w := httptest.NewRecorder()
+
+		// Serve the request with a handler which reports the time spent in the database.
+		ServerTiming(&ServerTimingConfig{
+			Expose: true,
+		})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			AddServerTiming(r.Context(), "db", 1500*time.Microsecond)
+			AddServerTiming(r.Context(), "db", time.Millisecond)
+			w.Write([]byte("OK"))
+		})).ServeHTTP(w, r)
+
+		header := w.Header().Get(string(ServerTimingHeader))
+		if !regexp.MustCompile(`^db;dur=2\.50, total;dur=\d+\.\d{2}$`).MatchString(header) {
+			t.Errorf("unexpected %s header %q", ServerTimingHeader, header)
+		}
+	})
+
+	t.Run("send total duration of response w/o body", func(t *testing.T) {
+
+		// Initialize test request and response recorder.
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		w := httptest.NewRecorder()
+
+		ServerTiming(&ServerTimingConfig{
+			Expose: true,
+		})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).ServeHTTP(w, r)
+
+		header := w.Header().Get(string(ServerTimingHeader))
+		if !regexp.MustCompile(`^total;dur=\d+\.\d{2}$`).MatchString(header) {
+			t.Errorf("unexpected %s header %q", ServerTimingHeader, header)
+		}
+	})
+
+	t.Run("record start w/o exposing the header", func(t *testing.T) {
+
+		// Initialize test request and response recorder.
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		w := httptest.NewRecorder()
+
+		var start time.Time
+		ServerTiming(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start, _ = RequestStartFromContext(r.Context())
+		})).ServeHTTP(w, r)
+
+		if start.IsZero() {
+			t.Errorf("expected the start of the request in the context")
+		}
+		if header := w.Header().Get(string(ServerTimingHeader)); header != "" {
+			t.Errorf("expected no %s header, got %q", ServerTimingHeader, header)
+		}
+	})
+}
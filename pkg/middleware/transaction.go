@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"gorm.io/gorm"
+)
+
+// WithTransaction returns a copy of the context which carries the supplied transaction.
+func WithTransaction(ctx context.Context, tx *gorm.DB) context.Context {
+	return context.WithValue(ctx, transactionKey, tx)
+}
+
+// TransactionFromContext returns the request-scoped transaction stored in the context by the `Transaction` middleware, if any.
+func TransactionFromContext(ctx context.Context) (*gorm.DB, bool) {
+	tx, exists := ctx.Value(transactionKey).(*gorm.DB)
+	return tx, exists
+}
+
+// transactionWriter is the response writer which ends the transaction right before the headers are sent,
+// so the client never sees a success status for changes which failed to commit.
+type transactionWriter struct {
+	http.ResponseWriter
+
+	//	Transaction of the request.
+	tx *gorm.DB
+
+	//	Whether the transaction has been committed or rolled back.
+	finished bool
+
+	//	Whether the commit failed, in which case the response of the handler is replaced.
+	failed bool
+}
+
+// Unwrap returns the original response writer.
+func (w *transactionWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// finish commits the transaction for the successful statuses and rolls it back for the others.
+func (w *transactionWriter) finish(status int) {
+	if w.finished {
+		return
+	}
+	w.finished = true
+	if status >= http.StatusBadRequest {
+		w.tx.Rollback()
+		return
+	}
+	if err := w.tx.Commit().Error; err != nil {
+		w.failed = true
+	}
+}
+
+func (w *transactionWriter) WriteHeader(status int) {
+
+	// The informational responses don't end the request.
+	if status < http.StatusOK {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	if w.failed {
+		return
+	}
+	w.finish(status)
+	if w.failed {
+		http.Error(w.ResponseWriter, "failed to commit the transaction", http.StatusInternalServerError)
+		return
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *transactionWriter) Write(b []byte) (int, error) {
+	if !w.finished {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	// Drop the body of the response which was replaced.
+	if w.failed {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Transaction is a middleware that wraps every request in a database transaction.
+//
+// The transaction is stored in the request context, and the database layer runs its queries in it,
+// so all the changes made while serving the request are atomic.
+// It's committed if the handler responds with a status below `400`, and rolled back otherwise or if the handler panics.
+// If the commit fails, the client receives `500 Internal Server Error` instead of the response of the handler.
+func Transaction(db *gorm.DB) Middleware {
+
+	// Validate the configuration.
+	if db == nil {
+		panic("failed to initialize the transaction middleware: missing database")
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tx := db.WithContext(r.Context()).Begin()
+			if tx.Error != nil {
+				http.Error(w, "failed to begin the transaction", http.StatusServiceUnavailable)
+				return
+			}
+
+			writer := &transactionWriter{
+				ResponseWriter: w,
+				tx:             tx,
+			}
+
+			// Roll back the transaction if the handler panics, and re-panic.
+			panicked := true
+			defer func() {
+				if panicked && !writer.finished {
+					writer.finished = true
+					tx.Rollback()
+				}
+			}()
+
+			next.ServeHTTP(writer, r.WithContext(WithTransaction(r.Context(), tx)))
+			panicked = false
+
+			// End the transaction if the handler didn't write anything.
+			if !writer.finished {
+				writer.WriteHeader(http.StatusOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// item is the row written by the handlers of the transaction tests.
+type item struct {
+	Name string
+}
+
+func TestTransaction(t *testing.T) {
+
+	// Open an in-memory database connection with SQLite.
+	// A single connection keeps the unshared in-memory database alive across the transactions.
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open the database connection: %v", err)
+	}
+	sqlDB, err := conn.DB()
+	if err != nil {
+		t.Fatalf("failed to get the database connection: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() {
+		sqlDB.Close()
+	})
+	if err := conn.AutoMigrate(&item{}); err != nil {
+		t.Fatalf("failed to migrate the schema: %v", err)
+	}
+
+	// handler writes a row in the request-scoped transaction and responds with the status.
+	handler := func(name string, status int) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tx, exists := TransactionFromContext(r.Context())
+			if !exists {
+				t.Fatalf("expected a transaction in the context")
+			}
+			if err := tx.Create(&item{Name: name}).Error; err != nil {
+				t.Fatalf("failed to write the row: %v", err)
+			}
+			if status == 0 {
+				panic("handler failed")
+			}
+			w.WriteHeader(status)
+		})
+	}
+
+	// exists checks whether the row was committed.
+	exists := func(name string) bool {
+		var count int64
+		if err := conn.Model(&item{}).Where("name = ?", name).Count(&count).Error; err != nil {
+			t.Fatalf("failed to count the rows: %v", err)
+		}
+		return count > 0
+	}
+
+	tests := []struct {
+		name       string
+		status     int
+		wantCommit bool
+	}{
+		{
+			name:       "commit on success",
+			status:     http.StatusCreated,
+			wantCommit: true,
+		},
+		{
+			name:       "roll back on client error",
+			status:     http.StatusUnprocessableEntity,
+			wantCommit: false,
+		},
+		{
+			name:       "roll back on server error",
+			status:     http.StatusInternalServerError,
+			wantCommit: false,
+		},
+		{
+			name:       "roll back on panic",
+			status:     0,
+			wantCommit: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Initialize test request and response recorder.
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			w := httptest.NewRecorder()
+
+			// Serve the request, recovering from the panic of the handler.
+			func() {
+				defer func() {
+					if recovered := recover(); recovered != nil && tt.status != 0 {
+						t.Fatalf("unexpected panic: %v", recovered)
+					}
+				}()
+				Transaction(conn)(handler(tt.name, tt.status)).ServeHTTP(w, r)
+			}()
+
+			if tt.status != 0 && w.Code != tt.status {
+				t.Errorf("ServeHTTP() = %v, want %v", w.Code, tt.status)
+			}
+			if got := exists(tt.name); got != tt.wantCommit {
+				t.Errorf("committed = %v, want %v", got, tt.wantCommit)
+			}
+		})
+	}
+}
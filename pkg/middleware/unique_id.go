@@ -3,75 +3,146 @@ package middleware
 import (
 	"context"
 	"net/http"
+	"strings"
 
 	"github.com/google/uuid"
 )
 
-// X-Request-ID is the key used to store the request ID in the context and the response header.
+// IDConfig is the configuration of the header written by the `RequestID`, `TraceID` and `CorrelationID` middlewares.
+type IDConfig struct {
+
+	// Header is the exact name of the header carrying the ID, for example `X-Correlation-Id`.
+	// The ID is read from the request header, if the caller or an upstream proxy sent a valid one,
+	// and written to the response header.
+	// It takes precedence over `Prefix`.
+	// Default: the header of the middleware, like `X-Request-ID`
+	//
+	// This field is optional.
+	Header Key
+
+	// Prefix replaces the `X-` prefix of the default header name.
+	// For example, the prefix `X-Acme-` makes the `RequestID` middleware write the `X-Acme-Request-ID` header.
+	// Default: `X-`
+	//
+	// This field is optional.
+	Prefix string
+}
+
+// header returns the name of the header to write the ID to, given the default one.
+func (c *IDConfig) header(fallback Key) Key {
+	switch {
+	case c == nil:
+		return fallback
+	case c.Header != "":
+		return c.Header
+	case c.Prefix != "":
+		return Key(c.Prefix + strings.TrimPrefix(string(fallback), "X-"))
+	}
+	return fallback
+}
+
+// maxIDLength is the maximum length of the IDs accepted from the request headers.
+const maxIDLength = 128
+
+// idFrom returns the ID sent in the request header, so it's kept across the hops of the request,
+// or a new UUID if the header is missing or invalid.
+//
+// The IDs end up in the logs and the response headers, so only the short ones made of the URL-safe characters are accepted.
+func idFrom(r *http.Request, header Key) string {
+	id := r.Header.Get(string(header))
+	if id == "" || len(id) > maxIDLength {
+		return uuid.New().String()
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_', c == '.', c == ':':
+		default:
+			return uuid.New().String()
+		}
+	}
+	return id
+}
+
+// X-Request-ID is the header carrying the request ID.
 //
 // The request ID is used to uniquely identify the request.
 const XRequestID Key = "X-Request-ID"
 
-// RequestID middleware adds a unique UUID to the request context and response headers.
-func RequestID(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ctx := r.Context()
-		id := uuid.New().String()
+// RequestID middleware adds the request ID to the request context and response headers.
+// The ID sent in the request header is kept, otherwise a unique UUID is generated.
+func RequestID(config *IDConfig) Middleware {
+	header := config.header(XRequestID)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			id := idFrom(r, header)
 
-		// Add the request ID to the request context.
-		ctx = context.WithValue(ctx, XRequestID, id)
+			// Add the request ID to the request context.
+			ctx = context.WithValue(ctx, requestIDKey, id)
 
-		// Update the request with the new context.
-		r = r.WithContext(ctx)
+			// Update the request with the new context.
+			r = r.WithContext(ctx)
 
-		// Add the request ID to the response headers.
-		w.Header().Set(string(XRequestID), id)
-		next.ServeHTTP(w, r)
-	})
+			// Add the request ID to the response headers.
+			w.Header().Set(string(header), id)
+			next.ServeHTTP(w, r)
+		})
+	}
 }
 
-// X-Trace-ID is the key used to store the trace ID in the context and the response header.
+// X-Trace-ID is the header carrying the trace ID.
 //
 // The trace ID is used to trace the request through multiple services.
 const XTraceID Key = "X-Trace-ID"
 
-// TraceID middleware adds a unique UUID to the request context and response headers.
-func TraceID(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ctx := r.Context()
-		id := uuid.New().String()
+// TraceID middleware adds the trace ID to the request context and response headers.
+// The ID sent in the request header is kept, otherwise a unique UUID is generated.
+func TraceID(config *IDConfig) Middleware {
+	header := config.header(XTraceID)
 
-		// Add the trace ID to the request context.
-		ctx = context.WithValue(ctx, XTraceID, id)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			id := idFrom(r, header)
 
-		// Update the request with the new context.
-		r = r.WithContext(ctx)
+			// Add the trace ID to the request context.
+			ctx = context.WithValue(ctx, traceIDKey, id)
 
-		// Add the trace ID to the response headers.
-		w.Header().Set(string(XTraceID), id)
-		next.ServeHTTP(w, r)
-	})
+			// Update the request with the new context.
+			r = r.WithContext(ctx)
+
+			// Add the trace ID to the response headers.
+			w.Header().Set(string(header), id)
+			next.ServeHTTP(w, r)
+		})
+	}
 }
 
-// X-Correlation-ID is the key used to store the correlation ID in the context and the response header.
+// X-Correlation-ID is the header carrying the correlation ID.
 //
 // The correlation ID is used to correlate the request with other requests.
 const XCorrelationID Key = "X-Correlation-ID"
 
-// CorrelationID middleware adds a unique UUID to the request context and response headers.
-func CorrelationID(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ctx := r.Context()
-		id := uuid.New().String()
+// CorrelationID middleware adds the correlation ID to the request context and response headers.
+// The ID sent in the request header is kept, otherwise a unique UUID is generated.
+func CorrelationID(config *IDConfig) Middleware {
+	header := config.header(XCorrelationID)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			id := idFrom(r, header)
 
-		// Add the correlation ID to the request context.
-		ctx = context.WithValue(ctx, XCorrelationID, id)
+			// Add the correlation ID to the request context.
+			ctx = context.WithValue(ctx, correlationIDKey, id)
 
-		// Update the request with the new context.
-		r = r.WithContext(ctx)
+			// Update the request with the new context.
+			r = r.WithContext(ctx)
 
-		// Add the correlation ID to the response headers.
-		w.Header().Set(string(XCorrelationID), id)
-		next.ServeHTTP(w, r)
-	})
+			// Add the correlation ID to the response headers.
+			w.Header().Set(string(header), id)
+			next.ServeHTTP(w, r)
+		})
+	}
 }
@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestIDs(t *testing.T) {
+
+	// Initialize a dummy handler which echoes the correlation ID it received in the context.
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, _ := CorrelationIDFromContext(r.Context())
+		w.Write([]byte(id))
+	})
+
+	tests := []struct {
+		name       string
+		middleware Middleware
+		wantHeader string
+	}{
+		{
+			name:       "write default request id header",
+			middleware: RequestID(nil),
+			wantHeader: "X-Request-ID",
+		},
+		{
+			name: "write request id header w/ prefix",
+			middleware: RequestID(&IDConfig{
+				Prefix: "X-Acme-",
+			}),
+			wantHeader: "X-Acme-Request-ID",
+		},
+		{
+			name: "write trace id header w/ exact name",
+			middleware: TraceID(&IDConfig{
+				Header: "Traceparent-Id",
+				Prefix: "X-Acme-",
+			}),
+			wantHeader: "Traceparent-Id",
+		},
+		{
+			name: "write correlation id header w/ exact name",
+			middleware: CorrelationID(&IDConfig{
+				Header: "X-Correlation-Id",
+			}),
+			wantHeader: "X-Correlation-Id",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Initialize test request and response recorder.
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			w := httptest.NewRecorder()
+
+			// Serve the request.
+			tt.middleware(handler).ServeHTTP(w, r)
+
+			if w.Header().Get(tt.wantHeader) == "" {
+				t.Errorf("expected the %s header, got %v", tt.wantHeader, w.Header())
+			}
+
+			// The default headers are replaced, not duplicated.
+			for _, header := range []Key{XRequestID, XTraceID, XCorrelationID} {
+				if http.CanonicalHeaderKey(string(header)) != http.CanonicalHeaderKey(tt.wantHeader) && w.Header().Get(string(header)) != "" {
+					t.Errorf("expected no %s header, got %v", header, w.Header())
+				}
+			}
+		})
+	}
+
+	t.Run("store correlation id w/ custom header in context", func(t *testing.T) {
+
+		// Initialize test request and response recorder.
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		w := httptest.NewRecorder()
+
+		// Serve the request.
+		CorrelationID(&IDConfig{
+			Header: "X-Correlation-Id",
+		})(handler).ServeHTTP(w, r)
+
+		if id := w.Header().Get("X-Correlation-Id"); id == "" || id != w.Body.String() {
+			t.Errorf("expected the header %q to match the id in the context %q", id, w.Body.String())
+		}
+	})
+
+	t.Run("keep the correlation id sent by the caller", func(t *testing.T) {
+
+		tests := []struct {
+			name string
+			sent string
+			kept bool
+		}{
+			{
+				name: "valid id",
+				sent: "upstream-7f3a:1",
+				kept: true,
+			},
+			{
+				name: "id w/ invalid characters",
+				sent: "evil\r\nid <script>",
+			},
+			{
+				name: "id over the maximum length",
+				sent: strings.Repeat("a", maxIDLength+1),
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+
+				// Initialize test request and response recorder.
+				r := httptest.NewRequest(http.MethodGet, "/", nil)
+				r.Header.Set("X-Correlation-Id", tt.sent)
+				w := httptest.NewRecorder()
+
+				// Serve the request.
+				CorrelationID(&IDConfig{
+					Header: "X-Correlation-Id",
+				})(handler).ServeHTTP(w, r)
+
+				id := w.Header().Get("X-Correlation-Id")
+				if kept := id == tt.sent; kept != tt.kept {
+					t.Errorf("X-Correlation-Id = %q, want the sent id kept: %v", id, tt.kept)
+				}
+				if id == "" || id != w.Body.String() {
+					t.Errorf("expected the header %q to match the id in the context %q", id, w.Body.String())
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,248 @@
+package openapi
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+// Version is the version of the OpenAPI specification that is generated.
+const Version = "3.0.3"
+
+// Document is the root object of an OpenAPI document.
+//
+// Link: https://spec.openapis.org/oas/v3.0.3#openapi-object
+type Document struct {
+	OpenAPI    string               `yaml:"openapi"`
+	Info       Info                 `yaml:"info"`
+	Servers    []Server             `yaml:"servers,omitempty"`
+	Paths      map[string]*PathItem `yaml:"paths"`
+	Components Components           `yaml:"components,omitempty"`
+}
+
+// Info provides the metadata about the API.
+type Info struct {
+	Title   string `yaml:"title"`
+	Version string `yaml:"version"`
+}
+
+// Server represents a server hosting the API.
+type Server struct {
+	URL string `yaml:"url"`
+}
+
+// PathItem holds the operations available on a single path, keyed by the lowercase HTTP method.
+type PathItem map[string]*Operation
+
+// Operation describes a single API operation on a path.
+type Operation struct {
+	Summary     string               `yaml:"summary,omitempty"`
+	OperationID string               `yaml:"operationId,omitempty"`
+	Parameters  []*Parameter         `yaml:"parameters,omitempty"`
+	RequestBody *RequestBody         `yaml:"requestBody,omitempty"`
+	Responses   map[string]*Response `yaml:"responses"`
+}
+
+// Parameter describes a single operation parameter.
+type Parameter struct {
+	Name     string  `yaml:"name"`
+	In       string  `yaml:"in"`
+	Required bool    `yaml:"required,omitempty"`
+	Schema   *Schema `yaml:"schema"`
+}
+
+// RequestBody describes a single request body.
+type RequestBody struct {
+	Required bool                  `yaml:"required,omitempty"`
+	Content  map[string]*MediaType `yaml:"content"`
+}
+
+// Response describes a single response from an API operation.
+type Response struct {
+	Description string                `yaml:"description"`
+	Content     map[string]*MediaType `yaml:"content,omitempty"`
+}
+
+// MediaType provides the schema for the media type identified by its key.
+type MediaType struct {
+	Schema *Schema `yaml:"schema"`
+}
+
+// Components holds the reusable objects of the document.
+type Components struct {
+	Schemas map[string]*Schema `yaml:"schemas,omitempty"`
+}
+
+// Schema describes a data type.
+type Schema struct {
+	Ref        string             `yaml:"$ref,omitempty"`
+	Type       string             `yaml:"type,omitempty"`
+	Format     string             `yaml:"format,omitempty"`
+	Nullable   bool               `yaml:"nullable,omitempty"`
+	Properties map[string]*Schema `yaml:"properties,omitempty"`
+	Items      *Schema            `yaml:"items,omitempty"`
+}
+
+// New creates a new, empty OpenAPI document.
+func New(title, version string) *Document {
+	return &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:   title,
+			Version: version,
+		},
+		Paths: make(map[string]*PathItem),
+		Components: Components{
+			Schemas: make(map[string]*Schema),
+		},
+	}
+}
+
+// AddOperation adds the operation to the document against the supplied method and path.
+func (d *Document) AddOperation(method, path string, operation *Operation) {
+	item, exists := d.Paths[path]
+	if !exists {
+		item = &PathItem{}
+		d.Paths[path] = item
+	}
+	(*item)[strings.ToLower(method)] = operation
+}
+
+// Schema returns the schema of the supplied value.
+//
+// Named structs are registered as reusable components and referenced from the returned schema.
+func (d *Document) Schema(v any) *Schema {
+	if v == nil {
+		return nil
+	}
+	return d.schemaOf(reflect.TypeOf(v))
+}
+
+// QueryParameters returns the query parameters declared by the `query` tags of the supplied struct.
+func (d *Document) QueryParameters(v any) []*Parameter {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var parameters []*Parameter
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := field.Tag.Get("query")
+		if name == "" || name == "-" || !field.IsExported() {
+			continue
+		}
+		parameters = append(parameters, &Parameter{
+			Name:   name,
+			In:     "query",
+			Schema: d.schemaOf(field.Type),
+		})
+	}
+	return parameters
+}
+
+// YAML encodes the document into YAML.
+func (d *Document) YAML() ([]byte, error) {
+	var buffer bytes.Buffer
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(d); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	uuidType      = reflect.TypeOf(uuid.UUID{})
+	deletedAtType = reflect.TypeOf(gorm.DeletedAt{})
+)
+
+// schemaOf returns the schema of the supplied type.
+func (d *Document) schemaOf(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case deletedAtType:
+		return &Schema{Type: "string", Format: "date-time", Nullable: true}
+	case uuidType:
+		return &Schema{Type: "string", Format: "uuid"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: d.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object"}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return d.structSchema(t)
+		}
+		if _, exists := d.Components.Schemas[t.Name()]; !exists {
+
+			// Reserve the name before walking the fields to support recursive types.
+			d.Components.Schemas[t.Name()] = &Schema{}
+			d.Components.Schemas[t.Name()] = d.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + t.Name()}
+	}
+	return &Schema{}
+}
+
+// structSchema returns the inline object schema of the supplied struct type.
+func (d *Document) structSchema(t reflect.Type) *Schema {
+	schema := Schema{
+		Type:       "object",
+		Properties: make(map[string]*Schema),
+	}
+	d.addProperties(&schema, t)
+	return &schema
+}
+
+// addProperties adds the JSON-visible fields of the struct type to the schema.
+func (d *Document) addProperties(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		// Flatten the embedded structs the same way `encoding/json` does.
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			d.addProperties(schema, field.Type)
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		schema.Properties[name] = d.schemaOf(field.Type)
+	}
+}
@@ -0,0 +1,106 @@
+// Package server builds the HTTP server of the service from its configuration.
+package server
+
+import (
+	"log"
+	"net/http"
+	"time"
+)
+
+// Default timeouts of the server.
+//
+// The write timeout cuts the connections of the handlers which take too long to respond.
+// If the `Timeout` middleware is mounted, keep its maximum below it, so the handlers time out before their connection is cut.
+const (
+	DefaultReadHeaderTimeout = 5 * time.Second
+	DefaultReadTimeout       = 30 * time.Second
+	DefaultWriteTimeout      = 60 * time.Second
+	DefaultIdleTimeout       = 120 * time.Second
+)
+
+type Config struct {
+
+	// Addr is the TCP address the server listens on.
+	// Default: `:8080`
+	//
+	// This field is optional.
+	Addr string
+
+	// Handler is the handler which serves the requests.
+	//
+	// This field is mandatory.
+	Handler http.Handler
+
+	// ErrorLog is the logger of the errors of the server, like the failed TLS handshakes.
+	// Default: nil, which logs to the standard logger.
+	//
+	// This field is optional.
+	ErrorLog *log.Logger
+
+	// ReadHeaderTimeout is the time allowed to read the request headers.
+	// It protects the server against the slow-loris attacks, which keep connections open by trickling the headers.
+	// Default: `DefaultReadHeaderTimeout`
+	//
+	// This field is optional.
+	ReadHeaderTimeout time.Duration
+
+	// ReadTimeout is the time allowed to read the entire request, including the body.
+	// Default: `DefaultReadTimeout`
+	//
+	// This field is optional.
+	ReadTimeout time.Duration
+
+	// WriteTimeout is the time allowed from the end of the request headers to the end of the response.
+	// Default: `DefaultWriteTimeout`
+	//
+	// This field is optional.
+	WriteTimeout time.Duration
+
+	// IdleTimeout is the time an idle keep-alive connection is kept open for.
+	// Default: `DefaultIdleTimeout`
+	//
+	// This field is optional.
+	IdleTimeout time.Duration
+}
+
+// New creates the HTTP server described by the configuration.
+//
+// Unlike the zero `http.Server`, it never waits on the clients indefinitely.
+func New(config *Config) *http.Server {
+
+	// Validate the configuration.
+	if config == nil || config.Handler == nil {
+		panic("server: handler is required")
+	}
+
+	// Set the default configuration.
+	if config.Addr == "" {
+		config.Addr = ":8080"
+	}
+
+	if config.ReadHeaderTimeout <= 0 {
+		config.ReadHeaderTimeout = DefaultReadHeaderTimeout
+	}
+
+	if config.ReadTimeout <= 0 {
+		config.ReadTimeout = DefaultReadTimeout
+	}
+
+	if config.WriteTimeout <= 0 {
+		config.WriteTimeout = DefaultWriteTimeout
+	}
+
+	if config.IdleTimeout <= 0 {
+		config.IdleTimeout = DefaultIdleTimeout
+	}
+
+	return &http.Server{
+		Addr:              config.Addr,
+		Handler:           config.Handler,
+		ErrorLog:          config.ErrorLog,
+		ReadHeaderTimeout: config.ReadHeaderTimeout,
+		ReadTimeout:       config.ReadTimeout,
+		WriteTimeout:      config.WriteTimeout,
+		IdleTimeout:       config.IdleTimeout,
+	}
+}
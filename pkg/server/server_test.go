@@ -0,0 +1,93 @@
+package server
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+
+	t.Run("build server w/ default timeouts", func(t *testing.T) {
+
+		server := New(&Config{
+			Handler: http.NotFoundHandler(),
+		})
+
+		if server.Addr != ":8080" {
+			t.Errorf("Addr = %q, want %q", server.Addr, ":8080")
+		}
+		if server.ReadHeaderTimeout != DefaultReadHeaderTimeout {
+			t.Errorf("ReadHeaderTimeout = %v, want %v", server.ReadHeaderTimeout, DefaultReadHeaderTimeout)
+		}
+		if server.ReadTimeout != DefaultReadTimeout {
+			t.Errorf("ReadTimeout = %v, want %v", server.ReadTimeout, DefaultReadTimeout)
+		}
+		if server.WriteTimeout != DefaultWriteTimeout {
+			t.Errorf("WriteTimeout = %v, want %v", server.WriteTimeout, DefaultWriteTimeout)
+		}
+		if server.IdleTimeout != DefaultIdleTimeout {
+			t.Errorf("IdleTimeout = %v, want %v", server.IdleTimeout, DefaultIdleTimeout)
+		}
+	})
+
+	t.Run("build server w/ configured timeouts", func(t *testing.T) {
+
+		server := New(&Config{
+			Handler:           http.NotFoundHandler(),
+			ReadHeaderTimeout: time.Second,
+			ReadTimeout:       2 * time.Second,
+			WriteTimeout:      3 * time.Second,
+			IdleTimeout:       4 * time.Second,
+		})
+
+		if server.ReadHeaderTimeout != time.Second ||
+			server.ReadTimeout != 2*time.Second ||
+			server.WriteTimeout != 3*time.Second ||
+			server.IdleTimeout != 4*time.Second {
+			t.Errorf("unexpected timeouts: %v, %v, %v, %v", server.ReadHeaderTimeout, server.ReadTimeout, server.WriteTimeout, server.IdleTimeout)
+		}
+	})
+
+	t.Run("close connection trickling the headers", func(t *testing.T) {
+
+		// Start the server with a short header timeout.
+		ts := httptest.NewUnstartedServer(http.NotFoundHandler())
+		ts.Config = New(&Config{
+			Handler:           http.NotFoundHandler(),
+			ReadHeaderTimeout: 100 * time.Millisecond,
+		})
+		ts.Start()
+		defer ts.Close()
+
+		conn, err := net.Dial("tcp", ts.Listener.Addr().String())
+		if err != nil {
+			t.Fatalf("failed to dial the server: %v", err)
+		}
+		defer conn.Close()
+
+		// Send an incomplete request and wait for the server to give up on it.
+		if _, err := conn.Write([]byte("GET / HTTP/1.1\r\nHost: localhost\r\n")); err != nil {
+			t.Fatalf("failed to write the request: %v", err)
+		}
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		if _, err := bufio.NewReader(conn).ReadByte(); err == nil {
+			t.Fatalf("expected the connection to be closed")
+		} else if ne, ok := err.(net.Error); ok && ne.Timeout() {
+			t.Fatalf("expected the server to close the connection before the client deadline")
+		}
+	})
+
+	t.Run("build server w/o handler", func(t *testing.T) {
+
+		defer func() {
+			if recover() == nil {
+				t.Errorf("expected New() to panic")
+			}
+		}()
+		New(&Config{})
+	})
+}
@@ -0,0 +1,44 @@
+package version
+
+import (
+	"encoding/json"
+	"net/http"
+	"runtime"
+)
+
+// The build information injected at build time with `-ldflags`.
+//
+// Example: go build -ldflags "-X github.com/mrinalwahal/boilerplate/pkg/version.Commit=$(git rev-parse HEAD) -X github.com/mrinalwahal/boilerplate/pkg/version.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd/main
+var (
+	Service   = "records"
+	Commit    = "unknown"
+	BuildTime = "unknown"
+)
+
+// BuildInfo describes the build of the running service.
+type BuildInfo struct {
+	Service   string `json:"service"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// Current returns the build information injected at build time.
+func Current() BuildInfo {
+	return BuildInfo{
+		Service:   Service,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+}
+
+// Handler returns the handler which responds with the supplied build information.
+//
+// It's useful to confirm the version of a deployment. For example, `GET /version`.
+func Handler(info BuildInfo) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(info)
+	})
+}
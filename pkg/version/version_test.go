@@ -0,0 +1,40 @@
+package version
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+
+	// Inject the build information, like `-ldflags` does.
+	Commit = "4b825dc"
+	t.Cleanup(func() {
+		Commit = "unknown"
+	})
+
+	// Initialize test request and response recorder.
+	r := httptest.NewRequest(http.MethodGet, "/version", nil)
+	w := httptest.NewRecorder()
+
+	Handler(Current()).ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("ServeHTTP() = %v, want %v", w.Code, http.StatusOK)
+	}
+
+	var info BuildInfo
+	if err := json.Unmarshal(w.Body.Bytes(), &info); err != nil {
+		t.Fatalf("failed to decode the response: %v", err)
+	}
+
+	if info.Commit != "4b825dc" {
+		t.Errorf("commit = %q, want %q", info.Commit, "4b825dc")
+	}
+	if info.GoVersion != runtime.Version() {
+		t.Errorf("go version = %q, want %q", info.GoVersion, runtime.Version())
+	}
+}
@@ -3,6 +3,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/google/uuid"
 	"github.com/mrinalwahal/boilerplate/model"
@@ -11,8 +12,85 @@ import (
 // DB interface declares the signature of the database layer.
 type DB interface {
 	Create(context.Context, *CreateOptions) (*model.Record, error)
+
+	// List fetches a list of records.
+	// If partial results are enabled and some of the rows fail to scan, the successfully scanned records
+	// are returned along with an error wrapping `ErrPartialResults`.
 	List(context.Context, *ListOptions) ([]*model.Record, error)
+
+	// ListChan streams the records matching the options on the first channel, without loading them all in memory.
+	// Both channels are closed once the rows are exhausted, the query fails or the context is cancelled.
+	// The error channel carries at most one error; read it once the records channel is closed.
+	ListChan(context.Context, *ListOptions) (<-chan *model.Record, <-chan error)
 	Get(context.Context, uuid.UUID) (*model.Record, error)
+
+	// GetByIDs fetches the records with the supplied IDs in a single query, keyed by their ID.
+	// It applies the Row Level Security (RLS) checks; the missing and the unowned records are absent from the map.
+	GetByIDs(context.Context, []uuid.UUID) (map[uuid.UUID]*model.Record, error)
+
+	// Exists reports whether the record exists, without fetching it.
+	// It applies the Row Level Security (RLS) checks, so a record owned by another user doesn't exist.
+	Exists(context.Context, uuid.UUID) (bool, error)
+
+	// GetIncludingDeleted fetches a record even if it's soft-deleted, so a restore flow can fetch it first.
+	GetIncludingDeleted(context.Context, uuid.UUID) (*model.Record, error)
 	Update(context.Context, uuid.UUID, *UpdateOptions) (*model.Record, error)
+
+	// UpdateResult updates a record like `Update`, and reports the number of the updated rows along with it.
+	UpdateResult(context.Context, uuid.UUID, *UpdateOptions) (*model.Result, error)
 	Delete(context.Context, uuid.UUID) error
+
+	// DeleteResult deletes a record like `Delete`, and reports the number of the deleted rows.
+	DeleteResult(context.Context, uuid.UUID) (*model.Result, error)
+
+	// Touch bumps the `updated_at` timestamp of a record, without changing any other field.
+	// It applies the Row Level Security (RLS) checks, and fails with `ErrNoRowsAffected` if the record doesn't exist.
+	Touch(context.Context, uuid.UUID) error
+
+	// Reassign moves the record to another user.
+	// It has no Row Level Security (RLS) checks, so the callers must make sure the requester is allowed to reassign it.
+	Reassign(ctx context.Context, ID uuid.UUID, userID uuid.UUID) (*model.Record, error)
+	Count(context.Context, *CountOptions) (int64, error)
+
+	// CountDeleted counts the soft-deleted records, the ones in the trash.
+	// It applies the Row Level Security (RLS) checks, so only the requester's records are counted.
+	CountDeleted(context.Context) (int64, error)
+
+	// DistinctTitles fetches the distinct titles starting with the prefix, in alphabetical order.
+	// It powers the type-ahead of the search box without fetching the rows.
+	DistinctTitles(ctx context.Context, prefix string, limit int) ([]string, error)
+
+	// Aggregate counts the records per group, ordered by the key of the group.
+	Aggregate(context.Context, *AggregateOptions) ([]*model.Group, error)
+
+	// Upsert creates a record, or updates the description of the one with the same natural key, `(user_id, title)`.
+	// It reports whether the record was created.
+	Upsert(context.Context, *CreateOptions) (*model.Record, bool, error)
+
+	// CreateIfNotExists creates a record, or returns the one with the same natural key, `(user_id, title)`, untouched.
+	// It reports whether the record was created.
+	CreateIfNotExists(context.Context, *CreateOptions) (*model.Record, bool, error)
+
+	// Import creates a record with the supplied timestamps instead of generated ones.
+	// It's meant for importing historical data.
+	Import(context.Context, *ImportOptions) (*model.Record, error)
+
+	// CreateAuditLog appends an entry to the audit trail.
+	// Call it with the transactional database layer, so the entry is rolled back with the mutation it records.
+	CreateAuditLog(context.Context, *CreateAuditLogOptions) (*model.AuditLog, error)
+
+	// ListAuditLogs fetches the entries of the audit trail, the most recent first.
+	ListAuditLogs(context.Context, *ListAuditLogsOptions) ([]*model.AuditLog, error)
+
+	// WithTransaction runs the supplied function inside a transaction.
+	// The transaction is committed if the function returns nil, and rolled back otherwise.
+	//
+	// The optional `sql.TxOptions` control the isolation level of the transaction.
+	// Databases which don't support the requested isolation level, like SQLite, ignore it.
+	WithTransaction(context.Context, func(DB) error, ...*sql.TxOptions) error
+
+	// WithNestedTransaction runs the supplied function inside a savepoint when already inside a transaction.
+	// If the function fails, only the changes made since the savepoint are rolled back.
+	// Outside a transaction, it behaves like `WithTransaction`.
+	WithNestedTransaction(context.Context, func(DB) error) error
 }
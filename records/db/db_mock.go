@@ -11,6 +11,7 @@ package db
 
 import (
 	context "context"
+	sql "database/sql"
 	reflect "reflect"
 
 	uuid "github.com/google/uuid"
@@ -41,6 +42,51 @@ func (m *MockDB) EXPECT() *MockDBMockRecorder {
 	return m.recorder
 }
 
+// Aggregate mocks base method.
+func (m *MockDB) Aggregate(arg0 context.Context, arg1 *AggregateOptions) ([]*model.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Aggregate", arg0, arg1)
+	ret0, _ := ret[0].([]*model.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Aggregate indicates an expected call of Aggregate.
+func (mr *MockDBMockRecorder) Aggregate(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Aggregate", reflect.TypeOf((*MockDB)(nil).Aggregate), arg0, arg1)
+}
+
+// Count mocks base method.
+func (m *MockDB) Count(arg0 context.Context, arg1 *CountOptions) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockDBMockRecorder) Count(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockDB)(nil).Count), arg0, arg1)
+}
+
+// CountDeleted mocks base method.
+func (m *MockDB) CountDeleted(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountDeleted", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountDeleted indicates an expected call of CountDeleted.
+func (mr *MockDBMockRecorder) CountDeleted(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountDeleted", reflect.TypeOf((*MockDB)(nil).CountDeleted), arg0)
+}
+
 // Create mocks base method.
 func (m *MockDB) Create(arg0 context.Context, arg1 *CreateOptions) (*model.Record, error) {
 	m.ctrl.T.Helper()
@@ -56,6 +102,37 @@ func (mr *MockDBMockRecorder) Create(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDB)(nil).Create), arg0, arg1)
 }
 
+// CreateAuditLog mocks base method.
+func (m *MockDB) CreateAuditLog(arg0 context.Context, arg1 *CreateAuditLogOptions) (*model.AuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuditLog", arg0, arg1)
+	ret0, _ := ret[0].(*model.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuditLog indicates an expected call of CreateAuditLog.
+func (mr *MockDBMockRecorder) CreateAuditLog(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuditLog", reflect.TypeOf((*MockDB)(nil).CreateAuditLog), arg0, arg1)
+}
+
+// CreateIfNotExists mocks base method.
+func (m *MockDB) CreateIfNotExists(arg0 context.Context, arg1 *CreateOptions) (*model.Record, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIfNotExists", arg0, arg1)
+	ret0, _ := ret[0].(*model.Record)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateIfNotExists indicates an expected call of CreateIfNotExists.
+func (mr *MockDBMockRecorder) CreateIfNotExists(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIfNotExists", reflect.TypeOf((*MockDB)(nil).CreateIfNotExists), arg0, arg1)
+}
+
 // Delete mocks base method.
 func (m *MockDB) Delete(arg0 context.Context, arg1 uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -70,6 +147,51 @@ func (mr *MockDBMockRecorder) Delete(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDB)(nil).Delete), arg0, arg1)
 }
 
+// DeleteResult mocks base method.
+func (m *MockDB) DeleteResult(arg0 context.Context, arg1 uuid.UUID) (*model.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteResult", arg0, arg1)
+	ret0, _ := ret[0].(*model.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteResult indicates an expected call of DeleteResult.
+func (mr *MockDBMockRecorder) DeleteResult(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteResult", reflect.TypeOf((*MockDB)(nil).DeleteResult), arg0, arg1)
+}
+
+// DistinctTitles mocks base method.
+func (m *MockDB) DistinctTitles(ctx context.Context, prefix string, limit int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DistinctTitles", ctx, prefix, limit)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DistinctTitles indicates an expected call of DistinctTitles.
+func (mr *MockDBMockRecorder) DistinctTitles(ctx, prefix, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DistinctTitles", reflect.TypeOf((*MockDB)(nil).DistinctTitles), ctx, prefix, limit)
+}
+
+// Exists mocks base method.
+func (m *MockDB) Exists(arg0 context.Context, arg1 uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exists", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exists indicates an expected call of Exists.
+func (mr *MockDBMockRecorder) Exists(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockDB)(nil).Exists), arg0, arg1)
+}
+
 // Get mocks base method.
 func (m *MockDB) Get(arg0 context.Context, arg1 uuid.UUID) (*model.Record, error) {
 	m.ctrl.T.Helper()
@@ -85,6 +207,51 @@ func (mr *MockDBMockRecorder) Get(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockDB)(nil).Get), arg0, arg1)
 }
 
+// GetByIDs mocks base method.
+func (m *MockDB) GetByIDs(arg0 context.Context, arg1 []uuid.UUID) (map[uuid.UUID]*model.Record, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIDs", arg0, arg1)
+	ret0, _ := ret[0].(map[uuid.UUID]*model.Record)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIDs indicates an expected call of GetByIDs.
+func (mr *MockDBMockRecorder) GetByIDs(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDs", reflect.TypeOf((*MockDB)(nil).GetByIDs), arg0, arg1)
+}
+
+// GetIncludingDeleted mocks base method.
+func (m *MockDB) GetIncludingDeleted(arg0 context.Context, arg1 uuid.UUID) (*model.Record, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncludingDeleted", arg0, arg1)
+	ret0, _ := ret[0].(*model.Record)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncludingDeleted indicates an expected call of GetIncludingDeleted.
+func (mr *MockDBMockRecorder) GetIncludingDeleted(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncludingDeleted", reflect.TypeOf((*MockDB)(nil).GetIncludingDeleted), arg0, arg1)
+}
+
+// Import mocks base method.
+func (m *MockDB) Import(arg0 context.Context, arg1 *ImportOptions) (*model.Record, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", arg0, arg1)
+	ret0, _ := ret[0].(*model.Record)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockDBMockRecorder) Import(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockDB)(nil).Import), arg0, arg1)
+}
+
 // List mocks base method.
 func (m *MockDB) List(arg0 context.Context, arg1 *ListOptions) ([]*model.Record, error) {
 	m.ctrl.T.Helper()
@@ -100,6 +267,65 @@ func (mr *MockDBMockRecorder) List(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockDB)(nil).List), arg0, arg1)
 }
 
+// ListAuditLogs mocks base method.
+func (m *MockDB) ListAuditLogs(arg0 context.Context, arg1 *ListAuditLogsOptions) ([]*model.AuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditLogs", arg0, arg1)
+	ret0, _ := ret[0].([]*model.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditLogs indicates an expected call of ListAuditLogs.
+func (mr *MockDBMockRecorder) ListAuditLogs(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditLogs", reflect.TypeOf((*MockDB)(nil).ListAuditLogs), arg0, arg1)
+}
+
+// ListChan mocks base method.
+func (m *MockDB) ListChan(arg0 context.Context, arg1 *ListOptions) (<-chan *model.Record, <-chan error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChan", arg0, arg1)
+	ret0, _ := ret[0].(<-chan *model.Record)
+	ret1, _ := ret[1].(<-chan error)
+	return ret0, ret1
+}
+
+// ListChan indicates an expected call of ListChan.
+func (mr *MockDBMockRecorder) ListChan(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChan", reflect.TypeOf((*MockDB)(nil).ListChan), arg0, arg1)
+}
+
+// Reassign mocks base method.
+func (m *MockDB) Reassign(ctx context.Context, ID, userID uuid.UUID) (*model.Record, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reassign", ctx, ID, userID)
+	ret0, _ := ret[0].(*model.Record)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reassign indicates an expected call of Reassign.
+func (mr *MockDBMockRecorder) Reassign(ctx, ID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reassign", reflect.TypeOf((*MockDB)(nil).Reassign), ctx, ID, userID)
+}
+
+// Touch mocks base method.
+func (m *MockDB) Touch(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Touch", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Touch indicates an expected call of Touch.
+func (mr *MockDBMockRecorder) Touch(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Touch", reflect.TypeOf((*MockDB)(nil).Touch), arg0, arg1)
+}
+
 // Update mocks base method.
 func (m *MockDB) Update(arg0 context.Context, arg1 uuid.UUID, arg2 *UpdateOptions) (*model.Record, error) {
 	m.ctrl.T.Helper()
@@ -114,3 +340,67 @@ func (mr *MockDBMockRecorder) Update(arg0, arg1, arg2 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDB)(nil).Update), arg0, arg1, arg2)
 }
+
+// UpdateResult mocks base method.
+func (m *MockDB) UpdateResult(arg0 context.Context, arg1 uuid.UUID, arg2 *UpdateOptions) (*model.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateResult", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateResult indicates an expected call of UpdateResult.
+func (mr *MockDBMockRecorder) UpdateResult(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateResult", reflect.TypeOf((*MockDB)(nil).UpdateResult), arg0, arg1, arg2)
+}
+
+// Upsert mocks base method.
+func (m *MockDB) Upsert(arg0 context.Context, arg1 *CreateOptions) (*model.Record, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", arg0, arg1)
+	ret0, _ := ret[0].(*model.Record)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockDBMockRecorder) Upsert(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockDB)(nil).Upsert), arg0, arg1)
+}
+
+// WithNestedTransaction mocks base method.
+func (m *MockDB) WithNestedTransaction(arg0 context.Context, arg1 func(DB) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithNestedTransaction", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithNestedTransaction indicates an expected call of WithNestedTransaction.
+func (mr *MockDBMockRecorder) WithNestedTransaction(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithNestedTransaction", reflect.TypeOf((*MockDB)(nil).WithNestedTransaction), arg0, arg1)
+}
+
+// WithTransaction mocks base method.
+func (m *MockDB) WithTransaction(arg0 context.Context, arg1 func(DB) error, arg2 ...*sql.TxOptions) error {
+	m.ctrl.T.Helper()
+	varargs := []any{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WithTransaction", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithTransaction indicates an expected call of WithTransaction.
+func (mr *MockDBMockRecorder) WithTransaction(arg0, arg1 any, arg2 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTransaction", reflect.TypeOf((*MockDB)(nil).WithTransaction), varargs...)
+}
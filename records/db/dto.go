@@ -1,7 +1,13 @@
 package db
 
 import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
 	"github.com/google/uuid"
+	"gorm.io/gorm/schema"
 )
 
 // CreateOptions holds the options for creating a new record.
@@ -10,6 +16,9 @@ type CreateOptions struct {
 	//	Title of the record.
 	Title string
 
+	//	Description of the record.
+	Description string
+
 	// ID of the user who is creating the record.
 	UserID uuid.UUID
 }
@@ -24,11 +33,55 @@ func (o *CreateOptions) validate() error {
 	return nil
 }
 
+// ImportOptions holds the options for importing a historical record.
+//
+// Unlike `CreateOptions`, the timestamps are set explicitly instead of being generated.
+type ImportOptions struct {
+
+	//	Title of the record.
+	Title string
+
+	//	Description of the record.
+	Description string
+
+	// ID of the user who created the record.
+	UserID uuid.UUID
+
+	//	Time when the record was originally created.
+	CreatedAt time.Time
+
+	//	Time when the record was last updated.
+	//	If it is zero, it defaults to the creation time.
+	UpdatedAt time.Time
+}
+
+func (o *ImportOptions) validate() error {
+	if o.Title == "" {
+		return ErrInvalidTitle
+	}
+	if o.UserID == uuid.Nil {
+		return ErrInvalidUserID
+	}
+	if o.CreatedAt.IsZero() {
+		return ErrInvalidTimestamps
+	}
+	if !o.UpdatedAt.IsZero() && o.UpdatedAt.Before(o.CreatedAt) {
+		return ErrInvalidTimestamps
+	}
+	return nil
+}
+
 // ListOptions holds the options for listing records.
 type ListOptions struct {
 
 	//	Title of the record.
 	Title string
+	//	Search term matched against the title and the description of the record.
+	Search string
+	//	Rank the records matching `Search` by relevance, before any other order:
+	//	the exact titles first, then the titles starting with the term, then the titles containing it,
+	//	then the records only matching on their description. It requires `Search`.
+	OrderByRelevance bool
 	//	Skip for pagination.
 	Skip int
 	//	Limit for pagination.
@@ -37,26 +90,193 @@ type ListOptions struct {
 	OrderBy string
 	//	Order by direction.
 	OrderDirection string
+	//	Include the soft-deleted records along with the active ones.
+	IncludeDeleted bool
+	//	Only list the soft-deleted records, the most recently deleted first.
+	//	It can't be combined with `IncludeDeleted`.
+	DeletedOnly bool
 }
 
+// MaxLimit is the largest page of records the database layer lists, whatever the role of the requester.
+// The service layer applies the lower caps of the roles.
+const MaxLimit = 1000
+
 func (o *ListOptions) validate() error {
 	if o.Skip < 0 ||
-		o.Limit < 0 || o.Limit > 100 {
+		o.Limit < 0 || o.Limit > MaxLimit {
+		return ErrInvalidFilters
+	}
+	if o.IncludeDeleted && o.DeletedOnly {
+		return ErrInvalidFilters
+	}
+	if o.OrderByRelevance && o.Search == "" {
+		return ErrInvalidFilters
+	}
+	return nil
+}
+
+// DefaultDistinctTitlesLimit is the number of distinct titles fetched, unless a limit is supplied.
+const DefaultDistinctTitlesLimit = 10
+
+// CountOptions holds the options for counting records.
+type CountOptions struct {
+
+	//	Title of the record.
+	Title string
+
+	//	ID of the user who created the record.
+	UserID uuid.UUID
+}
+
+// AggregateOptions holds the options for counting records per group.
+type AggregateOptions struct {
+
+	//	Expression the records are grouped by.
+	//	One of `GroupByDay` or `GroupByTitlePrefix`.
+	GroupBy string
+	//	Number of leading characters of the title which make up the key, when grouping by `GroupByTitlePrefix`.
+	PrefixLength int
+}
+
+func (o *AggregateOptions) validate() error {
+	if _, ok := groupings[o.GroupBy]; !ok {
+		return ErrInvalidFilters
+	}
+	if o.GroupBy == GroupByTitlePrefix && o.PrefixLength < 1 {
 		return ErrInvalidFilters
 	}
 	return nil
 }
 
+const (
+	// GroupByDay groups the records by the day they were created on, formatted as `YYYY-MM-DD`.
+	GroupByDay = "day"
+
+	// GroupByTitlePrefix groups the records by the leading characters of their title.
+	GroupByTitlePrefix = "title_prefix"
+)
+
+// groupings maps the allowed groupings to their SQL expressions.
+//
+// The expressions are only ever taken from this map, so the clients can't inject arbitrary SQL.
+var groupings = map[string]string{
+	GroupByDay:         "CAST(DATE(created_at) AS TEXT)",
+	GroupByTitlePrefix: "SUBSTR(title, 1, %d)",
+}
+
 // UpdateOptions holds the options for updating a record.
 type UpdateOptions struct {
 
 	//	Title of the record.
 	Title string
+
+	//	Description of the record.
+	Description string
 }
 
 func (o *UpdateOptions) validate() error {
-	if o.Title == "" {
+	if o.empty() {
+		return ErrNoFieldsToUpdate
+	}
+	if o.Title != "" && strings.TrimSpace(o.Title) == "" {
 		return ErrInvalidTitle
 	}
 	return nil
 }
+
+// empty checks whether none of the updatable fields are set.
+func (o *UpdateOptions) empty() bool {
+	return o.Title == "" && o.Description == ""
+}
+
+// updatable is the allowlist of the columns which `Update` may write.
+// A field added to `UpdateOptions` stays read-only until its column is listed here,
+// so sensitive columns, like `user_id`, can never be patched through the generic path.
+var updatable = map[string]bool{
+	"title":       true,
+	"description": true,
+}
+
+// updates builds the map of the columns to update from the set fields of the options.
+// The fields whose columns aren't in the allowlist are dropped.
+func (o *UpdateOptions) updates(namer schema.Namer) map[string]any {
+	updates := make(map[string]any)
+	value := reflect.ValueOf(o).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		column := namer.ColumnName("", value.Type().Field(i).Name)
+		if !updatable[column] || value.Field(i).IsZero() {
+			continue
+		}
+		updates[column] = value.Field(i).Interface()
+	}
+	return updates
+}
+
+// CreateAuditLogOptions holds the options for appending an entry to the audit trail.
+type CreateAuditLogOptions struct {
+
+	//	ID of the user who performed the mutation.
+	//	It is nil when the mutation wasn't performed on behalf of a user.
+	ActorID uuid.UUID
+
+	//	Mutation performed on the entity.
+	Action string
+
+	//	Kind of the mutated entity.
+	Entity string
+
+	//	ID of the mutated entity.
+	EntityID uuid.UUID
+
+	//	Fields changed by the mutation, as a JSON object.
+	Diff json.RawMessage
+}
+
+func (o *CreateAuditLogOptions) validate() error {
+	if o.Action == "" || o.Entity == "" || o.EntityID == uuid.Nil {
+		return ErrInvalidOptions
+	}
+	if o.Diff != nil && !json.Valid(o.Diff) {
+		return ErrInvalidOptions
+	}
+	return nil
+}
+
+// ListAuditLogsOptions holds the options for listing the entries of the audit trail.
+type ListAuditLogsOptions struct {
+
+	//	ID of the user who performed the mutations.
+	ActorID uuid.UUID
+
+	//	Mutation performed on the entities.
+	Action string
+
+	//	Kind of the mutated entities.
+	Entity string
+
+	//	ID of the mutated entity.
+	EntityID uuid.UUID
+
+	//	Only the entries created at or after this time.
+	Since time.Time
+
+	//	Only the entries created before this time.
+	Until time.Time
+
+	//	Skip for pagination.
+	Skip int
+
+	//	Limit for pagination.
+	Limit int
+}
+
+func (o *ListAuditLogsOptions) validate() error {
+	if o.Skip < 0 ||
+		o.Limit < 0 || o.Limit > 100 {
+		return ErrInvalidFilters
+	}
+	if !o.Since.IsZero() && !o.Until.IsZero() && !o.Since.Before(o.Until) {
+		return ErrInvalidFilters
+	}
+	return nil
+}
@@ -1,12 +1,33 @@
 package db
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/mrinalwahal/boilerplate/pkg/errs"
+)
 
 var (
-	ErrInvalidOptions  = fmt.Errorf("invalid options")
-	ErrInvalidRecordID = fmt.Errorf("invalid record id")
-	ErrInvalidUserID   = fmt.Errorf("invalid user id")
-	ErrInvalidTitle    = fmt.Errorf("invalid title")
-	ErrInvalidFilters  = fmt.Errorf("invalid filters")
-	ErrNoRowsAffected  = fmt.Errorf("no rows affected")
+	ErrInvalidOptions   = errs.Wrap(errs.InvalidArgument, "invalid options")
+	ErrInvalidRecordID  = errs.Wrap(errs.InvalidArgument, "invalid record id")
+	ErrInvalidUserID    = errs.Wrap(errs.InvalidArgument, "invalid user id")
+	ErrInvalidTitle     = errs.Wrap(errs.Unprocessable, "invalid title")
+	ErrNoFieldsToUpdate = errs.Wrap(errs.Unprocessable, "no fields to update")
+	ErrInvalidFilters   = errs.Wrap(errs.InvalidArgument, "invalid filters")
+	ErrNoRowsAffected   = errs.Wrap(errs.NotFound, "no rows affected")
+
+	// ErrDuplicateTitle is returned when the user already has a record, which isn't deleted, with the same title.
+	ErrDuplicateTitle = errs.Wrap(errs.AlreadyExists, "title already exists")
+
+	ErrInvalidTimestamps = errs.Wrap(errs.InvalidArgument, "invalid timestamps")
+
+	// ErrRetryable is returned when a transaction failed because of a serialization failure or a deadlock.
+	// The whole transaction can safely be retried.
+	ErrRetryable = fmt.Errorf("retryable transaction failure")
+
+	// ErrPartialResults is returned along with the successfully scanned records when some of the listed rows failed to scan.
+	ErrPartialResults = fmt.Errorf("partial results")
+
+	// ErrResultTooLarge is returned when a list matches more records than the hard cap of the database layer.
+	// The clients should narrow their filters or page through the records.
+	ErrResultTooLarge = errs.Wrap(errs.InvalidArgument, "result too large")
 )
@@ -0,0 +1,7 @@
+-- +goose Up
+-- modify "records" table
+ALTER TABLE "public"."records" ADD COLUMN "description" text NOT NULL DEFAULT '';
+
+-- +goose Down
+-- reverse: modify "records" table
+ALTER TABLE "public"."records" DROP COLUMN "description";
@@ -0,0 +1,19 @@
+-- +goose Up
+-- The titles are unique per user among the records which aren't deleted, so the upserts can conflict on them.
+-- Fail with a clear message, rather than the error of the index, if the existing records share a title.
+-- +goose StatementBegin
+DO $$
+BEGIN
+  IF EXISTS (
+    SELECT 1 FROM "public"."records" WHERE deleted_at IS NULL GROUP BY "user_id", "title" HAVING count(*) > 1
+  ) THEN
+    RAISE EXCEPTION 'some users have several records with the same title: rename or delete the duplicates, then migrate again';
+  END IF;
+END $$;
+-- +goose StatementEnd
+-- create index "idx_records_user_id_title" to table: "records"
+CREATE UNIQUE INDEX "idx_records_user_id_title" ON "public"."records" ("user_id", "title") WHERE (deleted_at IS NULL);
+
+-- +goose Down
+-- reverse: create index "idx_records_user_id_title" to table: "records"
+DROP INDEX "public"."idx_records_user_id_title";
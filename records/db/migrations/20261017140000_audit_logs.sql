@@ -0,0 +1,28 @@
+-- +goose Up
+-- create "audit_logs" table
+CREATE TABLE "public"."audit_logs" (
+  "id" uuid NOT NULL,
+  "created_at" timestamptz NULL,
+  "actor_id" uuid NOT NULL,
+  "action" text NOT NULL,
+  "entity" text NOT NULL,
+  "entity_id" uuid NOT NULL,
+  "diff" jsonb NULL,
+  PRIMARY KEY ("id")
+);
+-- create index "idx_audit_logs_actor_id" to table: "audit_logs"
+CREATE INDEX "idx_audit_logs_actor_id" ON "public"."audit_logs" ("actor_id");
+-- create index "idx_audit_logs_created_at" to table: "audit_logs"
+CREATE INDEX "idx_audit_logs_created_at" ON "public"."audit_logs" ("created_at");
+-- create index "idx_audit_logs_entity_id" to table: "audit_logs"
+CREATE INDEX "idx_audit_logs_entity_id" ON "public"."audit_logs" ("entity_id");
+
+-- +goose Down
+-- reverse: create index "idx_audit_logs_entity_id" to table: "audit_logs"
+DROP INDEX "public"."idx_audit_logs_entity_id";
+-- reverse: create index "idx_audit_logs_created_at" to table: "audit_logs"
+DROP INDEX "public"."idx_audit_logs_created_at";
+-- reverse: create index "idx_audit_logs_actor_id" to table: "audit_logs"
+DROP INDEX "public"."idx_audit_logs_actor_id";
+-- reverse: create "audit_logs" table
+DROP TABLE "public"."audit_logs";
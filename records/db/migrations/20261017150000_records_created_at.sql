@@ -0,0 +1,7 @@
+-- +goose Up
+-- create index "idx_records_created_at" to table: "records"
+CREATE INDEX "idx_records_created_at" ON "public"."records" ("created_at");
+
+-- +goose Down
+-- reverse: create index "idx_records_created_at" to table: "records"
+DROP INDEX "public"."idx_records_created_at";
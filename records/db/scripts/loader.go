@@ -13,6 +13,7 @@ import (
 // Define the models to generate migrations for.
 var models = []any{
 	&model.Record{},
+	&model.AuditLog{},
 }
 
 func main() {
@@ -2,13 +2,27 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"expvar"
+	"fmt"
+	"log/slog"
+	"strings"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/mrinalwahal/boilerplate/model"
+	"github.com/mrinalwahal/boilerplate/pkg/db/unbounded"
 	"github.com/mrinalwahal/boilerplate/pkg/middleware"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// rlsDenied counts the accesses denied by the RLS checks, keyed by `<entity>.<operation>`.
+//
+// It's published with the `expvar` package, so it's served at `/debug/vars` if the handler is mounted.
+var rlsDenied = expvar.NewMap("rls_denied")
+
 type SQLDBConfig struct {
 
 	// Database connection.
@@ -16,17 +30,67 @@ type SQLDBConfig struct {
 	//
 	// This field is mandatory.
 	DB *gorm.DB
+
+	// Logger is used to report the rows which failed to scan.
+	// Default: `slog.Default()`
+	//
+	// This field is optional.
+	Logger *slog.Logger
+
+	// AuditRLS enables reporting the accesses denied by the Row Level Security (RLS) checks.
+	// When a record isn't found because it's owned by another user, a warning is logged
+	// and the `rls_denied` counter is incremented.
+	// The ownership is probed with an extra query, so it's disabled by default.
+	// Default: `false`
+	//
+	// This field is optional.
+	AuditRLS bool
+
+	// PartialResults enables returning the successfully scanned subset of the records
+	// when some of the rows listed by a query fail to scan.
+	// The subset is returned along with an error wrapping `ErrPartialResults`.
+	// If disabled, a scan error fails the whole operation.
+	// Default: `false`
+	//
+	// This field is optional.
+	PartialResults bool
+
+	// MaxRows is the hard cap on the number of records a single list returns, whatever its limit,
+	// so a crafted request can't make the server load an enormous page in memory.
+	// The lists matching more records fail with `ErrResultTooLarge`.
+	// The streams of `ListChan` aren't capped, since they don't hold the records in memory.
+	// Default: `DefaultMaxRows`
+	//
+	// This field is optional.
+	MaxRows int
 }
 
+// DefaultMaxRows is the hard cap on the number of records a list returns, unless configured otherwise.
+const DefaultMaxRows = 10000
+
 func NewSQLDB(config *SQLDBConfig) DB {
 	if config == nil {
 		panic("db: nil config")
 	}
 
 	db := sqldb{
-		conn: config.DB,
+		conn:           config.DB,
+		logger:         config.Logger,
+		auditRLS:       config.AuditRLS,
+		partialResults: config.PartialResults,
+		maxRows:        config.MaxRows,
 	}
 
+	if db.maxRows <= 0 {
+		db.maxRows = DefaultMaxRows
+	}
+
+	if db.logger == nil {
+		db.logger = slog.Default()
+	}
+
+	db.logger = db.logger.With("layer", "database")
+
 	return &db
 }
 
@@ -39,11 +103,24 @@ type sqldb struct {
 
 	//	Database Connection
 	conn *gorm.DB
+
+	//	Logger.
+	logger *slog.Logger
+
+	//	Whether to report the accesses denied by the RLS checks.
+	auditRLS bool
+
+	//	Whether to return the successfully scanned records when some of the rows fail to scan.
+	partialResults bool
+
+	//	Hard cap on the number of records a list returns.
+	//	If it is zero, the lists are uncapped.
+	maxRows int
 }
 
 // Create operation creates a new record in the database.
 func (db *sqldb) Create(ctx context.Context, options *CreateOptions) (*model.Record, error) {
-	txn := db.conn.WithContext(ctx)
+	txn := db.session(ctx)
 	if options == nil {
 		return nil, ErrInvalidOptions
 	}
@@ -58,19 +135,154 @@ func (db *sqldb) Create(ctx context.Context, options *CreateOptions) (*model.Rec
 	// Prepare the payload we have to send to the database transaction.
 	var payload model.Record
 	payload.Title = options.Title
+	payload.Description = options.Description
 	payload.UserID = options.UserID
 
 	// Execute the transaction.
 	result := txn.Create(&payload)
 	if result.Error != nil {
+		if duplicate(result.Error) {
+			return nil, ErrDuplicateTitle
+		}
 		return nil, result.Error
 	}
 	return &payload, nil
 }
 
-// List operation fetches a list of records from the database.
-func (db *sqldb) List(ctx context.Context, options *ListOptions) ([]*model.Record, error) {
-	txn := db.conn.WithContext(ctx)
+// Upsert operation creates a record in the database, or updates the record with the same `(user_id, title)`.
+//
+// On conflict, only the description and the update time are overwritten.
+// The stored record is read back, since the ID generated for the insert is discarded on conflict.
+func (db *sqldb) Upsert(ctx context.Context, options *CreateOptions) (*model.Record, bool, error) {
+	txn := db.session(ctx)
+	if options == nil {
+		return nil, false, ErrInvalidOptions
+	}
+	if err := options.validate(); err != nil {
+		return nil, false, err
+	}
+
+	//
+	// This method has no Row Level Security (RLS) checks.
+	//
+
+	// Prepare the payload we have to send to the database transaction.
+	var payload model.Record
+	payload.Title = options.Title
+	payload.Description = options.Description
+	payload.UserID = options.UserID
+
+	// Execute the transaction.
+	result := txn.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "title"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "deleted_at IS NULL"},
+		}},
+		DoUpdates: clause.AssignmentColumns([]string{"description", "updated_at"}),
+	}).Create(&payload)
+	if result.Error != nil {
+		return nil, false, result.Error
+	}
+
+	var record model.Record
+	if result := txn.Where(&model.Record{
+		UserID: options.UserID,
+		Title:  options.Title,
+	}).First(&record); result.Error != nil {
+		return nil, false, result.Error
+	}
+	return &record, record.ID == payload.ID, nil
+}
+
+// CreateIfNotExists operation creates a record in the database, unless a record with the same `(user_id, title)` exists.
+//
+// On conflict, the existing record is left untouched and read back.
+func (db *sqldb) CreateIfNotExists(ctx context.Context, options *CreateOptions) (*model.Record, bool, error) {
+	txn := db.session(ctx)
+	if options == nil {
+		return nil, false, ErrInvalidOptions
+	}
+	if err := options.validate(); err != nil {
+		return nil, false, err
+	}
+
+	//
+	// This method has no Row Level Security (RLS) checks.
+	//
+
+	// Prepare the payload we have to send to the database transaction.
+	var payload model.Record
+	payload.Title = options.Title
+	payload.Description = options.Description
+	payload.UserID = options.UserID
+
+	// Execute the transaction.
+	result := txn.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "title"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "deleted_at IS NULL"},
+		}},
+		DoNothing: true,
+	}).Create(&payload)
+	if result.Error != nil {
+		return nil, false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return &payload, true, nil
+	}
+
+	var record model.Record
+	if result := txn.Where(&model.Record{
+		UserID: options.UserID,
+		Title:  options.Title,
+	}).First(&record); result.Error != nil {
+		return nil, false, result.Error
+	}
+	return &record, false, nil
+}
+
+// Import operation creates a record with the supplied timestamps in the database.
+//
+// Gorm only generates the `autoCreateTime` and `autoUpdateTime` timestamps when they are zero,
+// so the supplied ones are preserved.
+func (db *sqldb) Import(ctx context.Context, options *ImportOptions) (*model.Record, error) {
+	txn := db.session(ctx)
+	if options == nil {
+		return nil, ErrInvalidOptions
+	}
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+
+	//
+	// This method has no Row Level Security (RLS) checks.
+	//
+
+	// Prepare the payload we have to send to the database transaction.
+	var payload model.Record
+	payload.Title = options.Title
+	payload.Description = options.Description
+	payload.UserID = options.UserID
+	payload.CreatedAt = options.CreatedAt
+	payload.UpdatedAt = options.UpdatedAt
+	if payload.UpdatedAt.IsZero() {
+		payload.UpdatedAt = options.CreatedAt
+	}
+
+	// Execute the transaction.
+	result := txn.Create(&payload)
+	if result.Error != nil {
+		if duplicate(result.Error) {
+			return nil, ErrDuplicateTitle
+		}
+		return nil, result.Error
+	}
+	return &payload, nil
+}
+
+// listQuery builds the query of the records matching the list options.
+func (db *sqldb) listQuery(ctx context.Context, options *ListOptions) (*gorm.DB, error) {
+	txn := db.session(ctx)
 	if options == nil {
 		options = &ListOptions{}
 	}
@@ -79,7 +291,7 @@ func (db *sqldb) List(ctx context.Context, options *ListOptions) ([]*model.Recor
 	}
 
 	// If the request context contains JWT claims, apply Row Level Security (RLS) checks.
-	claims, exists := ctx.Value(middleware.XJWTClaims).(middleware.JWTClaims)
+	claims, exists := middleware.JWTClaimsFromContext(ctx)
 	if exists {
 
 		// 1. Only the user who created the record can list it.
@@ -88,8 +300,6 @@ func (db *sqldb) List(ctx context.Context, options *ListOptions) ([]*model.Recor
 		})
 	}
 
-	var payload []*model.Record
-
 	query := txn
 	if options.Limit > 0 {
 		query = query.Limit(options.Limit)
@@ -97,30 +307,155 @@ func (db *sqldb) List(ctx context.Context, options *ListOptions) ([]*model.Recor
 	if options.Skip > 0 {
 		query = query.Offset(options.Skip)
 	}
+	if options.OrderByRelevance {
+
+		// The rank is selected rather than ordered by directly, because the order clauses can't carry arguments.
+		query = query.Select("*, "+relevance+" AS relevance", strings.ToLower(options.Search), prefixed(options.Search), contains(options.Search)).
+			Order("relevance asc")
+	}
+	switch {
+	case options.DeletedOnly:
+		query = query.Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at desc")
+	case options.IncludeDeleted:
+		query = query.Unscoped()
+	}
 	if options.OrderBy != "" {
 		query = query.Order(options.OrderBy + " " + options.OrderDirection)
 	}
+
+	// Break the ties on the primary key, so the rows with equal values keep the same order across the pages.
+	if options.DeletedOnly || (options.OrderBy != "" && options.OrderBy != "id") || (options.OrderByRelevance && options.OrderBy == "") {
+		query = query.Order("id asc")
+	}
 	if options.Title != "" {
 		query = query.Where(&model.Record{
 			Title: options.Title,
 		})
 	}
+	if options.Search != "" {
+		pattern := contains(options.Search)
+		query = query.Where(`(LOWER(title) LIKE ? ESCAPE '\' OR LOWER(description) LIKE ? ESCAPE '\')`, pattern, pattern)
+	}
 
-	if result := query.Find(&payload); result.Error != nil {
-		return nil, result.Error
+	return query, nil
+}
+
+// List operation fetches a list of records from the database.
+func (db *sqldb) List(ctx context.Context, options *ListOptions) ([]*model.Record, error) {
+	query, err := db.listQuery(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read one row over the cap, to tell a list which hits it from one which exceeds it.
+	capped := db.maxRows > 0 && (options == nil || options.Limit == 0 || options.Limit > db.maxRows)
+	if capped {
+		query = query.Limit(db.maxRows + 1)
+	}
+
+	payload := make([]*model.Record, 0)
+
+	rows, err := query.Model(&model.Record{}).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Scan the rows one by one, so that a row which fails to scan can be told apart from a failed query.
+	// Each row is scanned with a new session, otherwise the error of a failed scan sticks to the following ones.
+	scanner := query.Session(&gorm.Session{})
+	var failures []error
+	for scanned := 1; rows.Next(); scanned++ {
+		if capped && scanned > db.maxRows {
+			return nil, ErrResultTooLarge
+		}
+
+		var record model.Record
+		if err := scanner.ScanRows(rows, &record); err != nil {
+			if !db.partialResults {
+				return nil, err
+			}
+			db.logger.LogAttrs(ctx, slog.LevelError, "failed to scan a record",
+				slog.String("function", "list"),
+				slog.String("error", err.Error()),
+			)
+			failures = append(failures, err)
+			continue
+		}
+		payload = append(payload, &record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(failures) > 0 {
+		return payload, fmt.Errorf("%w: %d rows failed to scan: %w", ErrPartialResults, len(failures), errors.Join(failures...))
 	}
 	return payload, nil
 }
 
+// ListChan operation streams the records from the database, one by one, on the returned channel.
+func (db *sqldb) ListChan(ctx context.Context, options *ListOptions) (<-chan *model.Record, <-chan error) {
+	records := make(chan *model.Record)
+
+	// Buffered, so the goroutine can exit even if the consumer never reads the error.
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(records)
+		defer close(errc)
+
+		query, err := db.listQuery(ctx, options)
+		if err != nil {
+			errc <- err
+			return
+		}
+
+		// The stream doesn't load the records in memory, so it may read them all.
+		rows, err := unbounded.Allow(query).Model(&model.Record{}).Rows()
+		if err != nil {
+			errc <- err
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+
+			// Stop early if the consumer has gone away.
+			if err := ctx.Err(); err != nil {
+				errc <- err
+				return
+			}
+
+			var record model.Record
+			if err := query.ScanRows(rows, &record); err != nil {
+				errc <- err
+				return
+			}
+			select {
+			case records <- &record:
+			case <-ctx.Done():
+				errc <- ctx.Err()
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			errc <- err
+		}
+	}()
+
+	return records, errc
+}
+
 // Get operation fetches a record from the database.
 func (db *sqldb) Get(ctx context.Context, ID uuid.UUID) (*model.Record, error) {
-	txn := db.conn.WithContext(ctx)
+	txn := db.session(ctx)
 	if ID == uuid.Nil {
 		return nil, ErrInvalidRecordID
 	}
 
 	// If the request context contains JWT claims, apply Row Level Security (RLS) checks.
-	claims, exists := ctx.Value(middleware.XJWTClaims).(middleware.JWTClaims)
+	claims, exists := middleware.JWTClaimsFromContext(ctx)
 	if exists {
 
 		// 1. Only the user who created the record can get it.
@@ -133,6 +468,110 @@ func (db *sqldb) Get(ctx context.Context, ID uuid.UUID) (*model.Record, error) {
 	payload.ID = ID
 	result := txn.First(&payload)
 	if result.Error != nil {
+		if exists && errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			db.audit(ctx, "get", ID, claims)
+		}
+		return nil, result.Error
+	}
+	return &payload, nil
+}
+
+// GetByIDs operation fetches the records with the supplied IDs from the database, keyed by their ID.
+//
+// The IDs of the records which don't exist, or which the requester can't get, are absent from the map.
+func (db *sqldb) GetByIDs(ctx context.Context, IDs []uuid.UUID) (map[uuid.UUID]*model.Record, error) {
+	txn := db.session(ctx)
+	if len(IDs) > MaxLimit {
+		return nil, ErrInvalidOptions
+	}
+
+	// Deduplicate the IDs, so the limit matches the number of the records which can be found.
+	unique := make(map[uuid.UUID]bool, len(IDs))
+	for _, ID := range IDs {
+		if ID == uuid.Nil {
+			return nil, ErrInvalidRecordID
+		}
+		unique[ID] = true
+	}
+	payload := make(map[uuid.UUID]*model.Record, len(unique))
+	if len(unique) == 0 {
+		return payload, nil
+	}
+	ids := make([]uuid.UUID, 0, len(unique))
+	for ID := range unique {
+		ids = append(ids, ID)
+	}
+
+	// If the request context contains JWT claims, apply Row Level Security (RLS) checks.
+	claims, exists := middleware.JWTClaimsFromContext(ctx)
+	if exists {
+
+		// 1. Only the user who created the records can get them.
+		txn = txn.Where(&model.Record{
+			UserID: claims.XUserID,
+		})
+	}
+
+	var records []*model.Record
+	if result := txn.Where("id IN ?", ids).Limit(len(ids)).Find(&records); result.Error != nil {
+		return nil, result.Error
+	}
+	for _, record := range records {
+		payload[record.ID] = record
+	}
+	return payload, nil
+}
+
+// Exists operation checks whether a record exists in the database, without fetching it.
+//
+// The soft-deleted records don't exist.
+func (db *sqldb) Exists(ctx context.Context, ID uuid.UUID) (bool, error) {
+	txn := db.session(ctx)
+	if ID == uuid.Nil {
+		return false, ErrInvalidRecordID
+	}
+
+	// If the request context contains JWT claims, apply Row Level Security (RLS) checks.
+	claims, exists := middleware.JWTClaimsFromContext(ctx)
+	if exists {
+
+		// 1. Only the user who created the record can see it.
+		txn = txn.Where(&model.Record{
+			UserID: claims.XUserID,
+		})
+	}
+
+	var count int64
+	if result := txn.Model(&model.Record{}).Where("id = ?", ID).Limit(1).Count(&count); result.Error != nil {
+		return false, result.Error
+	}
+	return count > 0, nil
+}
+
+// GetIncludingDeleted operation fetches a record from the database, including the soft-deleted ones.
+func (db *sqldb) GetIncludingDeleted(ctx context.Context, ID uuid.UUID) (*model.Record, error) {
+	txn := db.session(ctx).Unscoped()
+	if ID == uuid.Nil {
+		return nil, ErrInvalidRecordID
+	}
+
+	// If the request context contains JWT claims, apply Row Level Security (RLS) checks.
+	claims, exists := middleware.JWTClaimsFromContext(ctx)
+	if exists {
+
+		// 1. Only the user who created the record can get it.
+		txn = txn.Where(&model.Record{
+			UserID: claims.XUserID,
+		})
+	}
+
+	var payload model.Record
+	payload.ID = ID
+	result := txn.First(&payload)
+	if result.Error != nil {
+		if exists && errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			db.audit(ctx, "get", ID, claims)
+		}
 		return nil, result.Error
 	}
 	return &payload, nil
@@ -140,7 +579,16 @@ func (db *sqldb) Get(ctx context.Context, ID uuid.UUID) (*model.Record, error) {
 
 // Update operation updates a record in the database.
 func (db *sqldb) Update(ctx context.Context, id uuid.UUID, options *UpdateOptions) (*model.Record, error) {
-	txn := db.conn.WithContext(ctx)
+	result, err := db.UpdateResult(ctx, id, options)
+	if err != nil {
+		return nil, err
+	}
+	return result.Record, nil
+}
+
+// UpdateResult operation updates a record in the database and reports the number of the updated rows.
+func (db *sqldb) UpdateResult(ctx context.Context, id uuid.UUID, options *UpdateOptions) (*model.Result, error) {
+	txn := db.session(ctx)
 	if id == uuid.Nil {
 		return nil, ErrInvalidRecordID
 	}
@@ -152,7 +600,7 @@ func (db *sqldb) Update(ctx context.Context, id uuid.UUID, options *UpdateOption
 	}
 
 	// If the request context contains JWT claims, apply Row Level Security (RLS) checks.
-	claims, exists := ctx.Value(middleware.XJWTClaims).(middleware.JWTClaims)
+	claims, exists := middleware.JWTClaimsFromContext(ctx)
 	if exists {
 
 		// 1. Only the user who created the record can update it.
@@ -161,23 +609,47 @@ func (db *sqldb) Update(ctx context.Context, id uuid.UUID, options *UpdateOption
 		})
 	}
 
+	// Only the allowlisted columns are written.
+	updates := options.updates(db.conn.NamingStrategy)
+	if len(updates) == 0 {
+		return nil, ErrNoFieldsToUpdate
+	}
+
 	var payload model.Record
 	payload.ID = id
-	if result := txn.Model(&payload).Updates(options); result.Error != nil {
+	result := txn.Model(&payload).Updates(updates)
+	if result.Error != nil {
+		if duplicate(result.Error) {
+			return nil, ErrDuplicateTitle
+		}
 		return nil, result.Error
 	}
-	return db.Get(ctx, id)
+
+	record, err := db.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Result{
+		Record:       record,
+		RowsAffected: result.RowsAffected,
+	}, nil
 }
 
 // Delete operation deletes a record from the database.
 func (db *sqldb) Delete(ctx context.Context, ID uuid.UUID) error {
-	txn := db.conn.WithContext(ctx)
+	_, err := db.DeleteResult(ctx, ID)
+	return err
+}
+
+// DeleteResult operation deletes a record from the database and reports the number of the deleted rows.
+func (db *sqldb) DeleteResult(ctx context.Context, ID uuid.UUID) (*model.Result, error) {
+	txn := db.session(ctx)
 	if ID == uuid.Nil {
-		return ErrInvalidRecordID
+		return nil, ErrInvalidRecordID
 	}
 
 	// If the request context contains JWT claims, apply Row Level Security (RLS) checks.
-	claims, exists := ctx.Value(middleware.XJWTClaims).(middleware.JWTClaims)
+	claims, exists := middleware.JWTClaimsFromContext(ctx)
 	if exists {
 
 		// 1. Only the user who created the record can delete it.
@@ -189,11 +661,449 @@ func (db *sqldb) Delete(ctx context.Context, ID uuid.UUID) error {
 	var payload model.Record
 	payload.ID = ID
 	result := txn.Delete(&payload)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		if exists {
+			db.audit(ctx, "delete", ID, claims)
+		}
+		return nil, ErrNoRowsAffected
+	}
+	return &model.Result{
+		RowsAffected: result.RowsAffected,
+	}, nil
+}
+
+// Touch operation bumps the `updated_at` timestamp of a record in the database, without changing any other field.
+func (db *sqldb) Touch(ctx context.Context, ID uuid.UUID) error {
+	txn := db.session(ctx)
+	if ID == uuid.Nil {
+		return ErrInvalidRecordID
+	}
+
+	// If the request context contains JWT claims, apply Row Level Security (RLS) checks.
+	claims, exists := middleware.JWTClaimsFromContext(ctx)
+	if exists {
+
+		// 1. Only the user who created the record can touch it.
+		txn = txn.Where(&model.Record{
+			UserID: claims.XUserID,
+		})
+	}
+
+	var payload model.Record
+	payload.ID = ID
+	result := txn.Model(&payload).Update("updated_at", db.conn.NowFunc())
 	if result.Error != nil {
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
+		if exists {
+			db.audit(ctx, "touch", ID, claims)
+		}
 		return ErrNoRowsAffected
 	}
 	return nil
 }
+
+// Reassign operation moves a record to another user in the database.
+func (db *sqldb) Reassign(ctx context.Context, ID uuid.UUID, userID uuid.UUID) (*model.Record, error) {
+	txn := db.session(ctx)
+	if ID == uuid.Nil {
+		return nil, ErrInvalidRecordID
+	}
+	if userID == uuid.Nil {
+		return nil, ErrInvalidUserID
+	}
+
+	//
+	// This method has no Row Level Security (RLS) checks.
+	// The record isn't owned by the requester, so the callers must authorize the reassignment.
+	//
+
+	var payload model.Record
+	payload.ID = ID
+	result := txn.Model(&payload).Update("user_id", userID)
+	if result.Error != nil {
+		if duplicate(result.Error) {
+			return nil, ErrDuplicateTitle
+		}
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrNoRowsAffected
+	}
+
+	var record model.Record
+	if result := txn.First(&record, "id = ?", ID); result.Error != nil {
+		return nil, result.Error
+	}
+	return &record, nil
+}
+
+// Count operation counts the records in the database.
+//
+// The soft-deleted records are not counted.
+func (db *sqldb) Count(ctx context.Context, options *CountOptions) (int64, error) {
+	txn := db.session(ctx)
+	if options == nil {
+		options = &CountOptions{}
+	}
+
+	// If the request context contains JWT claims, apply Row Level Security (RLS) checks.
+	claims, exists := middleware.JWTClaimsFromContext(ctx)
+	if exists {
+
+		// 1. Only the user who created the records can count them.
+		txn = txn.Where(&model.Record{
+			UserID: claims.XUserID,
+		})
+	}
+
+	query := txn.Model(&model.Record{})
+	if options.UserID != uuid.Nil {
+		query = query.Where(&model.Record{
+			UserID: options.UserID,
+		})
+	}
+	if options.Title != "" {
+		query = query.Where(&model.Record{
+			Title: options.Title,
+		})
+	}
+
+	var count int64
+	if result := query.Count(&count); result.Error != nil {
+		return 0, result.Error
+	}
+	return count, nil
+}
+
+// CountDeleted operation counts the soft-deleted records in the database.
+func (db *sqldb) CountDeleted(ctx context.Context) (int64, error) {
+	txn := db.session(ctx)
+
+	// If the request context contains JWT claims, apply Row Level Security (RLS) checks.
+	claims, exists := middleware.JWTClaimsFromContext(ctx)
+	if exists {
+
+		// 1. Only the user who deleted the records can count them.
+		txn = txn.Where(&model.Record{
+			UserID: claims.XUserID,
+		})
+	}
+
+	var count int64
+	if result := txn.Model(&model.Record{}).Unscoped().Where("deleted_at IS NOT NULL").Count(&count); result.Error != nil {
+		return 0, result.Error
+	}
+	return count, nil
+}
+
+// Aggregate operation counts the records per group in the database.
+//
+// The grouping is done by the database, so only the counts are returned instead of the rows.
+func (db *sqldb) Aggregate(ctx context.Context, options *AggregateOptions) ([]*model.Group, error) {
+	txn := db.session(ctx)
+	if options == nil {
+		return nil, ErrInvalidOptions
+	}
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+
+	// If the request context contains JWT claims, apply Row Level Security (RLS) checks.
+	claims, exists := middleware.JWTClaimsFromContext(ctx)
+	if exists {
+
+		// 1. Only the user who created the records can aggregate them.
+		txn = txn.Where(&model.Record{
+			UserID: claims.XUserID,
+		})
+	}
+
+	expression := groupings[options.GroupBy]
+	if options.GroupBy == GroupByTitlePrefix {
+		expression = fmt.Sprintf(expression, options.PrefixLength)
+	}
+
+	groups := []*model.Group{}
+	result := txn.Model(&model.Record{}).
+		Select(expression + " AS key, COUNT(*) AS count").
+		Group("key").
+		Order("key asc").
+		Scan(&groups)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return groups, nil
+}
+
+// DistinctTitles operation fetches the distinct titles starting with the prefix from the database.
+//
+// The prefix is matched case-insensitively. If the limit is zero, `DefaultDistinctTitlesLimit` titles are returned at most.
+func (db *sqldb) DistinctTitles(ctx context.Context, prefix string, limit int) ([]string, error) {
+	txn := db.session(ctx)
+	if limit < 0 || limit > 100 {
+		return nil, ErrInvalidFilters
+	}
+	if limit == 0 {
+		limit = DefaultDistinctTitlesLimit
+	}
+
+	// If the request context contains JWT claims, apply Row Level Security (RLS) checks.
+	claims, exists := middleware.JWTClaimsFromContext(ctx)
+	if exists {
+
+		// 1. Only the user who created the records can list their titles.
+		txn = txn.Where(&model.Record{
+			UserID: claims.XUserID,
+		})
+	}
+
+	query := txn.Model(&model.Record{}).Distinct("title")
+	if prefix != "" {
+		query = query.Where(`LOWER(title) LIKE ? ESCAPE '\'`, prefixed(prefix))
+	}
+
+	titles := []string{}
+	if result := query.Order("title asc").Limit(limit).Pluck("title", &titles); result.Error != nil {
+		return nil, result.Error
+	}
+	return titles, nil
+}
+
+// CreateAuditLog operation appends an entry to the audit trail in the database.
+func (db *sqldb) CreateAuditLog(ctx context.Context, options *CreateAuditLogOptions) (*model.AuditLog, error) {
+	txn := db.session(ctx)
+	if options == nil {
+		return nil, ErrInvalidOptions
+	}
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+
+	//
+	// This method has no Row Level Security (RLS) checks.
+	//
+
+	// Prepare the payload we have to send to the database transaction.
+	var payload model.AuditLog
+	payload.ActorID = options.ActorID
+	payload.Action = options.Action
+	payload.Entity = options.Entity
+	payload.EntityID = options.EntityID
+	payload.Diff = options.Diff
+
+	// Execute the transaction.
+	result := txn.Create(&payload)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &payload, nil
+}
+
+// ListAuditLogs operation fetches the entries of the audit trail from the database, the most recent first.
+func (db *sqldb) ListAuditLogs(ctx context.Context, options *ListAuditLogsOptions) ([]*model.AuditLog, error) {
+	txn := db.session(ctx)
+	if options == nil {
+		options = &ListAuditLogsOptions{}
+	}
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+
+	// If the request context contains JWT claims, apply Row Level Security (RLS) checks.
+	claims, exists := middleware.JWTClaimsFromContext(ctx)
+	if exists {
+
+		// 1. Only the user who performed the mutations can list their entries.
+		txn = txn.Where(&model.AuditLog{
+			ActorID: claims.XUserID,
+		})
+	}
+
+	query := txn.Order("created_at desc").Order("id asc")
+	if options.Limit > 0 {
+		query = query.Limit(options.Limit)
+	}
+	if options.Skip > 0 {
+		query = query.Offset(options.Skip)
+	}
+	if options.ActorID != uuid.Nil {
+		query = query.Where(&model.AuditLog{
+			ActorID: options.ActorID,
+		})
+	}
+	if options.Action != "" {
+		query = query.Where(&model.AuditLog{
+			Action: options.Action,
+		})
+	}
+	if options.Entity != "" {
+		query = query.Where(&model.AuditLog{
+			Entity: options.Entity,
+		})
+	}
+	if options.EntityID != uuid.Nil {
+		query = query.Where(&model.AuditLog{
+			EntityID: options.EntityID,
+		})
+	}
+	if !options.Since.IsZero() {
+		query = query.Where("created_at >= ?", options.Since)
+	}
+	if !options.Until.IsZero() {
+		query = query.Where("created_at < ?", options.Until)
+	}
+
+	payload := make([]*model.AuditLog, 0)
+	if result := query.Find(&payload); result.Error != nil {
+		return nil, result.Error
+	}
+	return payload, nil
+}
+
+// WithTransaction runs the supplied function inside a transaction.
+func (db *sqldb) WithTransaction(ctx context.Context, fn func(DB) error, options ...*sql.TxOptions) error {
+	err := db.session(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(db.with(tx))
+	}, options...)
+	if retryable(err) {
+		return fmt.Errorf("%w: %w", ErrRetryable, err)
+	}
+	return err
+}
+
+// WithNestedTransaction runs the supplied function inside a savepoint of the current transaction.
+//
+// If the function fails, only the changes made since the savepoint are rolled back,
+// and the outer transaction carries on. Outside a transaction, it behaves like `WithTransaction`.
+func (db *sqldb) WithNestedTransaction(ctx context.Context, fn func(DB) error) (err error) {
+	if !db.transactional() {
+		return db.WithTransaction(ctx, fn)
+	}
+
+	tx := db.session(ctx)
+	name := "sp" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	if err := tx.SavePoint(name).Error; err != nil {
+		return err
+	}
+
+	// Roll back to the savepoint if the function panics, and re-panic.
+	panicked := true
+	defer func() {
+		if panicked {
+			tx.RollbackTo(name)
+		}
+	}()
+
+	err = fn(db.with(tx))
+	panicked = false
+	if err != nil {
+		if rollbackErr := tx.RollbackTo(name).Error; rollbackErr != nil {
+			return errors.Join(err, rollbackErr)
+		}
+	}
+	return err
+}
+
+// session returns the connection to run the queries on.
+//
+// Outside a transaction of the database layer, it's the request-scoped transaction opened by the `Transaction` middleware,
+// if the context carries one, so all the calls made while serving the request are committed or rolled back together.
+func (db *sqldb) session(ctx context.Context) *gorm.DB {
+	if !db.transactional() {
+		if tx, exists := middleware.TransactionFromContext(ctx); exists {
+			return tx.WithContext(ctx)
+		}
+	}
+	return db.conn.WithContext(ctx)
+}
+
+// transactional checks whether the connection of the database layer is a transaction.
+func (db *sqldb) transactional() bool {
+	committer, ok := db.conn.Statement.ConnPool.(gorm.TxCommitter)
+	return ok && committer != nil
+}
+
+// with returns a copy of the database layer which uses the supplied connection.
+func (db *sqldb) with(conn *gorm.DB) *sqldb {
+	return &sqldb{
+		conn:           conn,
+		logger:         db.logger,
+		auditRLS:       db.auditRLS,
+		partialResults: db.partialResults,
+		maxRows:        db.maxRows,
+	}
+}
+
+// audit reports the access to the record as denied by the RLS checks if the record exists but is owned by another user.
+func (db *sqldb) audit(ctx context.Context, operation string, ID uuid.UUID, claims middleware.JWTClaims) {
+	if !db.auditRLS {
+		return
+	}
+
+	// Probe the existence of the record without the RLS checks.
+	// The soft-deleted records are probed too, since `GetIncludingDeleted` reads them,
+	// but the records of the requester are skipped: they are missing because they're deleted, not because of RLS.
+	var count int64
+	query := db.session(ctx).Unscoped().Model(&model.Record{}).Where("id = ? AND user_id <> ?", ID, claims.XUserID)
+	if err := query.Count(&count).Error; err != nil || count == 0 {
+		return
+	}
+
+	rlsDenied.Add("records."+operation, 1)
+	db.logger.LogAttrs(ctx, slog.LevelWarn, "rls_denied",
+		slog.String("entity", "records"),
+		slog.String("operation", operation),
+		slog.String("record_id", ID.String()),
+		slog.String("user_id", claims.XUserID.String()),
+	)
+}
+
+// retryable checks whether the error is a serialization failure or a deadlock reported by PostgreSQL.
+//
+// Link: https://www.postgresql.org/docs/current/mvcc-serialization-failure-handling.html
+func retryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "40001" || pgErr.Code == "40P01"
+	}
+	return false
+}
+
+// duplicate checks whether the error is a violation of a unique constraint,
+// i.e. of the unique `(user_id, title)` index of the records.
+//
+// PostgreSQL reports it with the `23505` code, and SQLite with a "UNIQUE constraint failed" message.
+func duplicate(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "23505"
+	}
+	return errors.Is(err, gorm.ErrDuplicatedKey) || strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
+
+// contains returns the case-insensitive `LIKE` pattern matching the values which contain the supplied term.
+//
+// The wildcard characters in the term are escaped, so they are matched literally.
+func contains(term string) string {
+	return "%" + escape(term) + "%"
+}
+
+// relevance is the expression ranking the titles equal to the search term first, then the ones starting with it,
+// then the ones containing it, and the records only matching on their description last.
+// Its arguments are the lowercased term, and its `prefixed` and `contains` patterns.
+const relevance = `CASE WHEN LOWER(title) = ? THEN 0 WHEN LOWER(title) LIKE ? ESCAPE '\' THEN 1 WHEN LOWER(title) LIKE ? ESCAPE '\' THEN 2 ELSE 3 END`
+
+// prefixed returns the case-insensitive `LIKE` pattern matching the values which start with the supplied prefix.
+func prefixed(prefix string) string {
+	return escape(prefix) + "%"
+}
+
+// escape lowercases the term and escapes its `LIKE` wildcard characters, so they are matched literally.
+func escape(term string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(strings.ToLower(term))
+}
@@ -0,0 +1,205 @@
+//go:build integration
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mrinalwahal/boilerplate/model"
+	"github.com/mrinalwahal/boilerplate/pkg/db/postgrestest"
+	"github.com/mrinalwahal/boilerplate/pkg/middleware"
+	"gorm.io/gorm"
+)
+
+// configurePostgres opens a PostgreSQL database with the schema of the records and the audit trail.
+func configurePostgres(t *testing.T) *gorm.DB {
+	conn := postgrestest.Open(t)
+	if err := conn.AutoMigrate(&model.Record{}, &model.AuditLog{}); err != nil {
+		t.Fatalf("failed to migrate the schema: %v", err)
+	}
+	return conn
+}
+
+func Test_Integration_Database_RLS(t *testing.T) {
+
+	// Initialize the database.
+	db := &sqldb{
+		conn: configurePostgres(t),
+	}
+
+	owner := middleware.WithJWTClaims(context.Background(), middleware.JWTClaims{
+		XUserID: uuid.New(),
+	})
+	other := middleware.WithJWTClaims(context.Background(), middleware.JWTClaims{
+		XUserID: uuid.New(),
+	})
+
+	claims, _ := middleware.JWTClaimsFromContext(owner)
+	record, err := db.Create(owner, &CreateOptions{
+		Title:  "Test Record",
+		UserID: claims.XUserID,
+	})
+	if err != nil {
+		t.Fatalf("failed to seed the database: %v", err)
+	}
+
+	t.Run("get record as its owner", func(t *testing.T) {
+
+		if _, err := db.Get(owner, record.ID); err != nil {
+			t.Errorf("db.Get() error = %v", err)
+		}
+	})
+
+	t.Run("get record as a different user than the one who created it", func(t *testing.T) {
+
+		if _, err := db.Get(other, record.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("db.Get() error = %v, want %v", err, gorm.ErrRecordNotFound)
+		}
+	})
+
+	t.Run("list records as a different user than the one who created them", func(t *testing.T) {
+
+		records, err := db.List(other, &ListOptions{Title: record.Title})
+		if err != nil {
+			t.Fatalf("db.List() error = %v", err)
+		}
+		if len(records) != 0 {
+			t.Errorf("expected no records, got %d", len(records))
+		}
+	})
+}
+
+// Test_Integration_Database_AuditLogs_JSONB exercises the `jsonb` diffs of the audit trail, which SQLite stores as text.
+func Test_Integration_Database_AuditLogs_JSONB(t *testing.T) {
+
+	conn := configurePostgres(t)
+
+	// Initialize the database.
+	db := &sqldb{
+		conn: conn,
+	}
+
+	actor, other := uuid.New(), uuid.New()
+	entityID := uuid.New()
+
+	// Both the users change the same record, with the keys of the diffs in a different order.
+	for _, entry := range []struct {
+		actorID uuid.UUID
+		diff    string
+	}{
+		{actorID: actor, diff: `{"title": "Granted", "description": "By the owner"}`},
+		{actorID: other, diff: `{"description": "By someone else", "title": "Denied"}`},
+	} {
+		if _, err := db.CreateAuditLog(context.Background(), &CreateAuditLogOptions{
+			ActorID:  entry.actorID,
+			Action:   model.AuditActionUpdate,
+			Entity:   "record",
+			EntityID: entityID,
+			Diff:     json.RawMessage(entry.diff),
+		}); err != nil {
+			t.Fatalf("failed to seed the audit trail: %v", err)
+		}
+	}
+
+	ctx := middleware.WithJWTClaims(context.Background(), middleware.JWTClaims{
+		XUserID: actor,
+	})
+
+	t.Run("list only the entries of the actor", func(t *testing.T) {
+
+		logs, err := db.ListAuditLogs(ctx, &ListAuditLogsOptions{
+			EntityID: entityID,
+		})
+		if err != nil {
+			t.Fatalf("db.ListAuditLogs() error = %v", err)
+		}
+		if len(logs) != 1 || logs[0].ActorID != actor {
+			t.Fatalf("expected the single entry of the actor, got %v", logs)
+		}
+
+		// The diff comes back as an equivalent JSON object, normalized by `jsonb`.
+		var got, want map[string]any
+		if err := json.Unmarshal(logs[0].Diff, &got); err != nil {
+			t.Fatalf("failed to decode the diff: %v", err)
+		}
+		json.Unmarshal([]byte(`{"title": "Granted", "description": "By the owner"}`), &want)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("diff = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("match the diffs by containment within the entries of the actor", func(t *testing.T) {
+
+		// The containment operator only exists for `jsonb`, so this query fails on SQLite.
+		count := func(actorID uuid.UUID, contained string) int64 {
+			var count int64
+			if err := conn.Model(&model.AuditLog{}).
+				Where("entity_id = ? AND actor_id = ? AND diff @> ?::jsonb", entityID, actorID, contained).
+				Count(&count).Error; err != nil {
+				t.Fatalf("failed to query the diffs: %v", err)
+			}
+			return count
+		}
+
+		if got := count(actor, `{"title": "Granted"}`); got != 1 {
+			t.Errorf("expected the diff of the actor to match, got %d", got)
+		}
+		if got := count(actor, `{"title": "Denied"}`); got != 0 {
+			t.Errorf("expected the diff of the other user not to match, got %d", got)
+		}
+	})
+}
+
+// Test_Integration_Database_WithTransaction_Serializable relies on the serializable isolation level of PostgreSQL.
+func Test_Integration_Database_WithTransaction_Serializable(t *testing.T) {
+
+	// Initialize the database.
+	db := &sqldb{
+		conn: configurePostgres(t),
+	}
+
+	ctx := context.Background()
+	title := uuid.NewString()
+
+	// Both the transactions read the same predicate and then write to it, causing a write skew
+	// which PostgreSQL rejects under the serializable isolation level.
+	var wg sync.WaitGroup
+	read := make(chan struct{}, 2)
+	errs := make([]error, 2)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = db.WithTransaction(ctx, func(tx DB) error {
+				if _, err := tx.List(ctx, &ListOptions{Title: title}); err != nil {
+					return err
+				}
+
+				// Wait for the other transaction to read as well.
+				read <- struct{}{}
+				for deadline := time.Now().Add(5 * time.Second); len(read) < 2 && time.Now().Before(deadline); {
+					time.Sleep(time.Millisecond)
+				}
+
+				_, err := tx.Create(ctx, &CreateOptions{
+					Title:  title,
+					UserID: uuid.New(),
+				})
+				return err
+			}, &sql.TxOptions{Isolation: sql.LevelSerializable})
+		}(i)
+	}
+	wg.Wait()
+
+	if !errors.Is(errs[0], ErrRetryable) && !errors.Is(errs[1], ErrRetryable) {
+		t.Fatalf("expected one of the transactions to fail with a retryable error, got %v and %v", errs[0], errs[1])
+	}
+}
@@ -2,11 +2,18 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"expvar"
 	"fmt"
+	"reflect"
+	"sort"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/mrinalwahal/boilerplate/model"
+	"github.com/mrinalwahal/boilerplate/pkg/db/unbounded"
 	"github.com/mrinalwahal/boilerplate/pkg/middleware"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -29,7 +36,7 @@ func configure(t *testing.T) *testsqldbconfig {
 	}
 
 	// Migrate the schema.
-	if err := conn.AutoMigrate(&model.Record{}); err != nil {
+	if err := conn.AutoMigrate(&model.Record{}, &model.AuditLog{}); err != nil {
 		t.Fatalf("failed to migrate the schema: %v", err)
 	}
 
@@ -51,6 +58,19 @@ func configure(t *testing.T) *testsqldbconfig {
 	}
 }
 
+func Test_Database_Indexes(t *testing.T) {
+
+	// Setup the test config, which migrates the schema.
+	config := configure(t)
+
+	// The date-range filters rely on the index of the creation time.
+	for _, index := range []string{"idx_records_created_at", "idx_records_user_id_title"} {
+		if !config.conn.Migrator().HasIndex(&model.Record{}, index) {
+			t.Errorf("expected the index %q to be created", index)
+		}
+	}
+}
+
 func Test_NewSQLDB(t *testing.T) {
 
 	t.Run("create db with nil config", func(t *testing.T) {
@@ -194,7 +214,7 @@ func Test_Database_List(t *testing.T) {
 	t.Run("list records as a different user than the one who created them", func(t *testing.T) {
 
 		// Add JWT claims to the context.
-		ctx := context.WithValue(context.Background(), middleware.XJWTClaims, middleware.JWTClaims{
+		ctx := middleware.WithJWTClaims(context.Background(), middleware.JWTClaims{
 			XUserID: uuid.New(),
 		})
 
@@ -279,9 +299,46 @@ func Test_Database_List(t *testing.T) {
 			t.Fatalf("expected first record to be 'Record 4', got '%s'", records[0].Title)
 		}
 	})
+
+	t.Run("list w/ search term present only in the description", func(t *testing.T) {
+
+		seed, err := db.Create(ctx, &CreateOptions{
+			Title:       "Groceries",
+			Description: "Buy Avocados and 100% juice",
+			UserID:      uuid.New(),
+		})
+		if err != nil {
+			t.Fatalf("failed to seed the database: %v", err)
+		}
+
+		records, err := db.List(ctx, &ListOptions{
+			Search: "avocado",
+		})
+		if err != nil {
+			t.Fatalf("failed to list records: %v", err)
+		}
+
+		if len(records) != 1 || records[0].ID != seed.ID {
+			t.Fatalf("expected only the seeded record, got %v", records)
+		}
+	})
+
+	t.Run("list w/ search term containing wildcards", func(t *testing.T) {
+
+		records, err := db.List(ctx, &ListOptions{
+			Search: "1_0%",
+		})
+		if err != nil {
+			t.Fatalf("failed to list records: %v", err)
+		}
+
+		if len(records) != 0 {
+			t.Fatalf("expected the wildcards to be matched literally, got %d records", len(records))
+		}
+	})
 }
 
-func Test_Database_Get(t *testing.T) {
+func Test_Database_ListChan(t *testing.T) {
 
 	// Setup the test config.
 	config := configure(t)
@@ -291,136 +348,285 @@ func Test_Database_Get(t *testing.T) {
 		conn: config.conn,
 	}
 
-	// Seed the database with sample records.
-	options := CreateOptions{
-		Title:  "Test Record",
-		UserID: uuid.New(),
-	}
-
 	ctx := context.Background()
 
-	seed, err := db.Create(ctx, &options)
-	if err != nil {
-		t.Fatalf("failed to seed the database: %v", err)
+	// Seed the database with some records.
+	for i := 0; i < 5; i++ {
+		_, err := db.Create(ctx, &CreateOptions{
+			Title:  fmt.Sprintf("Streamed %d", i),
+			UserID: uuid.New(),
+		})
+		if err != nil {
+			t.Fatalf("failed to seed the database: %v", err)
+		}
 	}
 
-	t.Run("get record with nil ID", func(t *testing.T) {
+	t.Run("stream all records", func(t *testing.T) {
 
-		_, err := db.Get(ctx, uuid.Nil)
-		if err == nil {
-			t.Errorf("service.Get() error = %v, wantErr %v", err, true)
+		records, errc := db.ListChan(ctx, &ListOptions{
+			Search:         "streamed",
+			OrderBy:        "title",
+			OrderDirection: "asc",
+		})
+
+		var titles []string
+		for record := range records {
+			titles = append(titles, record.Title)
+		}
+		if err := <-errc; err != nil {
+			t.Fatalf("failed to stream records: %v", err)
+		}
+
+		want := []string{"Streamed 0", "Streamed 1", "Streamed 2", "Streamed 3", "Streamed 4"}
+		if !reflect.DeepEqual(titles, want) {
+			t.Fatalf("expected titles %v, got %v", want, titles)
 		}
 	})
 
-	t.Run("get record with valid ID", func(t *testing.T) {
+	t.Run("stream records with invalid options", func(t *testing.T) {
 
-		record, err := db.Get(ctx, seed.ID)
-		if err != nil {
-			t.Fatalf("failed to get record: %v", err)
-		}
+		records, errc := db.ListChan(ctx, &ListOptions{
+			Skip: -1,
+		})
 
-		if record.ID != seed.ID {
-			t.Fatalf("expected retrieved record to equal seed, got = %v", record)
+		for range records {
+			t.Fatalf("expected no records")
+		}
+		if err := <-errc; err == nil {
+			t.Fatalf("expected an error, got nil")
 		}
 	})
 
-	t.Run("get record as a different user than the one who created it", func(t *testing.T) {
+	t.Run("stop streaming when the context is cancelled", func(t *testing.T) {
 
-		// Add JWT claims to the context.
-		ctx := context.WithValue(context.Background(), middleware.XJWTClaims, middleware.JWTClaims{
-			XUserID: uuid.New(),
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		records, errc := db.ListChan(ctx, &ListOptions{
+			Search: "streamed",
 		})
 
-		_, err := db.Get(ctx, seed.ID)
-		if err == nil {
-			t.Errorf("service.Get() error = %v, wantErr %v", err, true)
+		// Consume a single record and walk away.
+		if _, ok := <-records; !ok {
+			t.Fatalf("expected a record before cancelling")
+		}
+		cancel()
+
+		// The producer must stop and close both channels.
+		for range records {
+		}
+		if err := <-errc; !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected error %v, got %v", context.Canceled, err)
 		}
 	})
 }
 
-func Test_Database_Update(t *testing.T) {
+func Test_Database_List_MaxRows(t *testing.T) {
 
 	// Setup the test config.
 	config := configure(t)
 
-	// Initialize the database.
+	// Initialize the database with a hard cap of 2 records per list.
 	db := &sqldb{
-		conn: config.conn,
+		conn:    config.conn,
+		maxRows: 2,
 	}
 
-	// Seed the database with sample records.
-	options := CreateOptions{
-		Title:  "Test Record",
-		UserID: uuid.New(),
+	ctx := context.Background()
+
+	// Seed the database with records of the same title for distinct users, so the lists can filter them.
+	seed := func(t *testing.T, title string, count int) {
+		for i := 0; i < count; i++ {
+			if _, err := db.Create(ctx, &CreateOptions{
+				Title:  title,
+				UserID: uuid.New(),
+			}); err != nil {
+				t.Fatalf("failed to seed the database: %v", err)
+			}
+		}
+	}
+	seed(t, "Capped Record", 3)
+	seed(t, "Fitting Record", 2)
+
+	tests := []struct {
+		name    string
+		options *ListOptions
+		want    int
+		wantErr error
+	}{
+		{
+			name:    "list more records than the cap w/o limit",
+			options: &ListOptions{Title: "Capped Record"},
+			wantErr: ErrResultTooLarge,
+		},
+		{
+			name:    "list more records than the cap w/ a larger limit",
+			options: &ListOptions{Title: "Capped Record", Limit: 10},
+			wantErr: ErrResultTooLarge,
+		},
+		{
+			name:    "list more records than the cap w/ a limit within it",
+			options: &ListOptions{Title: "Capped Record", Limit: 2},
+			want:    2,
+		},
+		{
+			name:    "list as many records as the cap w/o limit",
+			options: &ListOptions{Title: "Fitting Record"},
+			want:    2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := db.List(ctx, tt.options)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("db.List() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(records) != tt.want {
+				t.Errorf("db.List() = %d records, want %d", len(records), tt.want)
+			}
+		})
+	}
+
+	// The transactional database layers keep the cap.
+	for name, run := range map[string]func(context.Context, func(DB) error) error{
+		"transaction": func(ctx context.Context, fn func(DB) error) error {
+			return db.WithTransaction(ctx, fn)
+		},
+		"nested transaction": db.WithNestedTransaction,
+	} {
+		t.Run("list more records than the cap inside a "+name, func(t *testing.T) {
+			err := run(ctx, func(tx DB) error {
+				_, err := tx.List(ctx, &ListOptions{Title: "Capped Record"})
+				return err
+			})
+			if !errors.Is(err, ErrResultTooLarge) {
+				t.Fatalf("tx.List() error = %v, want %v", err, ErrResultTooLarge)
+			}
+		})
+	}
+}
+
+func Test_Database_Unbounded(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	// Fail the reads which list the records without a limit.
+	if err := config.conn.Use(unbounded.Plugin{
+		Tables: []string{"records"},
+		Strict: true,
+	}); err != nil {
+		t.Fatalf("failed to register the plugin: %v", err)
+	}
+
+	// Initialize the database.
+	db := &sqldb{
+		conn: config.conn,
 	}
 
 	ctx := context.Background()
 
-	seed, err := db.Create(ctx, &options)
-	if err != nil {
+	if _, err := db.Create(ctx, &CreateOptions{
+		Title:  "Test Record",
+		UserID: uuid.New(),
+	}); err != nil {
 		t.Fatalf("failed to seed the database: %v", err)
 	}
 
-	t.Run("update record with nil ID", func(t *testing.T) {
+	t.Run("list records w/o limit", func(t *testing.T) {
 
-		_, err := db.Update(ctx, uuid.Nil, &UpdateOptions{
-			Title: "Updated Record",
-		})
-		if err == nil {
-			t.Errorf("service.Update() error = %v, wantErr %v", err, true)
+		if _, err := db.List(ctx, &ListOptions{}); !errors.Is(err, unbounded.ErrUnbounded) {
+			t.Errorf("db.List() error = %v, want %v", err, unbounded.ErrUnbounded)
 		}
 	})
 
-	t.Run("update record with nil options", func(t *testing.T) {
+	t.Run("list records w/ limit", func(t *testing.T) {
 
-		_, err := db.Update(ctx, seed.ID, nil)
-		if err == nil {
-			t.Errorf("service.Update() error = %v, wantErr %v", err, true)
+		if _, err := db.List(ctx, &ListOptions{Limit: 10}); err != nil {
+			t.Errorf("db.List() error = %v", err)
 		}
 	})
 
-	t.Run("update record with invalid options", func(t *testing.T) {
+	t.Run("stream records w/o limit", func(t *testing.T) {
 
-		_, err := db.Update(ctx, seed.ID, &UpdateOptions{
-			Title: "",
-		})
-		if err == nil {
-			t.Errorf("service.Update() error = %v, wantErr %v", err, true)
+		records, errc := db.ListChan(ctx, &ListOptions{})
+		count := 0
+		for range records {
+			count++
+		}
+		if err := <-errc; err != nil {
+			t.Fatalf("db.ListChan() error = %v", err)
+		}
+		if count == 0 {
+			t.Errorf("expected the streamed records")
 		}
 	})
+}
 
-	t.Run("update record with valid options", func(t *testing.T) {
+func Test_Database_List_StableOrder(t *testing.T) {
 
-		updatedTitle := "Updated Record"
-		record, err := db.Update(ctx, seed.ID, &UpdateOptions{
-			Title: updatedTitle,
+	// Setup the test config.
+	config := configure(t)
+
+	// Initialize the database.
+	db := &sqldb{
+		conn: config.conn,
+	}
+
+	ctx := context.Background()
+
+	// Seed the database with records which share the same title.
+	for i := 0; i < 6; i++ {
+		_, err := db.Create(ctx, &CreateOptions{
+			Title:  "Duplicate",
+			UserID: uuid.New(),
 		})
 		if err != nil {
-			t.Fatalf("failed to update record: %v", err)
+			t.Fatalf("failed to seed the database: %v", err)
 		}
+	}
 
-		if record.Title != updatedTitle {
-			t.Fatalf("expected record title to be 'Updated Record', got '%s'", record.Title)
+	// Fetch the same pages twice and compare them.
+	page := func(skip int) []uuid.UUID {
+		records, err := db.List(ctx, &ListOptions{
+			Skip:           skip,
+			Limit:          3,
+			OrderBy:        "title",
+			OrderDirection: "asc",
+		})
+		if err != nil {
+			t.Fatalf("failed to list records: %v", err)
 		}
-	})
+		var ids []uuid.UUID
+		for _, record := range records {
+			ids = append(ids, record.ID)
+		}
+		return ids
+	}
 
-	t.Run("update record as a different user than the one who created it", func(t *testing.T) {
+	first, second := page(0), page(3)
+	if len(first) != 3 || len(second) != 3 {
+		t.Fatalf("expected 2 pages of 3 records, got %d and %d", len(first), len(second))
+	}
 
-		// Add JWT claims to the context.
-		ctx := context.WithValue(context.Background(), middleware.XJWTClaims, middleware.JWTClaims{
-			XUserID: uuid.New(),
-		})
+	seen := make(map[uuid.UUID]bool)
+	for _, id := range append(first, second...) {
+		if seen[id] {
+			t.Fatalf("expected the pages to be disjoint, got record %s twice", id)
+		}
+		seen[id] = true
+	}
 
-		_, err := db.Update(ctx, seed.ID, &UpdateOptions{
-			Title: "Updated Record",
-		})
-		if err == nil {
-			t.Errorf("service.Update() error = %v, wantErr %v", err, true)
+	// The pages are sorted by the primary key within the equal titles.
+	all := append(first, second...)
+	for i := 1; i < len(all); i++ {
+		if all[i-1].String() > all[i].String() {
+			t.Fatalf("expected the records to be ordered by id, got %v", all)
 		}
-	})
+	}
 }
 
-func Test_Database_Delete(t *testing.T) {
+func Test_Database_List_Relevance(t *testing.T) {
 
 	// Setup the test config.
 	config := configure(t)
@@ -432,47 +638,1886 @@ func Test_Database_Delete(t *testing.T) {
 
 	ctx := context.Background()
 
-	t.Run("delete record with nil ID", func(t *testing.T) {
-
-		err := db.Delete(ctx, uuid.Nil)
-		if err == nil {
-			t.Errorf("service.Delete() error = %v, wantErr %v", err, true)
+	// Seed the database with the matches in the reverse order of their relevance.
+	for _, seed := range []CreateOptions{
+		{Title: "Groceries", Description: "Oat milk"},
+		{Title: "Oat Milk"},
+		{Title: "Milky Way"},
+		{Title: "Milk"},
+		{Title: "Bread"},
+	} {
+		seed.UserID = uuid.New()
+		if _, err := db.Create(ctx, &seed); err != nil {
+			t.Fatalf("failed to seed the database: %v", err)
 		}
-	})
+	}
 
-	t.Run("delete record with valid ID", func(t *testing.T) {
+	t.Run("list w/ relevance order", func(t *testing.T) {
 
-		seed, err := db.Create(ctx, &CreateOptions{
-			Title:  "Test Record",
-			UserID: uuid.New(),
+		records, err := db.List(ctx, &ListOptions{
+			Search:           "MILK",
+			OrderByRelevance: true,
+			OrderBy:          "created_at",
+			OrderDirection:   "asc",
 		})
 		if err != nil {
-			t.Fatalf("failed to seed the database: %v", err)
+			t.Fatalf("failed to list records: %v", err)
 		}
 
-		if err := db.Delete(ctx, seed.ID); err != nil {
-			t.Fatalf("failed to delete record: %v", err)
+		want := []string{"Milk", "Milky Way", "Oat Milk", "Groceries"}
+		if len(records) != len(want) {
+			t.Fatalf("expected %d records, got %d", len(want), len(records))
+		}
+		for i, record := range records {
+			if record.Title != want[i] {
+				t.Fatalf("expected record %d to be %q, got %q", i, want[i], record.Title)
+			}
 		}
 	})
 
-	t.Run("delete record as a different user than the one who created it", func(t *testing.T) {
+	t.Run("page w/ relevance as the only order", func(t *testing.T) {
+
+		// Seed the records which rank equally, so only the tiebreaker orders them.
+		var want []string
+		for i := 0; i < 4; i++ {
+			record, err := db.Create(ctx, &CreateOptions{
+				Title:  fmt.Sprintf("Tied %d", i),
+				UserID: uuid.New(),
+			})
+			if err != nil {
+				t.Fatalf("failed to seed the database: %v", err)
+			}
+			want = append(want, record.ID.String())
+		}
+		sort.Strings(want)
+
+		for skip := range want {
+			records, err := db.List(ctx, &ListOptions{
+				Search:           "tied",
+				OrderByRelevance: true,
+				Skip:             skip,
+				Limit:            1,
+			})
+			if err != nil {
+				t.Fatalf("failed to list records: %v", err)
+			}
+			if len(records) != 1 || records[0].ID.String() != want[skip] {
+				t.Fatalf("expected the page %d to hold the record %s, got %v", skip, want[skip], records)
+			}
+		}
+	})
 
-		seed, err := db.Create(ctx, &CreateOptions{
-			Title:  "Test Record",
-			UserID: uuid.New(),
+	t.Run("list w/ relevance order but w/o search term", func(t *testing.T) {
+
+		_, err := db.List(ctx, &ListOptions{
+			OrderByRelevance: true,
+		})
+		if !errors.Is(err, ErrInvalidFilters) {
+			t.Fatalf("db.List() error = %v, want %v", err, ErrInvalidFilters)
+		}
+	})
+}
+
+func Test_Database_List_Deleted(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	// Initialize the database.
+	db := &sqldb{
+		conn: config.conn,
+	}
+
+	claims := middleware.JWTClaims{
+		XUserID: uuid.New(),
+	}
+	ctx := middleware.WithJWTClaims(context.Background(), claims)
+
+	// Seed the database with an active record and two records deleted one after the other.
+	var seeded []*model.Record
+	for _, title := range []string{"Active", "Deleted First", "Deleted Last"} {
+		record, err := db.Create(ctx, &CreateOptions{
+			Title:  title,
+			UserID: claims.XUserID,
 		})
 		if err != nil {
 			t.Fatalf("failed to seed the database: %v", err)
 		}
+		seeded = append(seeded, record)
+	}
+	for _, record := range seeded[1:] {
+		if err := db.Delete(ctx, record.ID); err != nil {
+			t.Fatalf("failed to delete the record: %v", err)
+		}
+		time.Sleep(time.Millisecond)
+	}
 
-		// Add JWT claims to the context.
-		ctx := context.WithValue(context.Background(), middleware.XJWTClaims, middleware.JWTClaims{
-			XUserID: uuid.New(),
+	// Seed a deleted record of another user, which the RLS checks must hide.
+	other, err := db.Create(ctx, &CreateOptions{
+		Title:  "Someone Else's",
+		UserID: uuid.New(),
+	})
+	if err != nil {
+		t.Fatalf("failed to seed the database: %v", err)
+	}
+	if err := db.Delete(context.Background(), other.ID); err != nil {
+		t.Fatalf("failed to delete the record: %v", err)
+	}
+
+	titles := func(records []*model.Record) []string {
+		var titles []string
+		for _, record := range records {
+			titles = append(titles, record.Title)
+		}
+		return titles
+	}
+
+	tests := []struct {
+		name    string
+		options *ListOptions
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "list active records only",
+			options: &ListOptions{OrderBy: "title", OrderDirection: "asc"},
+			want:    []string{"Active"},
+		},
+		{
+			name:    "list records including deleted",
+			options: &ListOptions{IncludeDeleted: true, OrderBy: "title", OrderDirection: "asc"},
+			want:    []string{"Active", "Deleted First", "Deleted Last"},
+		},
+		{
+			name:    "list deleted records only, the most recently deleted first",
+			options: &ListOptions{DeletedOnly: true},
+			want:    []string{"Deleted Last", "Deleted First"},
+		},
+		{
+			name:    "list w/ both deleted modes",
+			options: &ListOptions{IncludeDeleted: true, DeletedOnly: true},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			records, err := db.List(ctx, tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("db.List() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got := titles(records); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Fatalf("db.List() = %v, want %v", got, tt.want)
+			}
 		})
+	}
+}
 
-		err = db.Delete(ctx, seed.ID)
-		if err == nil {
-			t.Errorf("service.Delete() error = %v, wantErr %v", err, true)
+func Test_Database_Get(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	// Initialize the database.
+	db := &sqldb{
+		conn: config.conn,
+	}
+
+	// Seed the database with sample records.
+	options := CreateOptions{
+		Title:  "Test Record",
+		UserID: uuid.New(),
+	}
+
+	ctx := context.Background()
+
+	seed, err := db.Create(ctx, &options)
+	if err != nil {
+		t.Fatalf("failed to seed the database: %v", err)
+	}
+
+	t.Run("get record with nil ID", func(t *testing.T) {
+
+		_, err := db.Get(ctx, uuid.Nil)
+		if err == nil {
+			t.Errorf("service.Get() error = %v, wantErr %v", err, true)
+		}
+	})
+
+	t.Run("get record with valid ID", func(t *testing.T) {
+
+		record, err := db.Get(ctx, seed.ID)
+		if err != nil {
+			t.Fatalf("failed to get record: %v", err)
+		}
+
+		if record.ID != seed.ID {
+			t.Fatalf("expected retrieved record to equal seed, got = %v", record)
+		}
+	})
+
+	t.Run("get record as a different user than the one who created it", func(t *testing.T) {
+
+		// Add JWT claims to the context.
+		ctx := middleware.WithJWTClaims(context.Background(), middleware.JWTClaims{
+			XUserID: uuid.New(),
+		})
+
+		_, err := db.Get(ctx, seed.ID)
+		if err == nil {
+			t.Errorf("service.Get() error = %v, wantErr %v", err, true)
+		}
+	})
+}
+
+func Test_Database_GetByIDs(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	// Initialize the database.
+	db := &sqldb{
+		conn: config.conn,
+	}
+
+	owner := uuid.New()
+	ctx := context.Background()
+
+	// Seed the database with two records of the owner and one of another user.
+	var seeds []*model.Record
+	for i, userID := range []uuid.UUID{owner, owner, uuid.New()} {
+		seed, err := db.Create(ctx, &CreateOptions{
+			Title:  fmt.Sprintf("Record %d", i),
+			UserID: userID,
+		})
+		if err != nil {
+			t.Fatalf("failed to seed the database: %v", err)
+		}
+		seeds = append(seeds, seed)
+	}
+	absent := uuid.New()
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		IDs     []uuid.UUID
+		want    []uuid.UUID
+		wantErr error
+	}{
+		{
+			name: "get present and absent records",
+			ctx:  ctx,
+			IDs:  []uuid.UUID{seeds[0].ID, absent, seeds[2].ID},
+			want: []uuid.UUID{seeds[0].ID, seeds[2].ID},
+		},
+		{
+			name: "get records as their owner",
+			ctx:  middleware.WithJWTClaims(ctx, middleware.JWTClaims{XUserID: owner}),
+			IDs:  []uuid.UUID{seeds[0].ID, seeds[1].ID, seeds[2].ID},
+			want: []uuid.UUID{seeds[0].ID, seeds[1].ID},
+		},
+		{
+			name: "get records w/ duplicate IDs",
+			ctx:  ctx,
+			IDs:  []uuid.UUID{seeds[1].ID, seeds[1].ID},
+			want: []uuid.UUID{seeds[1].ID},
+		},
+		{
+			name: "get records w/o IDs",
+			ctx:  ctx,
+		},
+		{
+			name:    "get records w/ nil ID",
+			ctx:     ctx,
+			IDs:     []uuid.UUID{seeds[0].ID, uuid.Nil},
+			wantErr: ErrInvalidRecordID,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := db.GetByIDs(tt.ctx, tt.IDs)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("db.GetByIDs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("db.GetByIDs() = %d records, want %d", len(got), len(tt.want))
+			}
+			for _, ID := range tt.want {
+				if record, exists := got[ID]; !exists || record.ID != ID {
+					t.Errorf("db.GetByIDs() is missing record %v", ID)
+				}
+			}
+		})
+	}
+}
+
+func Test_Database_Exists(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	// Initialize the database.
+	db := &sqldb{
+		conn: config.conn,
+	}
+
+	owner := uuid.New()
+	ctx := context.Background()
+
+	// Seed the database with a sample record.
+	seed, err := db.Create(ctx, &CreateOptions{
+		Title:  "Test Record",
+		UserID: owner,
+	})
+	if err != nil {
+		t.Fatalf("failed to seed the database: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		ID      uuid.UUID
+		want    bool
+		wantErr bool
+	}{
+		{
+			name:    "check record with nil ID",
+			ctx:     ctx,
+			ID:      uuid.Nil,
+			wantErr: true,
+		},
+		{
+			name: "check record without JWT claims",
+			ctx:  ctx,
+			ID:   seed.ID,
+			want: true,
+		},
+		{
+			name: "check record as its owner",
+			ctx:  middleware.WithJWTClaims(ctx, middleware.JWTClaims{XUserID: owner}),
+			ID:   seed.ID,
+			want: true,
+		},
+		{
+			name: "check record as a different user than the one who created it",
+			ctx:  middleware.WithJWTClaims(ctx, middleware.JWTClaims{XUserID: uuid.New()}),
+			ID:   seed.ID,
+			want: false,
+		},
+		{
+			name: "check nonexistent record",
+			ctx:  ctx,
+			ID:   uuid.New(),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := db.Exists(tt.ctx, tt.ID)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("db.Exists() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("db.Exists() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Database_CountDeleted(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	// Initialize the database.
+	db := &sqldb{
+		conn: config.conn,
+	}
+
+	owner, other := uuid.New(), uuid.New()
+	ctx := context.Background()
+
+	// Seed the database with live and deleted records of both the users.
+	for _, seed := range []struct {
+		userID  uuid.UUID
+		deleted bool
+	}{
+		{userID: owner, deleted: true},
+		{userID: owner, deleted: true},
+		{userID: owner},
+		{userID: other, deleted: true},
+	} {
+		record, err := db.Create(ctx, &CreateOptions{
+			Title:  "Test Record",
+			UserID: seed.userID,
+		})
+		if err != nil {
+			t.Fatalf("failed to seed the database: %v", err)
+		}
+		if seed.deleted {
+			if err := db.Delete(ctx, record.ID); err != nil {
+				t.Fatalf("failed to seed the database: %v", err)
+			}
+		}
+	}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want int64
+	}{
+		{
+			name: "count deleted records without JWT claims",
+			ctx:  ctx,
+			want: 3,
+		},
+		{
+			name: "count deleted records as their owner",
+			ctx:  middleware.WithJWTClaims(ctx, middleware.JWTClaims{XUserID: owner}),
+			want: 2,
+		},
+		{
+			name: "count deleted records as a user who has none",
+			ctx:  middleware.WithJWTClaims(ctx, middleware.JWTClaims{XUserID: uuid.New()}),
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := db.CountDeleted(tt.ctx)
+			if err != nil {
+				t.Fatalf("db.CountDeleted() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("db.CountDeleted() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Database_Result(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	// Initialize the database.
+	db := &sqldb{
+		conn: config.conn,
+	}
+
+	owner := uuid.New()
+	ctx := middleware.WithJWTClaims(context.Background(), middleware.JWTClaims{XUserID: owner})
+	other := middleware.WithJWTClaims(context.Background(), middleware.JWTClaims{XUserID: uuid.New()})
+
+	// Seed the database with a sample record.
+	seed, err := db.Create(ctx, &CreateOptions{
+		Title:  "Test Record",
+		UserID: owner,
+	})
+	if err != nil {
+		t.Fatalf("failed to seed the database: %v", err)
+	}
+
+	t.Run("update record and report the updated count", func(t *testing.T) {
+
+		got, err := db.UpdateResult(ctx, seed.ID, &UpdateOptions{
+			Title: "Updated Record",
+		})
+		if err != nil {
+			t.Fatalf("db.UpdateResult() error = %v", err)
+		}
+		if got.RowsAffected != 1 {
+			t.Errorf("db.UpdateResult() rows affected = %v, want %v", got.RowsAffected, 1)
+		}
+		if got.Record == nil || got.Record.Title != "Updated Record" {
+			t.Errorf("db.UpdateResult() record = %v, want the updated record", got.Record)
+		}
+	})
+
+	t.Run("delete record as a different user than the one who created it", func(t *testing.T) {
+
+		got, err := db.DeleteResult(other, seed.ID)
+		if !errors.Is(err, ErrNoRowsAffected) {
+			t.Fatalf("db.DeleteResult() error = %v, want %v", err, ErrNoRowsAffected)
+		}
+		if got != nil {
+			t.Errorf("db.DeleteResult() = %v, want nil", got)
+		}
+	})
+
+	t.Run("delete record and report the deleted count", func(t *testing.T) {
+
+		got, err := db.DeleteResult(ctx, seed.ID)
+		if err != nil {
+			t.Fatalf("db.DeleteResult() error = %v", err)
+		}
+		if got.RowsAffected != 1 {
+			t.Errorf("db.DeleteResult() rows affected = %v, want %v", got.RowsAffected, 1)
+		}
+	})
+}
+
+func Test_Database_Touch(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	// Initialize the database.
+	db := &sqldb{
+		conn: config.conn,
+	}
+
+	owner := uuid.New()
+	ctx := middleware.WithJWTClaims(context.Background(), middleware.JWTClaims{XUserID: owner})
+
+	// Seed the database with a sample record.
+	seed, err := db.Create(ctx, &CreateOptions{
+		Title:       "Test Record",
+		Description: "Test Description",
+		UserID:      owner,
+	})
+	if err != nil {
+		t.Fatalf("failed to seed the database: %v", err)
+	}
+
+	t.Run("touch record as its owner", func(t *testing.T) {
+
+		// Let the clock move past the creation time.
+		time.Sleep(10 * time.Millisecond)
+
+		if err := db.Touch(ctx, seed.ID); err != nil {
+			t.Fatalf("db.Touch() error = %v", err)
+		}
+
+		got, err := db.Get(ctx, seed.ID)
+		if err != nil {
+			t.Fatalf("db.Get() error = %v", err)
+		}
+		if !got.UpdatedAt.After(seed.UpdatedAt) {
+			t.Errorf("UpdatedAt = %v, want after %v", got.UpdatedAt, seed.UpdatedAt)
+		}
+		if got.Title != seed.Title || got.Description != seed.Description || got.UserID != seed.UserID || !got.CreatedAt.Equal(seed.CreatedAt) {
+			t.Errorf("db.Touch() changed the record: got %+v, want %+v", got, seed)
+		}
+	})
+
+	t.Run("touch record as a different user than the one who created it", func(t *testing.T) {
+
+		other := middleware.WithJWTClaims(context.Background(), middleware.JWTClaims{XUserID: uuid.New()})
+		if err := db.Touch(other, seed.ID); !errors.Is(err, ErrNoRowsAffected) {
+			t.Errorf("db.Touch() error = %v, want %v", err, ErrNoRowsAffected)
+		}
+	})
+
+	t.Run("touch nonexistent record", func(t *testing.T) {
+
+		if err := db.Touch(ctx, uuid.New()); !errors.Is(err, ErrNoRowsAffected) {
+			t.Errorf("db.Touch() error = %v, want %v", err, ErrNoRowsAffected)
+		}
+	})
+
+	t.Run("touch record with nil ID", func(t *testing.T) {
+
+		if err := db.Touch(ctx, uuid.Nil); !errors.Is(err, ErrInvalidRecordID) {
+			t.Errorf("db.Touch() error = %v, want %v", err, ErrInvalidRecordID)
+		}
+	})
+}
+
+func Test_Database_GetIncludingDeleted(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	// Initialize the database.
+	db := &sqldb{
+		conn: config.conn,
+	}
+
+	ctx := context.Background()
+	owner := uuid.New()
+
+	// Seed the database with a soft-deleted record.
+	seed, err := db.Create(ctx, &CreateOptions{
+		Title:  "Trashed Record",
+		UserID: owner,
+	})
+	if err != nil {
+		t.Fatalf("failed to seed the database: %v", err)
+	}
+	if err := db.Delete(ctx, seed.ID); err != nil {
+		t.Fatalf("failed to delete the seed: %v", err)
+	}
+
+	t.Run("get deleted record", func(t *testing.T) {
+
+		if _, err := db.Get(ctx, seed.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("expected Get() error %v, got %v", gorm.ErrRecordNotFound, err)
+		}
+
+		record, err := db.GetIncludingDeleted(ctx, seed.ID)
+		if err != nil {
+			t.Fatalf("failed to get the deleted record: %v", err)
+		}
+
+		if record.ID != seed.ID || !record.DeletedAt.Valid {
+			t.Fatalf("expected the deleted seed, got = %v", record)
+		}
+	})
+
+	t.Run("get deleted record as the owner", func(t *testing.T) {
+
+		// Add JWT claims to the context.
+		ctx := middleware.WithJWTClaims(context.Background(), middleware.JWTClaims{
+			XUserID: owner,
+		})
+
+		if _, err := db.GetIncludingDeleted(ctx, seed.ID); err != nil {
+			t.Fatalf("failed to get the deleted record: %v", err)
+		}
+	})
+
+	t.Run("get deleted record as a different user than the one who created it", func(t *testing.T) {
+
+		// Add JWT claims to the context.
+		ctx := middleware.WithJWTClaims(context.Background(), middleware.JWTClaims{
+			XUserID: uuid.New(),
+		})
+
+		if _, err := db.GetIncludingDeleted(ctx, seed.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("expected error %v, got %v", gorm.ErrRecordNotFound, err)
+		}
+	})
+
+	t.Run("get record with nil ID", func(t *testing.T) {
+
+		if _, err := db.GetIncludingDeleted(ctx, uuid.Nil); err != ErrInvalidRecordID {
+			t.Fatalf("expected error %v, got %v", ErrInvalidRecordID, err)
+		}
+	})
+}
+
+func Test_Database_Update(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	// Initialize the database.
+	db := &sqldb{
+		conn: config.conn,
+	}
+
+	// Seed the database with sample records.
+	options := CreateOptions{
+		Title:  "Test Record",
+		UserID: uuid.New(),
+	}
+
+	ctx := context.Background()
+
+	seed, err := db.Create(ctx, &options)
+	if err != nil {
+		t.Fatalf("failed to seed the database: %v", err)
+	}
+
+	t.Run("update record with nil ID", func(t *testing.T) {
+
+		_, err := db.Update(ctx, uuid.Nil, &UpdateOptions{
+			Title: "Updated Record",
+		})
+		if err == nil {
+			t.Errorf("service.Update() error = %v, wantErr %v", err, true)
+		}
+	})
+
+	t.Run("update record with nil options", func(t *testing.T) {
+
+		_, err := db.Update(ctx, seed.ID, nil)
+		if err == nil {
+			t.Errorf("service.Update() error = %v, wantErr %v", err, true)
+		}
+	})
+
+	t.Run("update record with invalid options", func(t *testing.T) {
+
+		_, err := db.Update(ctx, seed.ID, &UpdateOptions{
+			Title: "",
+		})
+		if err == nil {
+			t.Errorf("service.Update() error = %v, wantErr %v", err, true)
+		}
+	})
+
+	t.Run("update record with no fields to update", func(t *testing.T) {
+
+		_, err := db.Update(ctx, seed.ID, &UpdateOptions{})
+		if err != ErrNoFieldsToUpdate {
+			t.Errorf("service.Update() error = %v, wantErr %v", err, ErrNoFieldsToUpdate)
+		}
+	})
+
+	t.Run("update record with valid options", func(t *testing.T) {
+
+		updatedTitle := "Updated Record"
+		record, err := db.Update(ctx, seed.ID, &UpdateOptions{
+			Title: updatedTitle,
+		})
+		if err != nil {
+			t.Fatalf("failed to update record: %v", err)
+		}
+
+		if record.Title != updatedTitle {
+			t.Fatalf("expected record title to be 'Updated Record', got '%s'", record.Title)
+		}
+	})
+
+	t.Run("update record w/ field outside of the allowlist", func(t *testing.T) {
+
+		// Drop the description from the allowlist for the duration of the test.
+		delete(updatable, "description")
+		t.Cleanup(func() {
+			updatable["description"] = true
+		})
+
+		_, err := db.Update(ctx, seed.ID, &UpdateOptions{
+			Description: "Patched Description",
+		})
+		if err != ErrNoFieldsToUpdate {
+			t.Fatalf("service.Update() error = %v, wantErr %v", err, ErrNoFieldsToUpdate)
+		}
+
+		record, err := db.Update(ctx, seed.ID, &UpdateOptions{
+			Title:       "Allowed Title",
+			Description: "Patched Description",
+		})
+		if err != nil {
+			t.Fatalf("failed to update record: %v", err)
+		}
+		if record.Title != "Allowed Title" {
+			t.Errorf("expected record title to be 'Allowed Title', got '%s'", record.Title)
+		}
+		if record.Description == "Patched Description" {
+			t.Errorf("expected the description outside of the allowlist to be ignored")
+		}
+	})
+
+	t.Run("update record as a different user than the one who created it", func(t *testing.T) {
+
+		// Add JWT claims to the context.
+		ctx := middleware.WithJWTClaims(context.Background(), middleware.JWTClaims{
+			XUserID: uuid.New(),
+		})
+
+		_, err := db.Update(ctx, seed.ID, &UpdateOptions{
+			Title: "Updated Record",
+		})
+		if err == nil {
+			t.Errorf("service.Update() error = %v, wantErr %v", err, true)
+		}
+	})
+}
+
+func Test_Database_Delete(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	// Initialize the database.
+	db := &sqldb{
+		conn: config.conn,
+	}
+
+	ctx := context.Background()
+
+	t.Run("delete record with nil ID", func(t *testing.T) {
+
+		err := db.Delete(ctx, uuid.Nil)
+		if err == nil {
+			t.Errorf("service.Delete() error = %v, wantErr %v", err, true)
+		}
+	})
+
+	t.Run("delete record with valid ID", func(t *testing.T) {
+
+		seed, err := db.Create(ctx, &CreateOptions{
+			Title:  "Test Record",
+			UserID: uuid.New(),
+		})
+		if err != nil {
+			t.Fatalf("failed to seed the database: %v", err)
+		}
+
+		if err := db.Delete(ctx, seed.ID); err != nil {
+			t.Fatalf("failed to delete record: %v", err)
+		}
+	})
+
+	t.Run("delete record as a different user than the one who created it", func(t *testing.T) {
+
+		seed, err := db.Create(ctx, &CreateOptions{
+			Title:  "Test Record",
+			UserID: uuid.New(),
+		})
+		if err != nil {
+			t.Fatalf("failed to seed the database: %v", err)
+		}
+
+		// Add JWT claims to the context.
+		ctx := middleware.WithJWTClaims(context.Background(), middleware.JWTClaims{
+			XUserID: uuid.New(),
+		})
+
+		err = db.Delete(ctx, seed.ID)
+		if err == nil {
+			t.Errorf("service.Delete() error = %v, wantErr %v", err, true)
+		}
+	})
+}
+
+func Test_Database_WithTransaction(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	// Initialize the database.
+	db := &sqldb{
+		conn: config.conn,
+	}
+
+	ctx := context.Background()
+
+	t.Run("commit transaction w/ isolation level", func(t *testing.T) {
+
+		var record *model.Record
+		err := db.WithTransaction(ctx, func(tx DB) (err error) {
+			record, err = tx.Create(ctx, &CreateOptions{
+				Title:  "Committed Record",
+				UserID: uuid.New(),
+			})
+			return err
+		}, &sql.TxOptions{Isolation: sql.LevelSerializable})
+		if err != nil {
+			t.Fatalf("failed to run the transaction: %v", err)
+		}
+
+		if _, err := db.Get(ctx, record.ID); err != nil {
+			t.Fatalf("expected the record to be committed, got %v", err)
+		}
+	})
+
+	t.Run("rollback transaction on error", func(t *testing.T) {
+
+		var record *model.Record
+		err := db.WithTransaction(ctx, func(tx DB) (err error) {
+			record, err = tx.Create(ctx, &CreateOptions{
+				Title:  "Rolled Back Record",
+				UserID: uuid.New(),
+			})
+			if err != nil {
+				return err
+			}
+			return fmt.Errorf("abort")
+		})
+		if err == nil {
+			t.Fatalf("expected the transaction to fail")
+		}
+
+		if _, err := db.Get(ctx, record.ID); err == nil {
+			t.Fatalf("expected the record to be rolled back")
+		}
+	})
+}
+
+func Test_Database_RequestTransaction(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	// Initialize the database.
+	db := &sqldb{
+		conn: config.conn,
+	}
+
+	tests := []struct {
+		name       string
+		commit     bool
+		wantExists bool
+	}{
+		{
+			name:       "commit request-scoped transaction",
+			commit:     true,
+			wantExists: true,
+		},
+		{
+			name:       "roll back request-scoped transaction",
+			commit:     false,
+			wantExists: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Open the transaction the way the `Transaction` middleware does.
+			tx := config.conn.Begin()
+			ctx := middleware.WithTransaction(context.Background(), tx)
+
+			record, err := db.Create(ctx, &CreateOptions{
+				Title:  "Request Record",
+				UserID: uuid.New(),
+			})
+			if err != nil {
+				t.Fatalf("failed to create the record: %v", err)
+			}
+
+			// The transactions of the database layer nest inside the request-scoped one.
+			var nested *model.Record
+			if err := db.WithTransaction(ctx, func(tx DB) (err error) {
+				nested, err = tx.Create(ctx, &CreateOptions{
+					Title:  "Nested Record",
+					UserID: uuid.New(),
+				})
+				return err
+			}); err != nil {
+				t.Fatalf("failed to run the nested transaction: %v", err)
+			}
+
+			if tt.commit {
+				err = tx.Commit().Error
+			} else {
+				err = tx.Rollback().Error
+			}
+			if err != nil {
+				t.Fatalf("failed to end the transaction: %v", err)
+			}
+
+			for _, id := range []uuid.UUID{record.ID, nested.ID} {
+				_, err := db.Get(context.Background(), id)
+				if exists := err == nil; exists != tt.wantExists {
+					t.Errorf("record exists = %v, want %v", exists, tt.wantExists)
+				}
+			}
+		})
+	}
+}
+
+func Test_Database_Count(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	// Initialize the database.
+	db := &sqldb{
+		conn: config.conn,
+	}
+
+	ctx := context.Background()
+
+	// Seed the database with records of two users.
+	owner := uuid.New()
+	for i, userID := range []uuid.UUID{owner, owner, uuid.New()} {
+		if _, err := db.Create(ctx, &CreateOptions{
+			Title:  fmt.Sprintf("Record %d", i),
+			UserID: userID,
+		}); err != nil {
+			t.Fatalf("failed to seed the database: %v", err)
+		}
+	}
+
+	t.Run("count records of a user", func(t *testing.T) {
+
+		count, err := db.Count(ctx, &CountOptions{
+			UserID: owner,
+		})
+		if err != nil {
+			t.Fatalf("failed to count records: %v", err)
+		}
+
+		if count != 2 {
+			t.Fatalf("expected 2 records, got %d", count)
+		}
+	})
+
+	t.Run("count records w/ title filter as the owner", func(t *testing.T) {
+
+		// Add JWT claims to the context.
+		ctx := middleware.WithJWTClaims(context.Background(), middleware.JWTClaims{
+			XUserID: owner,
+		})
+
+		count, err := db.Count(ctx, &CountOptions{
+			Title: "Record 2",
+		})
+		if err != nil {
+			t.Fatalf("failed to count records: %v", err)
+		}
+
+		// The record with the title belongs to a different user.
+		if count != 0 {
+			t.Fatalf("expected 0 records, got %d", count)
+		}
+	})
+}
+
+func Test_Database_Aggregate(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	// Initialize the database.
+	db := &sqldb{
+		conn: config.conn,
+	}
+
+	ctx := context.Background()
+
+	// Seed the database with records of two users, created on two days.
+	owner := uuid.New()
+	first := time.Date(2026, time.March, 1, 9, 0, 0, 0, time.UTC)
+	second := time.Date(2026, time.March, 2, 18, 0, 0, 0, time.UTC)
+	for _, seed := range []struct {
+		title     string
+		userID    uuid.UUID
+		createdAt time.Time
+	}{
+		{"alpha 1", owner, first},
+		{"alpha 2", owner, first},
+		{"beta 1", owner, second},
+		{"gamma 1", uuid.New(), second},
+	} {
+		if _, err := db.Import(ctx, &ImportOptions{
+			Title:     seed.title,
+			UserID:    seed.userID,
+			CreatedAt: seed.createdAt,
+		}); err != nil {
+			t.Fatalf("failed to seed the database: %v", err)
+		}
+	}
+
+	t.Run("aggregate records per day as the owner", func(t *testing.T) {
+
+		// Add JWT claims to the context.
+		ctx := middleware.WithJWTClaims(context.Background(), middleware.JWTClaims{
+			XUserID: owner,
+		})
+
+		groups, err := db.Aggregate(ctx, &AggregateOptions{
+			GroupBy: GroupByDay,
+		})
+		if err != nil {
+			t.Fatalf("failed to aggregate records: %v", err)
+		}
+
+		// The record of the other user must not be counted.
+		want := []model.Group{{Key: "2026-03-01", Count: 2}, {Key: "2026-03-02", Count: 1}}
+		if len(groups) != len(want) {
+			t.Fatalf("expected %d groups, got %d", len(want), len(groups))
+		}
+		for i, group := range groups {
+			if *group != want[i] {
+				t.Fatalf("expected group %v, got %v", want[i], *group)
+			}
+		}
+	})
+
+	t.Run("aggregate records per title prefix", func(t *testing.T) {
+
+		groups, err := db.Aggregate(ctx, &AggregateOptions{
+			GroupBy:      GroupByTitlePrefix,
+			PrefixLength: 2,
+		})
+		if err != nil {
+			t.Fatalf("failed to aggregate records: %v", err)
+		}
+
+		want := []model.Group{{Key: "al", Count: 2}, {Key: "be", Count: 1}, {Key: "ga", Count: 1}}
+		if len(groups) != len(want) {
+			t.Fatalf("expected %d groups, got %d", len(want), len(groups))
+		}
+		for i, group := range groups {
+			if *group != want[i] {
+				t.Fatalf("expected group %v, got %v", want[i], *group)
+			}
+		}
+	})
+
+	t.Run("aggregate records w/ unknown grouping", func(t *testing.T) {
+
+		if _, err := db.Aggregate(ctx, &AggregateOptions{
+			GroupBy: "user_id; DROP TABLE records",
+		}); err != ErrInvalidFilters {
+			t.Fatalf("expected error %v, got %v", ErrInvalidFilters, err)
+		}
+	})
+}
+
+func Test_Database_DistinctTitles(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	// Initialize the database.
+	db := &sqldb{
+		conn: config.conn,
+	}
+
+	ctx := context.Background()
+
+	// Seed the database with the same titles for two users.
+	owner := uuid.New()
+	for _, userID := range []uuid.UUID{owner, uuid.New()} {
+		for _, title := range []string{"Report Q2", "report Q1", "Review", "50%_off"} {
+			if _, err := db.Create(ctx, &CreateOptions{
+				Title:  title,
+				UserID: userID,
+			}); err != nil {
+				t.Fatalf("failed to seed the database: %v", err)
+			}
+		}
+	}
+
+	// Only the other user has this title.
+	if _, err := db.Create(ctx, &CreateOptions{
+		Title:  "Report Q3",
+		UserID: uuid.New(),
+	}); err != nil {
+		t.Fatalf("failed to seed the database: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		prefix string
+		limit  int
+		want   []string
+	}{
+		{
+			name:   "fetch distinct titles w/ prefix",
+			ctx:    ctx,
+			prefix: "rep",
+			want:   []string{"Report Q2", "Report Q3", "report Q1"},
+		},
+		{
+			name:   "fetch distinct titles w/ prefix as the owner",
+			ctx:    middleware.WithJWTClaims(ctx, middleware.JWTClaims{XUserID: owner}),
+			prefix: "REP",
+			want:   []string{"Report Q2", "report Q1"},
+		},
+		{
+			name:   "fetch distinct titles w/ limit",
+			ctx:    ctx,
+			prefix: "r",
+			limit:  2,
+			want:   []string{"Report Q2", "Report Q3"},
+		},
+		{
+			name:   "fetch distinct titles w/ wildcard in prefix",
+			ctx:    ctx,
+			prefix: "50%_",
+			want:   []string{"50%_off"},
+		},
+		{
+			name:   "fetch distinct titles w/ unmatched prefix",
+			ctx:    ctx,
+			prefix: "5_%x",
+			want:   []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			titles, err := db.DistinctTitles(tt.ctx, tt.prefix, tt.limit)
+			if err != nil {
+				t.Fatalf("failed to fetch the titles: %v", err)
+			}
+
+			if !reflect.DeepEqual(titles, tt.want) {
+				t.Fatalf("expected titles %v, got %v", tt.want, titles)
+			}
+		})
+	}
+
+	t.Run("fetch distinct titles w/ invalid limit", func(t *testing.T) {
+
+		if _, err := db.DistinctTitles(ctx, "", 101); err != ErrInvalidFilters {
+			t.Fatalf("expected error %v, got %v", ErrInvalidFilters, err)
+		}
+	})
+}
+
+func Test_Database_List_PartialResults(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	ctx := context.Background()
+
+	// Seed the database with a valid record.
+	seed := &sqldb{
+		conn: config.conn,
+	}
+	if _, err := seed.Create(ctx, &CreateOptions{
+		Title:  "Valid Record",
+		UserID: uuid.New(),
+	}); err != nil {
+		t.Fatalf("failed to seed the database: %v", err)
+	}
+
+	// Inject a row which fails to scan because of its malformed ID.
+	if err := config.conn.Exec(
+		"INSERT INTO records (id, title, description, user_id, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)",
+		"malformed-id", "Corrupt Record", "", uuid.New().String(), time.Now(), time.Now(),
+	).Error; err != nil {
+		t.Fatalf("failed to inject the corrupt row: %v", err)
+	}
+
+	t.Run("fail on scan error w/ partial results disabled", func(t *testing.T) {
+
+		db := NewSQLDB(&SQLDBConfig{
+			DB: config.conn,
+		})
+
+		records, err := db.List(ctx, nil)
+		if err == nil {
+			t.Fatalf("expected an error, got %d records", len(records))
+		}
+		if errors.Is(err, ErrPartialResults) {
+			t.Fatalf("expected a scan error, got %v", err)
+		}
+		if records != nil {
+			t.Fatalf("expected no records, got %d", len(records))
+		}
+	})
+
+	t.Run("return scanned records w/ partial results enabled", func(t *testing.T) {
+
+		db := NewSQLDB(&SQLDBConfig{
+			DB:             config.conn,
+			PartialResults: true,
+		})
+
+		records, err := db.List(ctx, nil)
+		if !errors.Is(err, ErrPartialResults) {
+			t.Fatalf("expected error %v, got %v", ErrPartialResults, err)
+		}
+		if len(records) != 1 {
+			t.Fatalf("expected 1 record, got %d", len(records))
+		}
+		if records[0].Title != "Valid Record" {
+			t.Fatalf("expected the valid record, got %q", records[0].Title)
+		}
+	})
+
+	t.Run("fail on query error w/ partial results enabled", func(t *testing.T) {
+
+		db := NewSQLDB(&SQLDBConfig{
+			DB:             config.conn,
+			PartialResults: true,
+		})
+
+		// Cancel the context, so that the query itself fails.
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		records, err := db.List(ctx, nil)
+		if err == nil || errors.Is(err, ErrPartialResults) {
+			t.Fatalf("expected a query error, got %v", err)
+		}
+		if records != nil {
+			t.Fatalf("expected no records, got %d", len(records))
+		}
+	})
+}
+
+func Test_Database_AuditRLS(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	// Initialize the database.
+	db := NewSQLDB(&SQLDBConfig{
+		DB:       config.conn,
+		AuditRLS: true,
+	})
+
+	// Seed the database with a record owned by another user.
+	record, err := db.Create(context.Background(), &CreateOptions{
+		Title:  "Owned Record",
+		UserID: uuid.New(),
+	})
+	if err != nil {
+		t.Fatalf("failed to seed the database: %v", err)
+	}
+
+	// Returns the current value of the counter.
+	counter := func(key string) int64 {
+		if value, ok := rlsDenied.Get(key).(*expvar.Int); ok {
+			return value.Value()
+		}
+		return 0
+	}
+
+	t.Run("count cross-user get", func(t *testing.T) {
+
+		before := counter("records.get")
+
+		// Add JWT claims of a different user to the context.
+		ctx := middleware.WithJWTClaims(context.Background(), middleware.JWTClaims{
+			XUserID: uuid.New(),
+		})
+
+		if _, err := db.Get(ctx, record.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("expected error %v, got %v", gorm.ErrRecordNotFound, err)
+		}
+
+		if after := counter("records.get"); after != before+1 {
+			t.Fatalf("expected the counter to be %d, got %d", before+1, after)
+		}
+	})
+
+	t.Run("skip get of a missing record", func(t *testing.T) {
+
+		before := counter("records.get")
+
+		// Add JWT claims of a different user to the context.
+		ctx := middleware.WithJWTClaims(context.Background(), middleware.JWTClaims{
+			XUserID: uuid.New(),
+		})
+
+		if _, err := db.Get(ctx, uuid.New()); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("expected error %v, got %v", gorm.ErrRecordNotFound, err)
+		}
+
+		if after := counter("records.get"); after != before {
+			t.Fatalf("expected the counter to be %d, got %d", before, after)
+		}
+	})
+
+	t.Run("count cross-user get of a deleted record", func(t *testing.T) {
+
+		// Seed the database with a deleted record owned by another user.
+		deleted, err := db.Create(context.Background(), &CreateOptions{
+			Title:  "Deleted Record",
+			UserID: uuid.New(),
+		})
+		if err != nil {
+			t.Fatalf("failed to seed the database: %v", err)
+		}
+		if err := db.Delete(context.Background(), deleted.ID); err != nil {
+			t.Fatalf("failed to delete the record: %v", err)
+		}
+
+		before := counter("records.get")
+
+		// Add JWT claims of a different user to the context.
+		ctx := middleware.WithJWTClaims(context.Background(), middleware.JWTClaims{
+			XUserID: uuid.New(),
+		})
+
+		if _, err := db.GetIncludingDeleted(ctx, deleted.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("expected error %v, got %v", gorm.ErrRecordNotFound, err)
+		}
+
+		if after := counter("records.get"); after != before+1 {
+			t.Fatalf("expected the counter to be %d, got %d", before+1, after)
+		}
+	})
+
+	t.Run("skip get of an own deleted record", func(t *testing.T) {
+
+		// Seed the database with a deleted record owned by the requester.
+		owner := uuid.New()
+		deleted, err := db.Create(context.Background(), &CreateOptions{
+			Title:  "Deleted Record",
+			UserID: owner,
+		})
+		if err != nil {
+			t.Fatalf("failed to seed the database: %v", err)
+		}
+		if err := db.Delete(context.Background(), deleted.ID); err != nil {
+			t.Fatalf("failed to delete the record: %v", err)
+		}
+
+		before := counter("records.get")
+
+		// Add JWT claims of the owner to the context.
+		ctx := middleware.WithJWTClaims(context.Background(), middleware.JWTClaims{
+			XUserID: owner,
+		})
+
+		if _, err := db.Get(ctx, deleted.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("expected error %v, got %v", gorm.ErrRecordNotFound, err)
+		}
+
+		if after := counter("records.get"); after != before {
+			t.Fatalf("expected the counter to be %d, got %d", before, after)
+		}
+	})
+
+	t.Run("count cross-user delete", func(t *testing.T) {
+
+		before := counter("records.delete")
+
+		// Add JWT claims of a different user to the context.
+		ctx := middleware.WithJWTClaims(context.Background(), middleware.JWTClaims{
+			XUserID: uuid.New(),
+		})
+
+		if err := db.Delete(ctx, record.ID); err != ErrNoRowsAffected {
+			t.Fatalf("expected error %v, got %v", ErrNoRowsAffected, err)
+		}
+
+		if after := counter("records.delete"); after != before+1 {
+			t.Fatalf("expected the counter to be %d, got %d", before+1, after)
+		}
+	})
+}
+
+func Test_Database_Upsert(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	// Initialize the database.
+	db := &sqldb{
+		conn: config.conn,
+	}
+
+	ctx := context.Background()
+	userID := uuid.New()
+
+	var inserted *model.Record
+
+	t.Run("upsert w/ new natural key inserts the record", func(t *testing.T) {
+
+		record, created, err := db.Upsert(ctx, &CreateOptions{
+			Title:       "Synced Record",
+			Description: "First version",
+			UserID:      userID,
+		})
+		if err != nil {
+			t.Fatalf("failed to upsert the record: %v", err)
+		}
+
+		if !created {
+			t.Fatalf("expected the record to be created")
+		}
+
+		if record.Description != "First version" {
+			t.Fatalf("expected the description 'First version', got '%s'", record.Description)
+		}
+		inserted = record
+	})
+
+	t.Run("upsert w/ existing natural key updates the record", func(t *testing.T) {
+
+		record, created, err := db.Upsert(ctx, &CreateOptions{
+			Title:       "Synced Record",
+			Description: "Second version",
+			UserID:      userID,
+		})
+		if err != nil {
+			t.Fatalf("failed to upsert the record: %v", err)
+		}
+
+		if created {
+			t.Fatalf("expected the record to be updated")
+		}
+
+		if record.ID != inserted.ID {
+			t.Fatalf("expected the record %s to be updated, got %s", inserted.ID, record.ID)
+		}
+
+		if record.Description != "Second version" {
+			t.Fatalf("expected the description 'Second version', got '%s'", record.Description)
+		}
+
+		count, err := db.Count(ctx, &CountOptions{
+			Title:  "Synced Record",
+			UserID: userID,
+		})
+		if err != nil {
+			t.Fatalf("failed to count the records: %v", err)
+		}
+		if count != 1 {
+			t.Fatalf("expected 1 record, got %d", count)
+		}
+	})
+
+	t.Run("upsert w/ same title for another user inserts the record", func(t *testing.T) {
+
+		record, created, err := db.Upsert(ctx, &CreateOptions{
+			Title:  "Synced Record",
+			UserID: uuid.New(),
+		})
+		if err != nil {
+			t.Fatalf("failed to upsert the record: %v", err)
+		}
+
+		if !created || record.ID == inserted.ID {
+			t.Fatalf("expected a new record to be created")
+		}
+	})
+
+	t.Run("create w/ natural key of an existing record", func(t *testing.T) {
+
+		// The titles are unique per user, so a plain create can't duplicate the natural key.
+		if _, err := db.Create(ctx, &CreateOptions{
+			Title:  "Synced Record",
+			UserID: userID,
+		}); !errors.Is(err, ErrDuplicateTitle) {
+			t.Fatalf("expected the error %v, got %v", ErrDuplicateTitle, err)
+		}
+	})
+
+	t.Run("upsert w/ invalid options", func(t *testing.T) {
+
+		if _, _, err := db.Upsert(ctx, &CreateOptions{}); err == nil {
+			t.Fatalf("expected an error, got nil")
+		}
+	})
+}
+
+func Test_Database_CreateIfNotExists(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	// Initialize the database.
+	db := &sqldb{
+		conn: config.conn,
+	}
+
+	ctx := context.Background()
+	userID := uuid.New()
+
+	var inserted *model.Record
+
+	t.Run("create w/ new natural key inserts the record", func(t *testing.T) {
+
+		record, created, err := db.CreateIfNotExists(ctx, &CreateOptions{
+			Title:       "Provisioned Record",
+			Description: "First version",
+			UserID:      userID,
+		})
+		if err != nil {
+			t.Fatalf("failed to create the record: %v", err)
+		}
+
+		if !created {
+			t.Fatalf("expected the record to be created")
+		}
+		inserted = record
+	})
+
+	t.Run("create w/ existing natural key returns the existing record", func(t *testing.T) {
+
+		record, created, err := db.CreateIfNotExists(ctx, &CreateOptions{
+			Title:       "Provisioned Record",
+			Description: "Second version",
+			UserID:      userID,
+		})
+		if err != nil {
+			t.Fatalf("failed to create the record: %v", err)
+		}
+
+		if created {
+			t.Fatalf("expected the existing record to be returned")
+		}
+
+		if record.ID != inserted.ID {
+			t.Fatalf("expected the record %s, got %s", inserted.ID, record.ID)
+		}
+
+		// The existing record must be left untouched.
+		if record.Description != "First version" {
+			t.Fatalf("expected the description 'First version', got '%s'", record.Description)
+		}
+	})
+
+	t.Run("create w/ invalid options", func(t *testing.T) {
+
+		if _, _, err := db.CreateIfNotExists(ctx, &CreateOptions{}); err == nil {
+			t.Fatalf("expected an error, got nil")
+		}
+	})
+}
+
+func Test_Database_Import(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	// Initialize the database.
+	db := &sqldb{
+		conn: config.conn,
+	}
+
+	ctx := context.Background()
+
+	t.Run("import record w/ historical timestamps", func(t *testing.T) {
+
+		createdAt := time.Date(2019, time.March, 1, 12, 0, 0, 0, time.UTC)
+		updatedAt := time.Date(2020, time.June, 15, 8, 30, 0, 0, time.UTC)
+
+		record, err := db.Import(ctx, &ImportOptions{
+			Title:     "Historical Record",
+			UserID:    uuid.New(),
+			CreatedAt: createdAt,
+			UpdatedAt: updatedAt,
+		})
+		if err != nil {
+			t.Fatalf("failed to import record: %v", err)
+		}
+
+		// Read the record back to make sure the stored timestamps weren't overwritten.
+		stored, err := db.Get(ctx, record.ID)
+		if err != nil {
+			t.Fatalf("failed to get record: %v", err)
+		}
+
+		if !stored.CreatedAt.Equal(createdAt) {
+			t.Fatalf("expected created at %v, got %v", createdAt, stored.CreatedAt)
+		}
+		if !stored.UpdatedAt.Equal(updatedAt) {
+			t.Fatalf("expected updated at %v, got %v", updatedAt, stored.UpdatedAt)
+		}
+	})
+
+	t.Run("import record w/o updated at", func(t *testing.T) {
+
+		createdAt := time.Date(2019, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+		record, err := db.Import(ctx, &ImportOptions{
+			Title:     "Historical Record",
+			UserID:    uuid.New(),
+			CreatedAt: createdAt,
+		})
+		if err != nil {
+			t.Fatalf("failed to import record: %v", err)
+		}
+
+		if !record.UpdatedAt.Equal(createdAt) {
+			t.Fatalf("expected updated at %v, got %v", createdAt, record.UpdatedAt)
+		}
+	})
+
+	t.Run("import record w/ updated at before created at", func(t *testing.T) {
+
+		createdAt := time.Date(2019, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+		_, err := db.Import(ctx, &ImportOptions{
+			Title:     "Historical Record",
+			UserID:    uuid.New(),
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt.Add(-time.Hour),
+		})
+		if err != ErrInvalidTimestamps {
+			t.Fatalf("expected error %v, got %v", ErrInvalidTimestamps, err)
+		}
+	})
+}
+
+func Test_Database_WithNestedTransaction(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	// Initialize the database.
+	db := &sqldb{
+		conn: config.conn,
+	}
+
+	ctx := context.Background()
+
+	t.Run("roll back to savepoint while outer transaction commits", func(t *testing.T) {
+
+		var outer, inner *model.Record
+		err := db.WithTransaction(ctx, func(tx DB) (err error) {
+			outer, err = tx.Create(ctx, &CreateOptions{
+				Title:  "Outer Record",
+				UserID: uuid.New(),
+			})
+			if err != nil {
+				return err
+			}
+
+			// The inner failure must only undo the changes made since the savepoint.
+			nestedErr := tx.WithNestedTransaction(ctx, func(tx DB) (err error) {
+				inner, err = tx.Create(ctx, &CreateOptions{
+					Title:  "Inner Record",
+					UserID: uuid.New(),
+				})
+				if err != nil {
+					return err
+				}
+				return fmt.Errorf("abort")
+			})
+			if nestedErr == nil {
+				t.Errorf("expected the nested transaction to fail")
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("failed to run the transaction: %v", err)
+		}
+
+		if _, err := db.Get(ctx, outer.ID); err != nil {
+			t.Fatalf("expected the outer record to be committed, got %v", err)
+		}
+		if _, err := db.Get(ctx, inner.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("expected the inner record to be rolled back, got %v", err)
+		}
+	})
+
+	t.Run("commit nested transaction w/ outer transaction", func(t *testing.T) {
+
+		var inner *model.Record
+		err := db.WithTransaction(ctx, func(tx DB) error {
+			return tx.WithNestedTransaction(ctx, func(tx DB) (err error) {
+				inner, err = tx.Create(ctx, &CreateOptions{
+					Title:  "Inner Record",
+					UserID: uuid.New(),
+				})
+				return err
+			})
+		})
+		if err != nil {
+			t.Fatalf("failed to run the transaction: %v", err)
+		}
+
+		if _, err := db.Get(ctx, inner.ID); err != nil {
+			t.Fatalf("expected the inner record to be committed, got %v", err)
+		}
+	})
+
+	t.Run("run as a transaction outside a transaction", func(t *testing.T) {
+
+		var record *model.Record
+		err := db.WithNestedTransaction(ctx, func(tx DB) (err error) {
+			record, err = tx.Create(ctx, &CreateOptions{
+				Title:  "Rolled Back Record",
+				UserID: uuid.New(),
+			})
+			if err != nil {
+				return err
+			}
+			return fmt.Errorf("abort")
+		})
+		if err == nil {
+			t.Fatalf("expected the transaction to fail")
+		}
+
+		if _, err := db.Get(ctx, record.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("expected the record to be rolled back, got %v", err)
+		}
+	})
+}
+
+func Test_Database_AuditLogs(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	// Initialize the database.
+	db := &sqldb{
+		conn: config.conn,
+	}
+
+	ctx := context.Background()
+	actorID := uuid.New()
+	entityID := uuid.New()
+
+	// Seed the audit trail with the entries of two actors.
+	for _, options := range []*CreateAuditLogOptions{
+		{ActorID: actorID, Action: model.AuditActionCreate, Entity: "record", EntityID: entityID},
+		{ActorID: actorID, Action: model.AuditActionUpdate, Entity: "record", EntityID: entityID, Diff: []byte(`{"title":"Updated"}`)},
+		{ActorID: uuid.New(), Action: model.AuditActionDelete, Entity: "record", EntityID: uuid.New()},
+	} {
+		if _, err := db.CreateAuditLog(ctx, options); err != nil {
+			t.Fatalf("failed to seed the audit trail: %v", err)
+		}
+	}
+
+	t.Run("create audit log w/ invalid options", func(t *testing.T) {
+
+		if _, err := db.CreateAuditLog(ctx, &CreateAuditLogOptions{Action: model.AuditActionCreate}); err == nil {
+			t.Fatalf("expected an error, got nil")
+		}
+		if _, err := db.CreateAuditLog(ctx, &CreateAuditLogOptions{
+			Action:   model.AuditActionUpdate,
+			Entity:   "record",
+			EntityID: entityID,
+			Diff:     []byte(`{not json`),
+		}); err == nil {
+			t.Fatalf("expected an error for the malformed diff, got nil")
+		}
+	})
+
+	t.Run("list audit logs w/ filters", func(t *testing.T) {
+
+		logs, err := db.ListAuditLogs(ctx, &ListAuditLogsOptions{
+			ActorID: actorID,
+			Action:  model.AuditActionUpdate,
+		})
+		if err != nil {
+			t.Fatalf("failed to list the audit logs: %v", err)
+		}
+
+		if len(logs) != 1 {
+			t.Fatalf("expected 1 audit log, got %d", len(logs))
+		}
+		if logs[0].EntityID != entityID || string(logs[0].Diff) != `{"title":"Updated"}` {
+			t.Fatalf("unexpected audit log: %+v", logs[0])
+		}
+	})
+
+	t.Run("list audit logs of an entity", func(t *testing.T) {
+
+		logs, err := db.ListAuditLogs(ctx, &ListAuditLogsOptions{
+			EntityID: entityID,
+		})
+		if err != nil {
+			t.Fatalf("failed to list the audit logs: %v", err)
+		}
+
+		if len(logs) != 2 {
+			t.Fatalf("expected 2 audit logs, got %d", len(logs))
+		}
+	})
+
+	t.Run("list audit logs w/ rls", func(t *testing.T) {
+
+		ctx := middleware.WithJWTClaims(ctx, middleware.JWTClaims{
+			XUserID: actorID,
+		})
+
+		logs, err := db.ListAuditLogs(ctx, nil)
+		if err != nil {
+			t.Fatalf("failed to list the audit logs: %v", err)
+		}
+
+		for _, log := range logs {
+			if log.ActorID != actorID {
+				t.Fatalf("expected only the audit logs of the actor, got %+v", log)
+			}
+		}
+		if len(logs) != 2 {
+			t.Fatalf("expected 2 audit logs, got %d", len(logs))
+		}
+	})
+
+	t.Run("list audit logs w/ invalid time range", func(t *testing.T) {
+
+		now := time.Now()
+		if _, err := db.ListAuditLogs(ctx, &ListAuditLogsOptions{
+			Since: now,
+			Until: now.Add(-time.Hour),
+		}); err != ErrInvalidFilters {
+			t.Fatalf("expected %v, got %v", ErrInvalidFilters, err)
 		}
 	})
 }
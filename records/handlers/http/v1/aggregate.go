@@ -0,0 +1,97 @@
+package v1
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/dyninc/qstring"
+	"github.com/mrinalwahal/boilerplate/records/service"
+)
+
+// AggregateOptions represents the options for counting records per group.
+type AggregateOptions struct {
+
+	//	Field the records are grouped by. One of `day` or `title_prefix`.
+	GroupBy string `query:"groupBy" qstring:"groupBy"`
+
+	//	Number of leading characters of the title which make up the key, when grouping by `title_prefix`.
+	PrefixLength int `query:"prefixLength" qstring:"prefixLength"`
+}
+
+// Aggregate handler counts the records per group.
+type AggregateHandler struct {
+
+	// Service layer.
+	//
+	// This field is mandatory.
+	service service.Service
+
+	// log is the `log/slog` instance that will be used to log messages.
+	// Default: `slog.DefaultLogger`
+	//
+	// This field is optional.
+	log *slog.Logger
+}
+
+type AggregateHandlerConfig struct {
+
+	// Service layer.
+	//
+	// This field is mandatory.
+	Service service.Service
+
+	// Logger is the `log/slog` instance that will be used to log messages.
+	// Default: `slog.DefaultLogger`
+	//
+	// This field is optional.
+	Logger *slog.Logger
+}
+
+// NewAggregateHandler creates a new instance of `AggregateHandler`.
+func NewAggregateHandler(config *AggregateHandlerConfig) Handler {
+	handler := AggregateHandler{
+		service: config.Service,
+		log:     config.Logger,
+	}
+
+	// Set the default logger if not provided.
+	if handler.log == nil {
+		handler.log = slog.Default()
+	}
+	handler.log = handler.log.With("handler", "aggregate")
+
+	return &handler
+}
+
+// ServeHTTP handles the incoming HTTP request.
+func (h *AggregateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.log.DebugContext(r.Context(), "handling request")
+
+	// Decode the request options.
+	var options AggregateOptions
+	if err := qstring.Unmarshal(r.URL.Query(), &options); err != nil {
+		write(w, http.StatusBadRequest, &Response{
+			Message: "Invalid request options.",
+			Err:     err,
+		})
+		return
+	}
+
+	// Call the service method that performs the required operation.
+	groups, err := h.service.Aggregate(r.Context(), &service.AggregateOptions{
+		GroupBy:      service.GroupBy(options.GroupBy),
+		PrefixLength: options.PrefixLength,
+	})
+	if err != nil {
+		write(w, statusOf(err), &Response{
+			Message: "Failed to aggregate the records.",
+			Err:     err,
+		})
+		return
+	}
+
+	write(w, http.StatusOK, &Response{
+		Message: "The records were aggregated successfully.",
+		Data:    groups,
+	})
+}
@@ -0,0 +1,91 @@
+package v1
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/google/uuid"
+	"github.com/mrinalwahal/boilerplate/records/service"
+)
+
+// Clone handler duplicates the record.
+type CloneHandler struct {
+
+	// Service layer.
+	//
+	// This field is mandatory.
+	service service.Service
+
+	// log is the `log/slog` instance that will be used to log messages.
+	// Default: `slog.DefaultLogger`
+	//
+	// This field is optional.
+	log *slog.Logger
+}
+
+type CloneHandlerConfig struct {
+
+	// Service layer.
+	//
+	// This field is mandatory.
+	Service service.Service
+
+	// Logger is the `log/slog` instance that will be used to log messages.
+	// Default: `slog.DefaultLogger`
+	//
+	// This field is optional.
+	Logger *slog.Logger
+}
+
+// NewCloneHandler creates a new instance of `CloneHandler`.
+func NewCloneHandler(config *CloneHandlerConfig) Handler {
+	handler := CloneHandler{
+		service: config.Service,
+		log:     config.Logger,
+	}
+
+	// Set the default logger if not provided.
+	if handler.log == nil {
+		handler.log = slog.Default()
+	}
+	handler.log = handler.log.With("handler", "clone")
+
+	return &handler
+}
+
+// ServeHTTP handles the incoming HTTP request.
+func (h *CloneHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.log.DebugContext(r.Context(), "handling request")
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		write(w, http.StatusBadRequest, &Response{
+			Message: "Invalid ID.",
+		})
+		return
+	}
+
+	record, err := h.service.Clone(r.Context(), id)
+	if err != nil {
+		write(w, statusOf(err), &Response{
+			Message: "Failed to clone the record.",
+			Err:     err,
+		})
+		return
+	}
+
+	// Point the client to the copy, which is a sibling of the original.
+	location := r.URL.Path
+	if uri, err := url.ParseRequestURI(r.RequestURI); err == nil {
+		location = uri.Path
+	}
+	w.Header().Set("Location", path.Join(path.Dir(path.Dir(location)), record.ID.String()))
+	lastModified(w, record)
+
+	write(w, http.StatusCreated, &Response{
+		Message: "The record was cloned successfully.",
+		Data:    record,
+	})
+}
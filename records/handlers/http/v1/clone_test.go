@@ -0,0 +1,74 @@
+package v1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mrinalwahal/boilerplate/model"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestCloneHandler_ServeHTTP(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	// Create the handler.
+	handler := NewCloneHandler(&CloneHandlerConfig{
+		Service: config.service,
+		Logger:  config.log,
+	})
+
+	clone := &model.Record{
+		Base: model.Base{
+			ID: uuid.New(),
+		},
+		Title: "Test Record (copy)",
+	}
+
+	tests := []struct {
+		name         string
+		record       *model.Record
+		err          error
+		wantStatus   int
+		wantLocation string
+	}{
+		{
+			name:         "clone own record",
+			record:       clone,
+			wantStatus:   http.StatusCreated,
+			wantLocation: "/v1/" + clone.ID.String(),
+		},
+		{
+			name:       "clone another user's record",
+			err:        gorm.ErrRecordNotFound,
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Initialize test request and response recorder.
+			id := uuid.New()
+			r := httptest.NewRequest(http.MethodPost, "/v1/"+id.String()+"/clone", nil)
+			r.SetPathValue("id", id.String())
+			w := httptest.NewRecorder()
+
+			config.service.EXPECT().Clone(gomock.Any(), id).Return(tt.record, tt.err).Times(1)
+
+			// Serve the request.
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Logf("response: %s", w.Body.String())
+				t.Fatalf("expected status code %d, got %d", tt.wantStatus, w.Code)
+			}
+			if location := w.Header().Get("Location"); location != tt.wantLocation {
+				t.Errorf("expected location %q, got %q", tt.wantLocation, location)
+			}
+		})
+	}
+}
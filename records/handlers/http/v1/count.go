@@ -0,0 +1,98 @@
+package v1
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/dyninc/qstring"
+	"github.com/mrinalwahal/boilerplate/records/service"
+)
+
+// CountOptions represents the options for counting records.
+type CountOptions struct {
+
+	//	Title of the record.
+	Title string `query:"title"`
+}
+
+// CountResult is the number of records matching the options.
+type CountResult struct {
+	Count int64 `json:"count"`
+}
+
+// Count handler counts the records.
+type CountHandler struct {
+
+	// Service layer.
+	//
+	// This field is mandatory.
+	service service.Service
+
+	// log is the `log/slog` instance that will be used to log messages.
+	// Default: `slog.DefaultLogger`
+	//
+	// This field is optional.
+	log *slog.Logger
+}
+
+type CountHandlerConfig struct {
+
+	// Service layer.
+	//
+	// This field is mandatory.
+	Service service.Service
+
+	// Logger is the `log/slog` instance that will be used to log messages.
+	// Default: `slog.DefaultLogger`
+	//
+	// This field is optional.
+	Logger *slog.Logger
+}
+
+// NewCountHandler creates a new instance of `CountHandler`.
+func NewCountHandler(config *CountHandlerConfig) Handler {
+	handler := CountHandler{
+		service: config.Service,
+		log:     config.Logger,
+	}
+
+	// Set the default logger if not provided.
+	if handler.log == nil {
+		handler.log = slog.Default()
+	}
+	handler.log = handler.log.With("handler", "count")
+
+	return &handler
+}
+
+// ServeHTTP handles the incoming HTTP request.
+func (h *CountHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.log.DebugContext(r.Context(), "handling request")
+
+	// Decode the request options.
+	var options CountOptions
+	if err := qstring.Unmarshal(r.URL.Query(), &options); err != nil {
+		write(w, http.StatusBadRequest, &Response{
+			Message: "Invalid request options.",
+			Err:     err,
+		})
+		return
+	}
+
+	// Call the service method that performs the required operation.
+	count, err := h.service.Count(r.Context(), &service.CountOptions{
+		Title: options.Title,
+	})
+	if err != nil {
+		write(w, statusOf(err), &Response{
+			Message: "Failed to count the records.",
+			Err:     err,
+		})
+		return
+	}
+
+	write(w, http.StatusOK, &Response{
+		Message: "The records were counted successfully.",
+		Data:    CountResult{Count: count},
+	})
+}
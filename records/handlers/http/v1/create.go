@@ -4,8 +4,11 @@ import (
 	"context"
 	"log/slog"
 	"net/http"
+	"strings"
 
+	"github.com/dyninc/qstring"
 	"github.com/google/uuid"
+	"github.com/mrinalwahal/boilerplate/model"
 	"github.com/mrinalwahal/boilerplate/pkg/middleware"
 	"github.com/mrinalwahal/boilerplate/records/service"
 )
@@ -16,27 +19,40 @@ type CreateOptions struct {
 	//	Title of the record.
 	Title string `json:"title"`
 
+	//	Description of the record.
+	Description string `json:"description"`
+
 	// ID of the user who is creating the record.
 	UserID uuid.UUID `json:"-"`
 }
 
+// CreateQuery represents the query parameters of the create request.
+//
+// The `qstring` tags keep the camel case names, since the decoder otherwise matches the lowercased field names.
+type CreateQuery struct {
+
+	//	Whether to return the record the user already owns with the same title instead of creating another one.
+	IfNotExists bool `query:"ifNotExists" qstring:"ifNotExists"`
+}
+
 // validate the options.
 func (o *CreateOptions) validate() error {
-	checks := []bool{
-		o.Title != "",
-		o.UserID != uuid.Nil,
+	fields := FieldErrors{}
+	if strings.TrimSpace(o.Title) == "" {
+		fields["title"] = "is required"
 	}
-	for _, check := range checks {
-		if !check {
-			return ErrInvalidRequestOptions
-		}
+	if o.UserID == uuid.Nil {
+		fields["user_id"] = "is required"
+	}
+	if len(fields) > 0 {
+		return fields
 	}
 	return nil
 }
 
 // preset presets options from claims in the context.
 func (o *CreateOptions) preset(ctx context.Context) error {
-	claims, exists := ctx.Value(middleware.XJWTClaims).(middleware.JWTClaims)
+	claims, exists := middleware.JWTClaimsFromContext(ctx)
 	if !exists {
 		return ErrInvalidJWTClaims
 	}
@@ -94,10 +110,20 @@ func NewCreateHandler(config *CreateHandlerConfig) Handler {
 func (h *CreateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	h.log.DebugContext(r.Context(), "handling request")
 
+	// Decode the query parameters.
+	var query CreateQuery
+	if err := qstring.Unmarshal(r.URL.Query(), &query); err != nil {
+		write(w, http.StatusBadRequest, &Response{
+			Message: "Invalid request options.",
+			Err:     err,
+		})
+		return
+	}
+
 	// Decode the request options.
 	options, err := decode[CreateOptions](r)
 	if err != nil {
-		write(w, http.StatusBadRequest, &Response{
+		write(w, statusOf(err), &Response{
 			Message: "Invalid request options.",
 			Err:     err,
 		})
@@ -118,26 +144,52 @@ func (h *CreateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	// Validate the request options.
 	if err := options.validate(); err != nil {
-		write(w, http.StatusBadRequest, Response{
+		write(w, http.StatusUnprocessableEntity, &Response{
 			Message: "Failed validate request options.",
-			Err:     ErrInvalidRequestOptions,
+			Err:     err,
 		})
 		return
 	}
 
 	// Call the service method that performs the required operation.
-	record, err := h.service.Create(ctx, &service.CreateOptions{
-		Title:  options.Title,
-		UserID: options.UserID,
-	})
+	// With `ifNotExists`, the record the user already owns with the same title is returned instead.
+	payload := service.CreateOptions{
+		Title:       options.Title,
+		Description: options.Description,
+		UserID:      options.UserID,
+	}
+	var record *model.Record
+	created := true
+	if query.IfNotExists {
+		record, created, err = h.service.GetOrCreate(ctx, &payload)
+	} else {
+		record, err = h.service.Create(ctx, &payload)
+	}
 	if err != nil {
-		write(w, http.StatusBadRequest, Response{
+		write(w, statusOf(err), Response{
 			Message: "Failed to create the record.",
 			Err:     err,
 		})
 		return
 	}
 
+	describe(w, r, record)
+
+	// Skip the body if the client prefers a minimal response.
+	if prefersMinimal(r) {
+		w.Header().Set("Preference-Applied", "return=minimal")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if !created {
+		write(w, http.StatusOK, Response{
+			Message: "The record already exists.",
+			Data:    record,
+		})
+		return
+	}
+
 	write(w, http.StatusCreated, Response{
 		Message: "The record was created successfully.",
 		Data:    record,
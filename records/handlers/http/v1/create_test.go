@@ -2,7 +2,7 @@ package v1
 
 import (
 	"bytes"
-	"context"
+	"compress/gzip"
 	"encoding/json"
 	"log/slog"
 	"net/http"
@@ -117,7 +117,7 @@ func TestCreateHandler_ServeHTTP(t *testing.T) {
 
 		// Set the JWT claims in the request context.
 		user_id := uuid.New()
-		r = r.WithContext(context.WithValue(r.Context(), middleware.XJWTClaims, middleware.JWTClaims{
+		r = r.WithContext(middleware.WithJWTClaims(r.Context(), middleware.JWTClaims{
 			XUserID: user_id,
 		}))
 
@@ -138,4 +138,373 @@ func TestCreateHandler_ServeHTTP(t *testing.T) {
 			t.Fatalf("expected status code %d, got %d", http.StatusCreated, w.Code)
 		}
 	})
+	t.Run("create w/ gzipped options", func(t *testing.T) {
+
+		// Create the handler, behind the middleware which decompresses the request bodies.
+		handler := middleware.Decompress(nil)(NewCreateHandler(&CreateHandlerConfig{
+			Service: config.service,
+			Logger:  config.log,
+		}))
+
+		var body bytes.Buffer
+		writer := gzip.NewWriter(&body)
+		if err := json.NewEncoder(writer).Encode(CreateOptions{
+			Title: "Test Record",
+		}); err != nil {
+			t.Fatalf("failed to compress the dummy body for request: %v", err)
+		}
+		writer.Close()
+
+		// Initialize test request and response recorder.
+		r := httptest.NewRequest(http.MethodPost, "/v1/records", &body)
+		r.Header.Set("Content-Encoding", "gzip")
+		w := httptest.NewRecorder()
+
+		// Set the JWT claims in the request context.
+		user_id := uuid.New()
+		r = r.WithContext(middleware.WithJWTClaims(r.Context(), middleware.JWTClaims{
+			XUserID: user_id,
+		}))
+
+		// The service layer is expected to receive the decompressed options.
+		config.service.EXPECT().Create(gomock.Any(), &service.CreateOptions{
+			Title:  "Test Record",
+			UserID: user_id,
+		}).Return(&model.Record{
+			Base: model.Base{
+				ID: uuid.New(),
+			},
+			Title:  "Test Record",
+			UserID: user_id,
+		}, nil).Times(1)
+
+		// Serve the request.
+		handler.ServeHTTP(w, r)
+
+		if w.Code != http.StatusCreated {
+			t.Logf("response: %s", w.Body.String())
+			t.Fatalf("expected status code %d, got %d", http.StatusCreated, w.Code)
+		}
+	})
+
+	t.Run("create w/ gzipped options exceeding the decompressed size", func(t *testing.T) {
+
+		// Create the handler, behind the middleware which decompresses the request bodies.
+		handler := middleware.Decompress(&middleware.DecompressConfig{
+			MaxSize: 1024,
+		})(NewCreateHandler(&CreateHandlerConfig{
+			Service: config.service,
+			Logger:  config.log,
+		}))
+
+		// A bomb: a tiny compressed body which expands way past the cap.
+		var body bytes.Buffer
+		writer := gzip.NewWriter(&body)
+		writer.Write([]byte(`{"title": "`))
+		writer.Write(bytes.Repeat([]byte("a"), 1<<20))
+		writer.Write([]byte(`"}`))
+		writer.Close()
+
+		// Initialize test request and response recorder.
+		r := httptest.NewRequest(http.MethodPost, "/v1/records", &body)
+		r.Header.Set("Content-Encoding", "gzip")
+		w := httptest.NewRecorder()
+
+		// The service layer should ideally not be expecting any calls to reach it.
+		config.service.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+
+		// Serve the request.
+		handler.ServeHTTP(w, r)
+
+		if w.Code != http.StatusRequestEntityTooLarge {
+			t.Fatalf("expected status code %d, got %d", http.StatusRequestEntityTooLarge, w.Code)
+		}
+	})
+
+	t.Run("create w/ minimal return preference", func(t *testing.T) {
+
+		// Create the handler.
+		handler := NewCreateHandler(&CreateHandlerConfig{
+			Service: config.service,
+			Logger:  config.log,
+		})
+
+		body, err := json.Marshal(CreateOptions{
+			Title: "Test Record",
+		})
+		if err != nil {
+			t.Fatalf("failed to marshal the dummy body for request: %v", err)
+		}
+
+		// Initialize test request and response recorder.
+		r := httptest.NewRequest(http.MethodPost, "/v1", bytes.NewBuffer(body))
+		r.Header.Set("Prefer", "return=minimal")
+		w := httptest.NewRecorder()
+
+		// Set the JWT claims in the request context.
+		user_id := uuid.New()
+		r = r.WithContext(middleware.WithJWTClaims(r.Context(), middleware.JWTClaims{
+			XUserID: user_id,
+		}))
+
+		// The service layer is expected to return a record.
+		id := uuid.New()
+		config.service.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&model.Record{
+			Base: model.Base{
+				ID: id,
+			},
+			Title:  "Test Record",
+			UserID: user_id,
+		}, nil).Times(1)
+
+		// Serve the request.
+		handler.ServeHTTP(w, r)
+
+		if w.Code != http.StatusNoContent {
+			t.Logf("response: %s", w.Body.String())
+			t.Fatalf("expected status code %d, got %d", http.StatusNoContent, w.Code)
+		}
+		if w.Body.Len() != 0 {
+			t.Fatalf("expected an empty body, got %s", w.Body.String())
+		}
+		if location := w.Header().Get("Location"); location != "/v1/"+id.String() {
+			t.Fatalf("expected location %s, got %s", "/v1/"+id.String(), location)
+		}
+		if w.Header().Get("ETag") == "" {
+			t.Fatalf("expected an etag header")
+		}
+	})
+
+	t.Run("create w/ representation return preference", func(t *testing.T) {
+
+		// Create the handler.
+		handler := NewCreateHandler(&CreateHandlerConfig{
+			Service: config.service,
+			Logger:  config.log,
+		})
+
+		body, err := json.Marshal(CreateOptions{
+			Title: "Test Record",
+		})
+		if err != nil {
+			t.Fatalf("failed to marshal the dummy body for request: %v", err)
+		}
+
+		// Initialize test request and response recorder.
+		r := httptest.NewRequest(http.MethodPost, "/v1", bytes.NewBuffer(body))
+		r.Header.Set("Prefer", "return=representation")
+		w := httptest.NewRecorder()
+
+		// Set the JWT claims in the request context.
+		user_id := uuid.New()
+		r = r.WithContext(middleware.WithJWTClaims(r.Context(), middleware.JWTClaims{
+			XUserID: user_id,
+		}))
+
+		// The service layer is expected to return a record.
+		config.service.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&model.Record{
+			Base: model.Base{
+				ID: uuid.New(),
+			},
+			Title:  "Test Record",
+			UserID: user_id,
+		}, nil).Times(1)
+
+		// Serve the request.
+		handler.ServeHTTP(w, r)
+
+		if w.Code != http.StatusCreated {
+			t.Logf("response: %s", w.Body.String())
+			t.Fatalf("expected status code %d, got %d", http.StatusCreated, w.Code)
+		}
+
+		var response Response
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("failed to unmarshal the response body: %v", err)
+		}
+		if response.Data == nil {
+			t.Fatalf("expected the record in the response body")
+		}
+	})
+}
+
+func TestCreateHandler_ServeHTTP_IfNotExists(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	tests := []struct {
+		name    string
+		created bool
+		want    int
+	}{
+		{
+			name:    "create w/ if not exists and new record",
+			created: true,
+			want:    http.StatusCreated,
+		},
+		{
+			name:    "create w/ if not exists and existing record",
+			created: false,
+			want:    http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Create the handler.
+			handler := NewCreateHandler(&CreateHandlerConfig{
+				Service: config.service,
+				Logger:  config.log,
+			})
+
+			body, err := json.Marshal(CreateOptions{
+				Title: "Test Record",
+			})
+			if err != nil {
+				t.Fatalf("failed to marshal the dummy body for request: %v", err)
+			}
+
+			// Initialize test request and response recorder.
+			r := httptest.NewRequest(http.MethodPost, "/v1?ifNotExists=true", bytes.NewBuffer(body))
+			w := httptest.NewRecorder()
+
+			// Set the JWT claims in the request context.
+			user_id := uuid.New()
+			r = r.WithContext(middleware.WithJWTClaims(r.Context(), middleware.JWTClaims{
+				XUserID: user_id,
+			}))
+
+			// The request must be routed to the get-or-create operation.
+			config.service.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+			config.service.EXPECT().GetOrCreate(gomock.Any(), &service.CreateOptions{
+				Title:  "Test Record",
+				UserID: user_id,
+			}).Return(&model.Record{
+				Base: model.Base{
+					ID: uuid.New(),
+				},
+				Title:  "Test Record",
+				UserID: user_id,
+			}, tt.created, nil).Times(1)
+
+			// Serve the request.
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.want {
+				t.Logf("response: %s", w.Body.String())
+				t.Fatalf("expected status code %d, got %d", tt.want, w.Code)
+			}
+		})
+	}
+}
+
+func TestCreateHandler_ServeHTTP_DuplicateTitle(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	// Create the handler.
+	handler := NewCreateHandler(&CreateHandlerConfig{
+		Service: config.service,
+		Logger:  config.log,
+	})
+
+	body, err := json.Marshal(CreateOptions{
+		Title: "Test Record",
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal the dummy body for request: %v", err)
+	}
+
+	// Initialize test request and response recorder.
+	r := httptest.NewRequest(http.MethodPost, "/v1", bytes.NewBuffer(body))
+	r = r.WithContext(middleware.WithJWTClaims(r.Context(), middleware.JWTClaims{
+		XUserID: uuid.New(),
+	}))
+	w := httptest.NewRecorder()
+
+	// The user already has a record with the same title.
+	config.service.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, service.ErrDuplicateTitle).Times(1)
+
+	// Serve the request.
+	handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusConflict {
+		t.Logf("response: %s", w.Body.String())
+		t.Fatalf("expected status code %d, got %d", http.StatusConflict, w.Code)
+	}
+}
+
+func TestCreateHandler_ServeHTTP_Validation(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantFields []string
+	}{
+		{
+			name:       "malformed json",
+			body:       `{"title": "Test Record"`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "missing title",
+			body:       `{"description": "Test Description"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantFields: []string{"title"},
+		},
+		{
+			name:       "blank title",
+			body:       `{"title": "   "}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantFields: []string{"title"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Create the handler.
+			handler := NewCreateHandler(&CreateHandlerConfig{
+				Service: config.service,
+				Logger:  config.log,
+			})
+
+			// Initialize test request and response recorder.
+			r := httptest.NewRequest(http.MethodPost, "/v1/records", bytes.NewBufferString(tt.body))
+			r = r.WithContext(middleware.WithJWTClaims(r.Context(), middleware.JWTClaims{
+				XUserID: uuid.New(),
+			}))
+			w := httptest.NewRecorder()
+
+			// The service layer should not be reached.
+			config.service.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+
+			// Serve the request.
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status code %d, got %d", tt.wantStatus, w.Code)
+			}
+
+			var response Response
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode the response: %v", err)
+			}
+
+			fields, _ := response.Err.(FieldErrors)
+			if len(fields) != len(tt.wantFields) {
+				t.Fatalf("expected field errors for %v, got %v", tt.wantFields, fields)
+			}
+			for _, field := range tt.wantFields {
+				if _, ok := fields[field]; !ok {
+					t.Errorf("expected a field error for %q, got %v", field, fields)
+				}
+			}
+		})
+	}
 }
@@ -1,11 +1,13 @@
 package v1
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
 
 	"github.com/google/uuid"
 	"github.com/mrinalwahal/boilerplate/records/service"
+	"gorm.io/gorm"
 )
 
 // Delete handler deletes the record.
@@ -21,6 +23,9 @@ type DeleteHandler struct {
 	//
 	// This field is optional.
 	log *slog.Logger
+
+	// idempotent treats the delete of a missing record as a success.
+	idempotent bool
 }
 
 type DeleteHandlerConfig struct {
@@ -35,13 +40,22 @@ type DeleteHandlerConfig struct {
 	//
 	// This field is optional.
 	Logger *slog.Logger
+
+	// Idempotent treats the delete of a missing record as a success, since the desired end state is achieved,
+	// so the clients can safely retry the deletes. Both outcomes are answered with `204 No Content`.
+	// Otherwise, the delete is strict and a missing record is answered with `404 Not Found`.
+	// Default: `false`
+	//
+	// This field is optional.
+	Idempotent bool
 }
 
 // NewDeleteHandler deletes a new instance of `DeleteHandler`.
 func NewDeleteHandler(config *DeleteHandlerConfig) Handler {
 	handler := DeleteHandler{
-		service: config.Service,
-		log:     config.Logger,
+		service:    config.Service,
+		log:        config.Logger,
+		idempotent: config.Idempotent,
 	}
 
 	// Set the default logger if not provided.
@@ -67,8 +81,13 @@ func (h *DeleteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.Delete(r.Context(), id); err != nil {
-		write(w, http.StatusBadRequest, &Response{
+	err = h.service.Delete(r.Context(), id)
+	if h.idempotent && (err == nil || errors.Is(err, service.ErrNoRowsAffected) || errors.Is(err, gorm.ErrRecordNotFound)) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if err != nil {
+		write(w, statusOf(err), &Response{
 			Message: "Failed to delete the record.",
 			Err:     err,
 		})
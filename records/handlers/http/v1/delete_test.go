@@ -0,0 +1,81 @@
+package v1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mrinalwahal/boilerplate/records/service"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDeleteHandler_ServeHTTP(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	tests := []struct {
+		name       string
+		idempotent bool
+		err        error
+		wantStatus int
+	}{
+		{
+			name:       "strict delete of existing record",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "strict delete of missing record",
+			err:        service.ErrNoRowsAffected,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "idempotent delete of existing record",
+			idempotent: true,
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "idempotent delete of missing record",
+			idempotent: true,
+			err:        service.ErrNoRowsAffected,
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "idempotent delete w/ unavailable service",
+			idempotent: true,
+			err:        service.ErrServiceUnavailable,
+			wantStatus: http.StatusServiceUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Create the handler.
+			handler := NewDeleteHandler(&DeleteHandlerConfig{
+				Service:    config.service,
+				Logger:     config.log,
+				Idempotent: tt.idempotent,
+			})
+
+			// Initialize test request and response recorder.
+			id := uuid.New()
+			r := httptest.NewRequest(http.MethodDelete, "/v1/"+id.String(), nil)
+			r.SetPathValue("id", id.String())
+			w := httptest.NewRecorder()
+
+			config.service.EXPECT().Delete(gomock.Any(), id).Return(tt.err).Times(1)
+
+			// Serve the request.
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Logf("response: %s", w.Body.String())
+				t.Fatalf("expected status code %d, got %d", tt.wantStatus, w.Code)
+			}
+			if tt.wantStatus == http.StatusNoContent && w.Body.Len() != 0 {
+				t.Fatalf("expected an empty body, got %s", w.Body.String())
+			}
+		})
+	}
+}
@@ -1,9 +1,70 @@
 package v1
 
-import "fmt"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
 
-var ErrInvalidRecordID = fmt.Errorf("invalid record id")
-var ErrRecordNotFound = fmt.Errorf("record not found")
-var ErrInvalidRequestOptions = fmt.Errorf("invalid request options")
-var ErrInvalidUserID = fmt.Errorf("invalid user id")
+	"github.com/mrinalwahal/boilerplate/pkg/errs"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidRecordID = errs.Wrap(errs.InvalidArgument, "invalid record id")
+var ErrRecordNotFound = errs.Wrap(errs.NotFound, "record not found")
+var ErrInvalidRequestOptions = errs.Wrap(errs.InvalidArgument, "invalid request options")
+var ErrInvalidUserID = errs.Wrap(errs.InvalidArgument, "invalid user id")
 var ErrInvalidJWTClaims = fmt.Errorf("invalid jwt claims")
+var ErrPreconditionFailed = fmt.Errorf("precondition failed")
+var ErrRouteNotFound = errs.Wrap(errs.NotFound, "route not found")
+
+// StatusClientClosedRequest is the non-standard status code of the requests whose client went away
+// before the response was written, as popularised by nginx.
+const StatusClientClosedRequest = 499
+
+// FieldErrors holds the validation errors of the request fields, keyed by the field name.
+//
+// It's returned with `422 Unprocessable Entity` and rendered in the `fields` object of the response.
+type FieldErrors map[string]string
+
+// Error returns the validation errors sorted by the field name.
+//
+// This method is required to implement the `error` interface.
+func (e FieldErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for field, message := range e {
+		messages = append(messages, field+": "+message)
+	}
+	sort.Strings(messages)
+	return "invalid fields: " + strings.Join(messages, "; ")
+}
+
+// statusOf returns the HTTP status code for the error returned by the service layer.
+//
+// The errors of the cancelled and the expired contexts aren't failures of the server,
+// so they're reported as `499` and `504` instead.
+// The request bodies exceeding their cap, like the decompressed ones, are reported as `413`.
+func statusOf(err error) int {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, context.Canceled):
+		return StatusClientClosedRequest
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, gorm.ErrRecordNotFound), errs.Is(err, errs.NotFound):
+		return http.StatusNotFound
+	case errs.Is(err, errs.Unavailable):
+		return http.StatusServiceUnavailable
+	case errs.Is(err, errs.PermissionDenied):
+		return http.StatusForbidden
+	case errs.Is(err, errs.AlreadyExists):
+		return http.StatusConflict
+	case errs.Is(err, errs.Unprocessable):
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,163 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/mrinalwahal/boilerplate/model"
+	"github.com/mrinalwahal/boilerplate/pkg/errs"
+)
+
+var ErrInvalidFieldset = errs.Wrap(errs.InvalidArgument, "invalid fields")
+
+// selectable lists the fields of the records a fieldset can include or exclude.
+var selectable = map[string]bool{
+	"id":          true,
+	"title":       true,
+	"description": true,
+	"user_id":     true,
+	"created_at":  true,
+	"updated_at":  true,
+	"deleted_at":  true,
+}
+
+// Fieldset is the sparse set of the fields of the records rendered in the responses.
+type Fieldset struct {
+
+	//	Fields named by the client.
+	fields map[string]bool
+
+	//	Whether the named fields are excluded rather than included.
+	exclude bool
+}
+
+// ParseFieldset parses the value of the `fields` query parameter.
+//
+// It's either a comma-separated list of the fields to include, like `title,description`,
+// or of the fields to exclude, each prefixed with `-`, like `-deleted_at`. The two can't be mixed.
+func ParseFieldset(raw string) (*Fieldset, error) {
+	fieldset := Fieldset{
+		fields: make(map[string]bool),
+	}
+	for i, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		excluded := strings.HasPrefix(name, "-")
+		if i == 0 {
+			fieldset.exclude = excluded
+		} else if excluded != fieldset.exclude {
+			return nil, fmt.Errorf("%w: can't mix included and excluded fields", ErrInvalidFieldset)
+		}
+
+		name = strings.TrimPrefix(name, "-")
+		if !selectable[name] {
+			return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidFieldset, name)
+		}
+		fieldset.fields[name] = true
+	}
+	return &fieldset, nil
+}
+
+// selected checks whether the field is rendered.
+func (f *Fieldset) selected(name string) bool {
+	return f.fields[name] != f.exclude
+}
+
+// fieldsWriter is the response writer which carries the fieldset of the records in the response.
+type fieldsWriter struct {
+	http.ResponseWriter
+
+	//	Fieldset requested by the client.
+	fieldset *Fieldset
+}
+
+// Unwrap returns the original response writer.
+func (w *fieldsWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Fields middleware renders only the fields of the records selected by the `fields` query parameter
+// in the JSON responses written by the handlers. See `ParseFieldset` for its syntax.
+//
+// The invalid fieldsets are rejected with `400 Bad Request`. The protobuf responses carry all the fields.
+func Fields(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw := r.URL.Query().Get("fields")
+		if raw == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		fieldset, err := ParseFieldset(raw)
+		if err != nil {
+			write(w, http.StatusBadRequest, &Response{
+				Message: "Invalid fields.",
+				Err:     err,
+			})
+			return
+		}
+
+		next.ServeHTTP(&fieldsWriter{
+			ResponseWriter: w,
+			fieldset:       fieldset,
+		}, r)
+	})
+}
+
+// project returns the response with only the selected fields of its records.
+// The responses which carry no records are returned as they are.
+func project(response any, fieldset *Fieldset) (any, error) {
+	var r Response
+	switch response := response.(type) {
+	case *Response:
+		r = *response
+	case Response:
+		r = response
+	default:
+		return response, nil
+	}
+
+	switch r.Data.(type) {
+	case *model.Record, []*model.Record:
+	default:
+		return response, nil
+	}
+
+	encoded, err := json.Marshal(r.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode the numbers as `json.Number` to re-encode them without any loss of precision.
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+
+	var decoded any
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, err
+	}
+
+	switch decoded := decoded.(type) {
+	case map[string]any:
+		fieldset.filter(decoded)
+	case []any:
+		for _, item := range decoded {
+			if object, ok := item.(map[string]any); ok {
+				fieldset.filter(object)
+			}
+		}
+	}
+	r.Data = decoded
+	return &r, nil
+}
+
+// filter deletes the fields which aren't selected from the decoded record.
+func (f *Fieldset) filter(record map[string]any) {
+	for name := range record {
+		if !f.selected(name) {
+			delete(record, name)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mrinalwahal/boilerplate/model"
+	"go.uber.org/mock/gomock"
+)
+
+func TestParseFieldset(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		raw     string
+		wantErr bool
+	}{
+		{
+			name: "include fields",
+			raw:  "title,description",
+		},
+		{
+			name: "exclude fields",
+			raw:  "-deleted_at,-user_id",
+		},
+		{
+			name:    "mix included and excluded fields",
+			raw:     "title,-deleted_at",
+			wantErr: true,
+		},
+		{
+			name:    "unknown field",
+			raw:     "-password",
+			wantErr: true,
+		},
+		{
+			name:    "empty field",
+			raw:     "title,",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseFieldset(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFieldset() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrInvalidFieldset) {
+				t.Errorf("ParseFieldset() error = %v, want %v", err, ErrInvalidFieldset)
+			}
+		})
+	}
+}
+
+func TestFields(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	record := &model.Record{
+		Base: model.Base{
+			ID: uuid.New(),
+		},
+		Title:       "Record 1",
+		Description: "Description 1",
+		UserID:      uuid.New(),
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		list       bool
+		wantStatus int
+		wantKeys   []string
+	}{
+		{
+			name:       "get record w/ included fields",
+			query:      "title,description",
+			wantStatus: http.StatusOK,
+			wantKeys:   []string{"description", "title"},
+		},
+		{
+			name:       "get record w/ excluded fields",
+			query:      "-deleted_at,-user_id,-description",
+			wantStatus: http.StatusOK,
+			wantKeys:   []string{"created_at", "id", "title", "updated_at"},
+		},
+		{
+			name:       "list records w/ excluded fields",
+			query:      "-deleted_at",
+			list:       true,
+			wantStatus: http.StatusOK,
+			wantKeys:   []string{"created_at", "description", "id", "title", "updated_at", "user_id"},
+		},
+		{
+			name:       "get record w/ mixed fields",
+			query:      "title,-deleted_at",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			var handler http.Handler
+			if tt.list {
+				handler = NewListHandler(&ListHandlerConfig{
+					Service: config.service,
+					Logger:  config.log,
+				})
+			} else {
+				handler = NewGetHandler(&GetHandlerConfig{
+					Service: config.service,
+					Logger:  config.log,
+				})
+			}
+
+			// Set the expectation.
+			if tt.wantStatus == http.StatusOK {
+				if tt.list {
+					config.service.EXPECT().List(gomock.Any(), gomock.Any()).Return([]*model.Record{record}, nil).Times(1)
+				} else {
+					config.service.EXPECT().Get(gomock.Any(), record.ID).Return(record, nil).Times(1)
+				}
+			}
+
+			// Initialize test request and response recorder.
+			r := httptest.NewRequest(http.MethodGet, "/?fields="+tt.query, nil)
+			r.SetPathValue("id", record.ID.String())
+			w := httptest.NewRecorder()
+
+			Fields(handler).ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("ServeHTTP() = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var resp struct {
+				Data json.RawMessage `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode the response: %v", err)
+			}
+
+			var data map[string]any
+			if tt.list {
+				var items []map[string]any
+				if err := json.Unmarshal(resp.Data, &items); err != nil || len(items) != 1 {
+					t.Fatalf("failed to decode the records: %v", err)
+				}
+				data = items[0]
+			} else if err := json.Unmarshal(resp.Data, &data); err != nil {
+				t.Fatalf("failed to decode the record: %v", err)
+			}
+
+			keys := make([]string, 0, len(data))
+			for key := range data {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			if len(keys) != len(tt.wantKeys) {
+				t.Fatalf("keys = %v, want %v", keys, tt.wantKeys)
+			}
+			for i := range keys {
+				if keys[i] != tt.wantKeys[i] {
+					t.Fatalf("keys = %v, want %v", keys, tt.wantKeys)
+				}
+			}
+		})
+	}
+}
@@ -57,6 +57,16 @@ func NewGetHandler(config *GetHandlerConfig) Handler {
 func (h *GetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	h.log.DebugContext(r.Context(), "handling request")
 
+	// Skip the repeated load if the record was already loaded by the `LoadRecord` middleware.
+	if record, exists := loaded(r.Context()); exists {
+		lastModified(w, record)
+		write(w, http.StatusOK, &Response{
+			Message: "The record was retrieved successfully.",
+			Data:    record,
+		})
+		return
+	}
+
 	id, err := uuid.Parse(r.PathValue("id"))
 	if err != nil {
 		write(w, http.StatusBadRequest, &Response{
@@ -67,13 +77,14 @@ func (h *GetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	record, err := h.service.Get(r.Context(), id)
 	if err != nil {
-		write(w, http.StatusBadRequest, &Response{
+		write(w, statusOf(err), &Response{
 			Message: "Failed to get the record.",
 			Err:     err,
 		})
 		return
 	}
 
+	lastModified(w, record)
 	write(w, http.StatusOK, &Response{
 		Message: "The record was retrieved successfully.",
 		Data:    record,
@@ -1,11 +1,13 @@
 package v1
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/mrinalwahal/boilerplate/model"
@@ -76,6 +78,41 @@ func TestGetHandler_ServeHTTP(t *testing.T) {
 			},
 			want: http.StatusOK,
 		},
+		{
+			name: "get record after the client went away",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					ctx, cancel := context.WithCancel(context.Background())
+					cancel()
+					req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+					req.SetPathValue("id", recordID.String())
+					return req
+				}(),
+			},
+			expectation: environment.service.EXPECT().Get(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, ID uuid.UUID) (*model.Record, error) {
+				return nil, fmt.Errorf("failed to get the record: %w", ctx.Err())
+			}),
+			want: StatusClientClosedRequest,
+		},
+		{
+			name: "get record past the deadline",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					ctx, cancel := context.WithDeadline(context.Background(), time.Now())
+					t.Cleanup(cancel)
+					req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+					req.SetPathValue("id", recordID.String())
+					return req
+				}(),
+			},
+			expectation: environment.service.EXPECT().Get(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, ID uuid.UUID) (*model.Record, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}),
+			want: http.StatusGatewayTimeout,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
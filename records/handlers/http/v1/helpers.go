@@ -2,8 +2,15 @@ package v1
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/mrinalwahal/boilerplate/model"
 )
 
 // Default HTTP Response structure.
@@ -12,6 +19,14 @@ type Response struct {
 	Data    interface{} `json:"data,omitempty"`
 	Message string      `json:"message,omitempty"`
 	Err     error       `json:"error,omitempty"`
+
+	// RequestID is the ID of the request, returned on the errors which aren't tied to a handler.
+	RequestID string `json:"request_id,omitempty"`
+
+	// Status is the HTTP status code the response is written with when it's rendered by `ServeHTTP`.
+	// It's not part of the body.
+	// Default: `500`
+	Status int `json:"-"`
 }
 
 // Error returns the error message.
@@ -24,46 +39,170 @@ func (r *Response) Error() string {
 	return r.Message
 }
 
+// ServeHTTP writes the response with its status.
+//
+// It lets the handlers and the services abort with `panic(&Response{...})` on a broken business invariant,
+// and have the `Recover` middleware render the response instead of a generic `500`.
+func (r *Response) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	status := r.Status
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+	w.Header().Set("Content-Type", "application/json")
+	write(w, status, r)
+}
+
 func (r Response) MarshalJSON() ([]byte, error) {
 	var errorMsg string
+	var fields FieldErrors
 	if r.Err != nil {
 		errorMsg = r.Err.Error()
+		errors.As(r.Err, &fields)
 	}
 	var structure = struct {
-		Data    interface{} `json:"data,omitempty"`
-		Message string      `json:"message,omitempty"`
-		Err     string      `json:"error,omitempty"`
+		Data      interface{}       `json:"data,omitempty"`
+		Message   string            `json:"message,omitempty"`
+		Err       string            `json:"error,omitempty"`
+		Fields    map[string]string `json:"fields,omitempty"`
+		RequestID string            `json:"request_id,omitempty"`
 	}{
-		Data:    r.Data,
-		Message: r.Message,
-		Err:     errorMsg,
+		Data:      r.Data,
+		Message:   r.Message,
+		Err:       errorMsg,
+		Fields:    fields,
+		RequestID: r.RequestID,
 	}
 	return json.Marshal(structure)
 }
 
 func (r *Response) UnmarshalJSON(data []byte) error {
 	var structure = struct {
-		Data    interface{} `json:"data,omitempty"`
-		Message string      `json:"message,omitempty"`
-		Err     string      `json:"error,omitempty"`
+		Data      interface{}       `json:"data,omitempty"`
+		Message   string            `json:"message,omitempty"`
+		Err       string            `json:"error,omitempty"`
+		Fields    map[string]string `json:"fields,omitempty"`
+		RequestID string            `json:"request_id,omitempty"`
 	}{}
 	if err := json.Unmarshal(data, &structure); err != nil {
 		return err
 	}
 	r.Data = structure.Data
 	r.Message = structure.Message
+	r.RequestID = structure.RequestID
 	if structure.Err != "" {
 		r.Err = fmt.Errorf(structure.Err)
 	}
+	if structure.Fields != nil {
+		r.Err = FieldErrors(structure.Fields)
+	}
 	return nil
 }
 
+// prefersMinimal checks whether the client prefers a minimal response over the full representation.
+//
+// The preference is read from the `Prefer: return=minimal` request header.
+// Link: https://www.rfc-editor.org/rfc/rfc7240#section-4.2
+func prefersMinimal(r *http.Request) bool {
+	for _, header := range r.Header.Values("Prefer") {
+		for _, preference := range strings.Split(header, ",") {
+			if strings.EqualFold(strings.TrimSpace(preference), "return=minimal") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// describe sets the `Location` and `ETag` headers of the supplied record on the response.
+//
+// The location is resolved against the original request URI, so it holds even if the router is mounted under a prefix.
+func describe(w http.ResponseWriter, r *http.Request, record *model.Record) {
+	location := r.URL.Path
+	if uri, err := url.ParseRequestURI(r.RequestURI); err == nil {
+		location = uri.Path
+	}
+	if id := record.ID.String(); path.Base(location) != id {
+		location = path.Join(location, id)
+	}
+	w.Header().Set("Location", location)
+	w.Header().Set("ETag", fmt.Sprintf(`"%s-%d"`, record.ID, record.UpdatedAt.UnixNano()))
+	lastModified(w, record)
+}
+
+// lastModified sets the `Last-Modified` header of the supplied record on the response.
+//
+// Clients can send it back in the `If-Unmodified-Since` header to update the record only if it hasn't changed since.
+func lastModified(w http.ResponseWriter, record *model.Record) {
+	w.Header().Set("Last-Modified", record.UpdatedAt.UTC().Format(http.TimeFormat))
+}
+
+// modifiedSince checks whether the record was modified after the time in the supplied `If-Unmodified-Since` header.
+//
+// HTTP dates have a resolution of one second, so the modification time is truncated to the second before comparing.
+// An invalid date is ignored, as required by the RFC.
+// Link: https://www.rfc-editor.org/rfc/rfc9110#section-13.1.4
+func modifiedSince(record *model.Record, header string) bool {
+	since, err := http.ParseTime(header)
+	if err != nil {
+		return false
+	}
+	return record.UpdatedAt.Truncate(time.Second).After(since)
+}
+
 // write writes the data to the supplied http response writer.
+//
+// If the writer is wrapped by the `Localize` middleware, the message of the error is rendered in the requested language.
+// If the writer is wrapped by the `Protobuf` middleware, the response is encoded as a `recordspb.Response` when it maps to one.
+// If the writer is wrapped by the `Fields` middleware, only the selected fields of the records are rendered.
+// If the writer is wrapped by the `FieldNames` middleware, the keys are rendered in the configured naming convention.
 func write(w http.ResponseWriter, status int, response any) error {
+	if lw, ok := unwrap[*localeWriter](w); ok {
+		if r, ok := response.(*Response); ok && r.Err != nil {
+			if message, ok := lw.localize(r.Err); ok {
+				localized := *r
+				localized.Message = message
+				response = &localized
+			}
+		}
+	}
+	if _, ok := unwrap[*protobufWriter](w); ok {
+		if message, ok := envelope(response); ok {
+			return encodeProtobuf(w, status, message)
+		}
+	}
+	if fw, ok := unwrap[*fieldsWriter](w); ok {
+		projected, err := project(response, fw.fieldset)
+		if err != nil {
+			return err
+		}
+		response = projected
+	}
+	if nw, ok := unwrap[*namingWriter](w); ok {
+		renamed, err := rename(response, nw.naming)
+		if err != nil {
+			return err
+		}
+		response = renamed
+	}
 	w.WriteHeader(status)
 	return encode(w, response)
 }
 
+// unwrap finds the response writer of the supplied type in the chain of the wrapped writers.
+func unwrap[T http.ResponseWriter](w http.ResponseWriter) (T, bool) {
+	for {
+		if found, ok := w.(T); ok {
+			return found, true
+		}
+		wrapper, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			var zero T
+			return zero, false
+		}
+		w = wrapper.Unwrap()
+	}
+}
+
 // decode decodes the request body into the supplied type.
 func decode[T any](r *http.Request) (T, error) {
 	defer r.Body.Close()
@@ -1,10 +1,12 @@
 package v1
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
 
 	"github.com/dyninc/qstring"
+	"github.com/mrinalwahal/boilerplate/model"
 	"github.com/mrinalwahal/boilerplate/records/service"
 )
 
@@ -25,6 +27,18 @@ type ListOptions struct {
 
 	//	Title of the record.
 	Title string `query:"name"`
+
+	//	Search term matched against the title and the description of the record.
+	Search string `query:"search"`
+
+	//	Rank the records matching the search term by relevance, the exact titles first.
+	OrderByRelevance bool `query:"orderByRelevance" qstring:"orderByRelevance"`
+
+	//	Include the soft-deleted records along with the active ones.
+	IncludeDeleted bool `query:"includeDeleted" qstring:"includeDeleted"`
+
+	//	Only list the soft-deleted records, the most recently deleted first.
+	DeletedOnly bool `query:"deletedOnly" qstring:"deletedOnly"`
 }
 
 // List handler lists the records.
@@ -88,14 +102,32 @@ func (h *ListHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	// Call the service method that performs the required operation.
 	records, err := h.service.List(r.Context(), &service.ListOptions{
-		Title:          options.Title,
-		Skip:           options.Skip,
-		Limit:          options.Limit,
-		OrderBy:        options.OrderBy,
-		OrderDirection: options.OrderDirection,
+		Title:            options.Title,
+		Search:           options.Search,
+		OrderByRelevance: options.OrderByRelevance,
+		Skip:             options.Skip,
+		Limit:            options.Limit,
+		OrderBy:          service.OrderBy(options.OrderBy),
+		OrderDirection:   service.OrderDirection(options.OrderDirection),
+		IncludeDeleted:   options.IncludeDeleted,
+		DeletedOnly:      options.DeletedOnly,
 	})
+
+	// Render an empty list as `[]` rather than `null`.
+	if records == nil {
+		records = []*model.Record{}
+	}
+
+	if errors.Is(err, service.ErrPartialResults) {
+		write(w, http.StatusOK, &Response{
+			Message: "Some of the records could not be retrieved.",
+			Err:     err,
+			Data:    records,
+		})
+		return
+	}
 	if err != nil {
-		write(w, http.StatusBadRequest, &Response{
+		write(w, statusOf(err), &Response{
 			Message: "Failed to list the records.",
 			Err:     err,
 		})
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/mrinalwahal/boilerplate/model"
+	"github.com/mrinalwahal/boilerplate/records/service"
 	"go.uber.org/mock/gomock"
 )
 
@@ -155,3 +156,110 @@ func TestListHandler_ServeHTTP(t *testing.T) {
 		})
 	}
 }
+
+func TestListHandler_ServeHTTP_PartialResults(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	h := &ListHandler{
+		service: config.service,
+		log:     config.log,
+	}
+
+	// The service layer returns the records which were scanned successfully.
+	config.service.EXPECT().List(gomock.Any(), gomock.Any()).Return([]*model.Record{
+		{
+			Title: "Record 1",
+		},
+	}, fmt.Errorf("%w: 1 rows failed to scan", service.ErrPartialResults)).Times(1)
+
+	// Initialize test request and response recorder.
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("ListHandler.ServeHTTP() = %v, want %v", w.Code, http.StatusOK)
+	}
+
+	// Decode the body
+	var resp struct {
+		Data []interface{} `json:"data"`
+		Err  string        `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("ListHandler.ServeHTTP() = %v", err)
+	}
+
+	if len(resp.Data) != 1 {
+		t.Errorf("expected 1 record, got %d", len(resp.Data))
+	}
+	if resp.Err == "" {
+		t.Errorf("expected the response to flag the partial results")
+	}
+}
+
+func TestListHandler_ServeHTTP_Empty(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	h := &ListHandler{
+		service: config.service,
+		log:     config.log,
+	}
+
+	// The service layer finds no records.
+	config.service.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+
+	// Initialize test request and response recorder.
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("ListHandler.ServeHTTP() = %v, want %v", w.Code, http.StatusOK)
+	}
+
+	// Decode the body
+	var resp struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("ListHandler.ServeHTTP() = %v", err)
+	}
+
+	if string(resp.Data) != "[]" {
+		t.Errorf("expected data to be [], got %s", resp.Data)
+	}
+}
+
+func TestListHandler_ServeHTTP_Deleted(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	h := &ListHandler{
+		service: config.service,
+		log:     config.log,
+	}
+
+	// The camel case query parameters must reach the service layer.
+	config.service.EXPECT().List(gomock.Any(), gomock.Cond(func(x any) bool {
+		options, ok := x.(*service.ListOptions)
+		return ok && options.DeletedOnly && !options.IncludeDeleted
+	})).Return(nil, nil).Times(1)
+
+	// Initialize test request and response recorder.
+	r := httptest.NewRequest(http.MethodGet, "/?deletedOnly=true", nil)
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("ListHandler.ServeHTTP() = %v, want %v", w.Code, http.StatusOK)
+	}
+}
@@ -0,0 +1,66 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/mrinalwahal/boilerplate/model"
+	"github.com/mrinalwahal/boilerplate/pkg/middleware"
+	"github.com/mrinalwahal/boilerplate/records/service"
+	"gorm.io/gorm"
+)
+
+// ctxKey is the type of the keys used to store values in the request context.
+type ctxKey int
+
+// recordKey is the key used to store the record loaded from the path in the context.
+const recordKey ctxKey = iota
+
+// LoadRecord middleware loads the record identified by the `{id}` path value and stores it in the request context.
+//
+// The record is fetched through the service layer, so the Row Level Security (RLS) checks apply.
+// If the record doesn't exist, or isn't visible to the requester, the request is rejected with a `404` before it reaches the handler.
+func LoadRecord(svc service.Service) middleware.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			id, err := uuid.Parse(r.PathValue("id"))
+			if err != nil {
+				write(w, http.StatusBadRequest, &Response{
+					Message: "Invalid ID.",
+					Err:     ErrInvalidRecordID,
+				})
+				return
+			}
+
+			record, err := svc.Get(r.Context(), id)
+			if err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					write(w, http.StatusNotFound, &Response{
+						Message: "The record was not found.",
+						Err:     ErrRecordNotFound,
+					})
+					return
+				}
+				write(w, statusOf(err), &Response{
+					Message: "Failed to get the record.",
+					Err:     err,
+				})
+				return
+			}
+
+			// Write the record to the request context.
+			r = r.WithContext(context.WithValue(r.Context(), recordKey, record))
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// loaded returns the record loaded by the `LoadRecord` middleware, if any.
+func loaded(ctx context.Context) (*model.Record, bool) {
+	record, exists := ctx.Value(recordKey).(*model.Record)
+	return record, exists
+}
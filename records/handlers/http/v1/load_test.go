@@ -0,0 +1,69 @@
+package v1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mrinalwahal/boilerplate/model"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestLoadRecord(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	t.Run("load existing record", func(t *testing.T) {
+
+		id := uuid.New()
+
+		// The service layer is expected to return the record.
+		config.service.EXPECT().Get(gomock.Any(), id).Return(&model.Record{
+			Base: model.Base{
+				ID: id,
+			},
+			Title: "Test Record",
+		}, nil).Times(1)
+
+		// Initialize test request and response recorder.
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.SetPathValue("id", id.String())
+		w := httptest.NewRecorder()
+
+		// Serve the request.
+		LoadRecord(config.service)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			record, exists := loaded(r.Context())
+			if !exists || record.ID != id {
+				t.Errorf("expected record %v in the context, got %v", id, record)
+			}
+			w.WriteHeader(http.StatusOK)
+		})).ServeHTTP(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status code %d, got %d", http.StatusOK, w.Code)
+		}
+	})
+
+	t.Run("load missing record", func(t *testing.T) {
+
+		// The service layer is expected to not find the record.
+		config.service.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, gorm.ErrRecordNotFound).Times(1)
+
+		// Initialize test request and response recorder.
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.SetPathValue("id", uuid.NewString())
+		w := httptest.NewRecorder()
+
+		// Serve the request.
+		LoadRecord(config.service)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("expected the handler to not be called")
+		})).ServeHTTP(w, r)
+
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("expected status code %d, got %d", http.StatusNotFound, w.Code)
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/mrinalwahal/boilerplate/pkg/middleware"
+	"github.com/mrinalwahal/boilerplate/records/service"
+	"golang.org/x/text/language"
+)
+
+// DefaultLanguage is the language the messages fall back to.
+const DefaultLanguage = "en"
+
+// Catalog translates the errors into localized messages.
+type Catalog interface {
+
+	// Message returns the message of the error in the supplied language, if the catalog has one.
+	//
+	// The language is a base language subtag. For example, `en` or `es`.
+	Message(lang string, err error) (string, bool)
+}
+
+// MapCatalog is the catalog which holds the messages of the errors per language.
+type MapCatalog map[string]map[error]string
+
+// Message returns the message of the entry in the language which matches the supplied error.
+func (c MapCatalog) Message(lang string, err error) (string, bool) {
+	for target, message := range c[lang] {
+		if errors.Is(err, target) {
+			return message, true
+		}
+	}
+	return "", false
+}
+
+// DefaultCatalog holds the messages of the sentinel errors in English and Spanish.
+var DefaultCatalog = MapCatalog{
+	"en": {
+		service.ErrInvalidTitle:          "The title is invalid.",
+		service.ErrNoFieldsToUpdate:      "There are no fields to update.",
+		service.ErrInvalidFilters:        "The filters are invalid.",
+		service.ErrInvalidOrderBy:        "The records can't be ordered by this field.",
+		service.ErrInvalidOrderDirection: "The order direction is invalid.",
+		service.ErrInvalidGroupBy:        "The records can't be grouped by this field.",
+		service.ErrQuotaExceeded:         "You have reached the maximum number of records.",
+		service.ErrTooManyCopies:         "You have reached the maximum number of copies of this record.",
+		service.ErrDuplicateTitle:        "You already have a record with this title.",
+		service.ErrResultTooLarge:        "Too many records match the filters; narrow them or page through the records.",
+		service.ErrServiceUnavailable:    "The service is temporarily unavailable.",
+		ErrInvalidJWTClaims:              "The JWT claims are invalid.",
+		ErrPreconditionFailed:            "The record was modified since you last read it.",
+		ErrRouteNotFound:                 "The requested route doesn't exist.",
+	},
+	"es": {
+		service.ErrInvalidTitle:          "El título no es válido.",
+		service.ErrNoFieldsToUpdate:      "No hay campos para actualizar.",
+		service.ErrInvalidFilters:        "Los filtros no son válidos.",
+		service.ErrInvalidOrderBy:        "Los registros no se pueden ordenar por este campo.",
+		service.ErrInvalidOrderDirection: "La dirección de ordenación no es válida.",
+		service.ErrInvalidGroupBy:        "Los registros no se pueden agrupar por este campo.",
+		service.ErrQuotaExceeded:         "Has alcanzado el número máximo de registros.",
+		service.ErrTooManyCopies:         "Has alcanzado el número máximo de copias de este registro.",
+		service.ErrDuplicateTitle:        "Ya tienes un registro con este título.",
+		service.ErrResultTooLarge:        "Demasiados registros coinciden con los filtros; acótalos o pagina los registros.",
+		service.ErrServiceUnavailable:    "El servicio no está disponible temporalmente.",
+		ErrInvalidJWTClaims:              "Las credenciales del JWT no son válidas.",
+		ErrPreconditionFailed:            "El registro ha cambiado desde la última vez que lo leíste.",
+		ErrRouteNotFound:                 "La ruta solicitada no existe.",
+	},
+}
+
+// localeWriter is the response writer which carries the languages accepted by the client.
+type localeWriter struct {
+	http.ResponseWriter
+
+	//	Catalog of the localized messages.
+	catalog Catalog
+
+	//	Languages accepted by the client, in the order of preference.
+	languages []string
+}
+
+// Unwrap returns the original response writer.
+func (w *localeWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Localize middleware renders the messages of the error responses in the language requested by the `Accept-Language` header.
+//
+// The errors which have no message in the requested languages fall back to `DefaultLanguage`,
+// and then to the message set by the handler.
+func Localize(catalog Catalog) middleware.Middleware {
+	return func(next http.Handler) http.Handler {
+		if catalog == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(&localeWriter{
+				ResponseWriter: w,
+				catalog:        catalog,
+				languages:      accepted(r),
+			}, r)
+		})
+	}
+}
+
+// localize returns the message of the error in the most preferred language the catalog has it in.
+func (w *localeWriter) localize(err error) (string, bool) {
+	for _, lang := range append(w.languages, DefaultLanguage) {
+		if message, ok := w.catalog.Message(lang, err); ok {
+			return message, true
+		}
+	}
+	return "", false
+}
+
+// accepted returns the base languages accepted by the client in the order of preference.
+func accepted(r *http.Request) []string {
+	tags, _, err := language.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+	if err != nil {
+		return nil
+	}
+
+	var languages []string
+	for _, tag := range tags {
+		base, _ := tag.Base()
+		languages = append(languages, base.String())
+	}
+	return languages
+}
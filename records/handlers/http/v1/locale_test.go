@@ -0,0 +1,80 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mrinalwahal/boilerplate/records/service"
+	"go.uber.org/mock/gomock"
+)
+
+func TestLocalize(t *testing.T) {
+
+	// Setup the test environment.
+	environment := configure(t)
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		want           string
+	}{
+		{
+			name: "render message in english by default",
+			want: "The title is invalid.",
+		},
+		{
+			name:           "render message in english",
+			acceptLanguage: "en-US,en;q=0.9",
+			want:           "The title is invalid.",
+		},
+		{
+			name:           "render message in spanish",
+			acceptLanguage: "es-ES,es;q=0.9,en;q=0.8",
+			want:           "El título no es válido.",
+		},
+		{
+			name:           "fall back to english for unknown language",
+			acceptLanguage: "fr-FR",
+			want:           "The title is invalid.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			environment.service.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, service.ErrInvalidTitle).Times(1)
+
+			h := Localize(DefaultCatalog)(&UpdateHandler{
+				service: environment.service,
+				log:     environment.log,
+			})
+
+			// Initialize test request and response recorder.
+			id := uuid.New()
+			r := httptest.NewRequest(http.MethodPatch, "/"+id.String(), bytes.NewBufferString(`{"title": "title"}`))
+			r.SetPathValue("id", id.String())
+			if tt.acceptLanguage != "" {
+				r.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, r)
+
+			if w.Code != http.StatusUnprocessableEntity {
+				t.Fatalf("ServeHTTP() = %v, want %v", w.Code, http.StatusUnprocessableEntity)
+			}
+
+			var resp Response
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode the response: %v", err)
+			}
+
+			if resp.Message != tt.want {
+				t.Errorf("message = %q, want %q", resp.Message, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/mrinalwahal/boilerplate/pkg/middleware"
+)
+
+// FieldNaming is the naming convention of the keys in the JSON responses.
+type FieldNaming int
+
+const (
+
+	// SnakeCase renders the keys as declared in the `json` tags. For example, `user_id`.
+	SnakeCase FieldNaming = iota
+
+	// CamelCase renders the keys in camelCase. For example, `userId`.
+	CamelCase
+)
+
+// namingWriter is the response writer which carries the naming convention of the response keys.
+type namingWriter struct {
+	http.ResponseWriter
+
+	//	Naming convention of the response keys.
+	naming FieldNaming
+}
+
+// Unwrap returns the original response writer.
+func (w *namingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// FieldNames middleware renders the keys of the JSON responses written by the handlers in the supplied naming convention.
+func FieldNames(naming FieldNaming) middleware.Middleware {
+	return func(next http.Handler) http.Handler {
+		if naming == SnakeCase {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(&namingWriter{
+				ResponseWriter: w,
+				naming:         naming,
+			}, r)
+		})
+	}
+}
+
+// rename re-encodes the supplied data with its keys renamed to the naming convention.
+func rename(data any, naming FieldNaming) (any, error) {
+	if naming == SnakeCase {
+		return data, nil
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode the numbers as `json.Number` to re-encode them without any loss of precision.
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+
+	var decoded any
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, err
+	}
+	return renameKeys(decoded, camelCase), nil
+}
+
+// renameKeys recursively renames the keys of the objects in the supplied decoded JSON value.
+func renameKeys(value any, fn func(string) string) any {
+	switch value := value.(type) {
+	case map[string]any:
+		renamed := make(map[string]any, len(value))
+		for key, v := range value {
+			renamed[fn(key)] = renameKeys(v, fn)
+		}
+		return renamed
+	case []any:
+		for i, v := range value {
+			value[i] = renameKeys(v, fn)
+		}
+		return value
+	}
+	return value
+}
+
+// camelCase converts the supplied snake_case key to camelCase.
+func camelCase(key string) string {
+	parts := strings.Split(key, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
@@ -0,0 +1,95 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mrinalwahal/boilerplate/model"
+	"go.uber.org/mock/gomock"
+)
+
+func TestFieldNames(t *testing.T) {
+
+	// Setup the test environment.
+	environment := configure(t)
+
+	// Test record.
+	record := &model.Record{
+		Title:  "Test Record",
+		UserID: uuid.New(),
+	}
+	record.ID = uuid.New()
+
+	tests := []struct {
+		name    string
+		naming  FieldNaming
+		want    string
+		wantNot string
+	}{
+		{
+			name:    "render keys in snake_case by default",
+			naming:  SnakeCase,
+			want:    "user_id",
+			wantNot: "userId",
+		},
+		{
+			name:    "render keys in camelCase",
+			naming:  CamelCase,
+			want:    "userId",
+			wantNot: "user_id",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			environment.service.EXPECT().Get(gomock.Any(), record.ID).Return(record, nil).Times(1)
+
+			h := FieldNames(tt.naming)(&GetHandler{
+				service: environment.service,
+				log:     environment.log,
+			})
+
+			// Initialize test request and response recorder.
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.SetPathValue("id", record.ID.String())
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, r)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("ServeHTTP() = %v, want %v", w.Code, http.StatusOK)
+			}
+
+			// Decode the body
+			var body struct {
+				Data map[string]any `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("failed to decode the response: %v", err)
+			}
+
+			if _, exists := body.Data[tt.want]; !exists {
+				t.Errorf("expected the key %q in %v", tt.want, body.Data)
+			}
+			if _, exists := body.Data[tt.wantNot]; exists {
+				t.Errorf("expected no key %q in %v", tt.wantNot, body.Data)
+			}
+		})
+	}
+}
+
+func Test_camelCase(t *testing.T) {
+	tests := map[string]string{
+		"owner_id":   "ownerId",
+		"created_at": "createdAt",
+		"title":      "title",
+	}
+	for key, want := range tests {
+		if got := camelCase(key); got != want {
+			t.Errorf("camelCase(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/mrinalwahal/boilerplate/pkg/middleware"
+)
+
+// unknownRequestID is the placeholder returned for the requests which don't carry a request ID.
+const unknownRequestID = "unknown"
+
+// NotFound handler responds to the requests which don't match any route with the JSON `404 Not Found` envelope.
+//
+// The response carries the ID of the request, so the clients can quote it when reporting the error.
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	id, exists := middleware.RequestIDFromContext(r.Context())
+	if !exists || id == "" {
+		id = unknownRequestID
+	}
+	w.Header().Set("Content-Type", "application/json")
+	write(w, http.StatusNotFound, &Response{
+		Message:   "The requested route doesn't exist.",
+		Err:       ErrRouteNotFound,
+		RequestID: id,
+	})
+}
@@ -0,0 +1,132 @@
+package v1
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mrinalwahal/boilerplate/api/grpc/recordspb"
+	"github.com/mrinalwahal/boilerplate/model"
+	"google.golang.org/protobuf/proto"
+)
+
+// ProtobufContentType is the media type of the responses encoded as `recordspb.Response`.
+const ProtobufContentType = "application/x-protobuf"
+
+// protobufWriter is the response writer of the requests which prefer the protobuf responses.
+type protobufWriter struct {
+	http.ResponseWriter
+}
+
+// Unwrap returns the original response writer.
+func (w *protobufWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Protobuf middleware serves the responses written by the handlers as `recordspb.Response` messages
+// to the clients which prefer `application/x-protobuf` over JSON in their `Accept` header.
+//
+// The responses whose data has no protobuf mapping, like the aggregates, are still served in JSON.
+func Protobuf(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		// The representation depends on the header, so the caches must key the responses by it.
+		w.Header().Add("Vary", "Accept")
+
+		if prefersProtobuf(strings.Join(r.Header.Values("Accept"), ",")) {
+			w = &protobufWriter{
+				ResponseWriter: w,
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// prefersProtobuf checks whether the value of the `Accept` header ranks protobuf above JSON.
+//
+// The wildcards match JSON, so a tie is served in JSON.
+func prefersProtobuf(header string) bool {
+	var protobufQ, jsonQ float64
+	for _, item := range strings.Split(header, ",") {
+		mediatype, params, err := mime.ParseMediaType(item)
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if raw, exists := params["q"]; exists {
+			if value, err := strconv.ParseFloat(raw, 64); err == nil {
+				q = value
+			}
+		}
+
+		switch mediatype {
+		case ProtobufContentType:
+			protobufQ = max(protobufQ, q)
+		case "application/json", "application/*", "*/*":
+			jsonQ = max(jsonQ, q)
+		}
+	}
+	return protobufQ > jsonQ
+}
+
+// envelope converts the response to its protobuf message.
+// It reports false if the data of the response has no protobuf mapping.
+func envelope(response any) (*recordspb.Response, bool) {
+	var r *Response
+	switch response := response.(type) {
+	case *Response:
+		r = response
+	case Response:
+		r = &response
+	default:
+		return nil, false
+	}
+
+	message := recordspb.Response{
+		Message:   r.Message,
+		RequestId: r.RequestID,
+	}
+	if r.Err != nil {
+		message.Error = r.Err.Error()
+		var fields FieldErrors
+		if errors.As(r.Err, &fields) {
+			message.Fields = fields
+		}
+	}
+
+	switch data := r.Data.(type) {
+	case nil:
+	case *model.Record:
+		message.Data = &recordspb.Response_Record{
+			Record: recordspb.FromModel(data),
+		}
+	case []*model.Record:
+		records := recordspb.ListResponse{
+			Records: make([]*recordspb.Record, 0, len(data)),
+		}
+		for _, record := range data {
+			records.Records = append(records.Records, recordspb.FromModel(record))
+		}
+		message.Data = &recordspb.Response_Records{
+			Records: &records,
+		}
+	default:
+		return nil, false
+	}
+	return &message, true
+}
+
+// encodeProtobuf writes the protobuf message with its content type and the supplied status.
+func encodeProtobuf(w http.ResponseWriter, status int, message proto.Message) error {
+	encoded, err := proto.Marshal(message)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", ProtobufContentType)
+	w.WriteHeader(status)
+	_, err = w.Write(encoded)
+	return err
+}
@@ -0,0 +1,179 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mrinalwahal/boilerplate/api/grpc/recordspb"
+	"github.com/mrinalwahal/boilerplate/model"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestProtobuf(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	record := &model.Record{
+		Base: model.Base{
+			ID: uuid.New(),
+		},
+		Title:  "Record 1",
+		UserID: uuid.New(),
+	}
+
+	// Get handler wrapped by the middleware.
+	get := Protobuf(NewGetHandler(&GetHandlerConfig{
+		Service: config.service,
+		Logger:  config.log,
+	}))
+
+	// serve serves a request for the record with the supplied `Accept` header.
+	serve := func(h http.Handler, target, accept string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+		r.SetPathValue("id", record.ID.String())
+		if accept != "" {
+			r.Header.Set("Accept", accept)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("get record accepting protobuf", func(t *testing.T) {
+
+		config.service.EXPECT().Get(gomock.Any(), record.ID).Return(record, nil).Times(1)
+
+		w := serve(get, "/", "application/x-protobuf")
+		if w.Code != http.StatusOK {
+			t.Fatalf("ServeHTTP() = %v, want %v", w.Code, http.StatusOK)
+		}
+		if got := w.Header().Get("Content-Type"); got != ProtobufContentType {
+			t.Errorf("Content-Type = %q, want %q", got, ProtobufContentType)
+		}
+
+		var resp recordspb.Response
+		if err := proto.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode the response: %v", err)
+		}
+		if got := resp.GetRecord(); got.GetId() != record.ID.String() || got.GetTitle() != record.Title {
+			t.Errorf("unexpected record %v", got)
+		}
+		if resp.GetMessage() == "" {
+			t.Errorf("expected the message in the envelope")
+		}
+	})
+
+	t.Run("get record accepting json", func(t *testing.T) {
+
+		config.service.EXPECT().Get(gomock.Any(), record.ID).Return(record, nil).Times(1)
+
+		w := serve(get, "/", "application/json")
+		if w.Code != http.StatusOK {
+			t.Fatalf("ServeHTTP() = %v, want %v", w.Code, http.StatusOK)
+		}
+
+		var resp Response
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode the response: %v", err)
+		}
+		if resp.Data == nil {
+			t.Errorf("expected data to be non-nil")
+		}
+		if got := w.Header().Get("Vary"); got != "Accept" {
+			t.Errorf("Vary = %q, want %q", got, "Accept")
+		}
+	})
+
+	t.Run("get invalid record accepting protobuf", func(t *testing.T) {
+
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.SetPathValue("id", "invalid")
+		r.Header.Set("Accept", ProtobufContentType)
+		w := httptest.NewRecorder()
+		get.ServeHTTP(w, r)
+
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("ServeHTTP() = %v, want %v", w.Code, http.StatusBadRequest)
+		}
+
+		var resp recordspb.Response
+		if err := proto.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode the response: %v", err)
+		}
+		if resp.GetMessage() != "Invalid ID." || resp.GetData() != nil {
+			t.Errorf("unexpected response %v", &resp)
+		}
+	})
+
+	t.Run("list records accepting protobuf", func(t *testing.T) {
+
+		config.service.EXPECT().List(gomock.Any(), gomock.Any()).Return([]*model.Record{record, record}, nil).Times(1)
+
+		list := Protobuf(NewListHandler(&ListHandlerConfig{
+			Service: config.service,
+			Logger:  config.log,
+		}))
+
+		w := serve(list, "/", "application/x-protobuf, application/json;q=0.5")
+		if w.Code != http.StatusOK {
+			t.Fatalf("ServeHTTP() = %v, want %v", w.Code, http.StatusOK)
+		}
+
+		var resp recordspb.Response
+		if err := proto.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode the response: %v", err)
+		}
+		if got := len(resp.GetRecords().GetRecords()); got != 2 {
+			t.Errorf("expected 2 records, got %d", got)
+		}
+	})
+
+	t.Run("aggregate records accepting protobuf", func(t *testing.T) {
+
+		config.service.EXPECT().Aggregate(gomock.Any(), gomock.Any()).Return([]*model.Group{}, nil).Times(1)
+
+		aggregate := Protobuf(NewAggregateHandler(&AggregateHandlerConfig{
+			Service: config.service,
+			Logger:  config.log,
+		}))
+
+		// The groups have no protobuf mapping, so they're served in JSON.
+		w := serve(aggregate, "/?groupBy=title", "application/x-protobuf")
+		if w.Code != http.StatusOK {
+			t.Fatalf("ServeHTTP() = %v, want %v", w.Code, http.StatusOK)
+		}
+
+		var resp Response
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode the response: %v", err)
+		}
+	})
+}
+
+func Test_prefersProtobuf(t *testing.T) {
+
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{header: "", want: false},
+		{header: "application/json", want: false},
+		{header: "application/x-protobuf", want: true},
+		{header: "application/x-protobuf, */*;q=0.1", want: true},
+		{header: "application/json, application/x-protobuf", want: false},
+		{header: "application/json;q=0.5, application/x-protobuf", want: true},
+		{header: "application/x-protobuf;q=0", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := prefersProtobuf(tt.header); got != tt.want {
+				t.Errorf("prefersProtobuf(%q) = %v, want %v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mrinalwahal/boilerplate/pkg/middleware"
+)
+
+func TestResponse_ServeHTTP(t *testing.T) {
+
+	var errInvariant = errors.New("record is locked")
+
+	tests := []struct {
+		name       string
+		response   *Response
+		wantStatus int
+	}{
+		{
+			name: "render panic w/ status",
+			response: &Response{
+				Status:  http.StatusConflict,
+				Message: "The record is locked.",
+				Err:     errInvariant,
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name: "render panic w/o status",
+			response: &Response{
+				Message: "The record is locked.",
+				Err:     errInvariant,
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Handler which aborts with the response.
+			h := middleware.Recover(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				panic(tt.response)
+			}))
+
+			// Initialize test request and response recorder.
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("ServeHTTP() = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("Content-Type"); got != "application/json" {
+				t.Errorf("Content-Type = %q, want %q", got, "application/json")
+			}
+
+			var resp Response
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode the response: %v", err)
+			}
+			if resp.Message != tt.response.Message || resp.Err == nil || resp.Err.Error() != errInvariant.Error() {
+				t.Errorf("unexpected response %+v", resp)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package v1
 import (
 	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/mrinalwahal/boilerplate/records/service"
@@ -12,7 +13,26 @@ import (
 type UpdateOptions struct {
 
 	//	Title of the record.
-	Title string `json:"title" validate:"required"`
+	Title string `json:"title"`
+
+	//	Description of the record.
+	Description string `json:"description"`
+}
+
+// validate the options.
+func (o *UpdateOptions) validate() error {
+	fields := FieldErrors{}
+	if o.Title == "" && o.Description == "" {
+		fields["title"] = "either title or description is required"
+		fields["description"] = "either title or description is required"
+	}
+	if o.Title != "" && strings.TrimSpace(o.Title) == "" {
+		fields["title"] = "must not be blank"
+	}
+	if len(fields) > 0 {
+		return fields
+	}
+	return nil
 }
 
 // Update handler update a new record.
@@ -72,26 +92,67 @@ func (h *UpdateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Reject the update if the record changed since the time declared by the client.
+	if header := r.Header.Get("If-Unmodified-Since"); header != "" {
+		record, exists := loaded(r.Context())
+		if !exists {
+			record, err = h.service.Get(r.Context(), id)
+			if err != nil {
+				write(w, statusOf(err), &Response{
+					Message: "Failed to get the record.",
+					Err:     err,
+				})
+				return
+			}
+		}
+		if modifiedSince(record, header) {
+			write(w, http.StatusPreconditionFailed, &Response{
+				Message: "The record was modified since the time in the If-Unmodified-Since header.",
+				Err:     ErrPreconditionFailed,
+			})
+			return
+		}
+	}
+
 	options, err := decode[UpdateOptions](r)
 	if err != nil {
-		write(w, http.StatusBadRequest, &Response{
+		write(w, statusOf(err), &Response{
 			Message: "Invalid request options.",
 			Err:     err,
 		})
 		return
 	}
 
+	// Validate the request options.
+	if err := options.validate(); err != nil {
+		write(w, http.StatusUnprocessableEntity, &Response{
+			Message: "Failed validate request options.",
+			Err:     err,
+		})
+		return
+	}
+
 	record, err := h.service.Update(r.Context(), id, &service.UpdateOptions{
-		Title: options.Title,
+		Title:       options.Title,
+		Description: options.Description,
 	})
 	if err != nil {
-		write(w, http.StatusBadRequest, &Response{
+		write(w, statusOf(err), &Response{
 			Message: "Failed to update the record.",
 			Err:     err,
 		})
 		return
 	}
 
+	describe(w, r, record)
+
+	// Skip the body if the client prefers a minimal response.
+	if prefersMinimal(r) {
+		w.Header().Set("Preference-Applied", "return=minimal")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
 	write(w, http.StatusOK, &Response{
 		Message: "The record was updated successfully.",
 		Data:    record,
@@ -7,11 +7,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/mrinalwahal/boilerplate/model"
+	"github.com/mrinalwahal/boilerplate/pkg/middleware"
 	"github.com/mrinalwahal/boilerplate/records/service"
 	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
 )
 
 func TestUpdateHandler_ServeHTTP(t *testing.T) {
@@ -71,6 +74,24 @@ func TestUpdateHandler_ServeHTTP(t *testing.T) {
 			wantStatus: http.StatusOK,
 			wantErr:    false,
 		},
+		{
+			name: "ignore fields outside of the allowlist",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/%s", recordID.String()), bytes.NewBufferString(fmt.Sprintf(`{"title": "Updated Title", "user_id": %q}`, uuid.New())))
+					req.SetPathValue("id", recordID.String())
+					return req
+				}(),
+			},
+			expectation: environment.service.EXPECT().Update(gomock.Any(), recordID, &service.UpdateOptions{
+				Title: "Updated Title",
+			}).Return(&model.Record{
+				Title: "Updated Title",
+			}, nil),
+			wantStatus: http.StatusOK,
+			wantErr:    false,
+		},
 		{
 			name: "return invalid title after updating record",
 			args: args{
@@ -128,3 +149,200 @@ func TestUpdateHandler_ServeHTTP(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateHandler_ServeHTTP_IfUnmodifiedSince(t *testing.T) {
+
+	// Setup the test environment.
+	environment := configure(t)
+
+	// Test record, last modified an hour ago.
+	record := &model.Record{
+		Title: "Test Record",
+	}
+	record.ID = uuid.New()
+	record.UpdatedAt = time.Now().Add(-time.Hour)
+
+	h := &UpdateHandler{
+		service: environment.service,
+		log:     environment.log,
+	}
+
+	// Returns the update request carrying the supplied precondition.
+	request := func(since time.Time) *http.Request {
+		req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/%s", record.ID), bytes.NewBufferString(`{"title": "Updated Title"}`))
+		req.SetPathValue("id", record.ID.String())
+		req.Header.Set("If-Unmodified-Since", since.UTC().Format(http.TimeFormat))
+		return req
+	}
+
+	t.Run("reject update w/ stale precondition", func(t *testing.T) {
+
+		environment.service.EXPECT().Get(gomock.Any(), record.ID).Return(record, nil).Times(1)
+		environment.service.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, request(record.UpdatedAt.Add(-time.Minute)))
+
+		if w.Code != http.StatusPreconditionFailed {
+			t.Errorf("UpdateHandler.ServeHTTP() = %v, want %v", w.Code, http.StatusPreconditionFailed)
+		}
+	})
+
+	t.Run("update w/ fresh precondition", func(t *testing.T) {
+
+		environment.service.EXPECT().Get(gomock.Any(), record.ID).Return(record, nil).Times(1)
+		environment.service.EXPECT().Update(gomock.Any(), record.ID, gomock.Any()).Return(record, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, request(record.UpdatedAt))
+
+		if w.Code != http.StatusOK {
+			t.Errorf("UpdateHandler.ServeHTTP() = %v, want %v", w.Code, http.StatusOK)
+		}
+		if w.Header().Get("Last-Modified") == "" {
+			t.Errorf("expected the Last-Modified header to be set")
+		}
+	})
+}
+
+func TestUpdateHandler_ServeHTTP_Validation(t *testing.T) {
+
+	// Setup the test environment.
+	environment := configure(t)
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantFields []string
+	}{
+		{
+			name:       "malformed json",
+			body:       `{"title": 42}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "no fields to update",
+			body:       `{}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantFields: []string{"title", "description"},
+		},
+		{
+			name:       "blank title",
+			body:       `{"title": "   "}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantFields: []string{"title"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			h := &UpdateHandler{
+				service: environment.service,
+				log:     environment.log,
+			}
+
+			// Initialize test request and response recorder.
+			id := uuid.New()
+			r := httptest.NewRequest(http.MethodPatch, "/"+id.String(), bytes.NewBufferString(tt.body))
+			r.SetPathValue("id", id.String())
+			w := httptest.NewRecorder()
+
+			// The service layer should not be reached.
+			environment.service.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("ServeHTTP() = %v, want %v", w.Code, tt.wantStatus)
+			}
+
+			var response Response
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode the response: %v", err)
+			}
+
+			fields, _ := response.Err.(FieldErrors)
+			if len(fields) != len(tt.wantFields) {
+				t.Fatalf("expected field errors for %v, got %v", tt.wantFields, fields)
+			}
+			for _, field := range tt.wantFields {
+				if _, ok := fields[field]; !ok {
+					t.Errorf("expected a field error for %q, got %v", field, fields)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateHandler_ServeHTTP_RLS(t *testing.T) {
+
+	// Setup the test environment.
+	environment := configure(t)
+
+	// Test UUIDs of the record and its owner.
+	recordID := uuid.New()
+	owner := uuid.New()
+
+	tests := []struct {
+		name string
+
+		// userID is the ID of the user in the JWT claims of the request.
+		userID uuid.UUID
+
+		// expectation is the response of the service layer.
+		// With the RLS checks, the record isn't found for anyone but its owner.
+		expectation func() *gomock.Call
+
+		// wantStatus is the status code we expect in response.
+		wantStatus int
+	}{
+		{
+			name:   "update by the owner",
+			userID: owner,
+			expectation: func() *gomock.Call {
+				return environment.service.EXPECT().Update(gomock.Any(), recordID, gomock.Any()).Return(&model.Record{
+					Base: model.Base{
+						ID: recordID,
+					},
+					Title:  "Updated Record",
+					UserID: owner,
+				}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "update by another user",
+			userID: uuid.New(),
+			expectation: func() *gomock.Call {
+				return environment.service.EXPECT().Update(gomock.Any(), recordID, gomock.Any()).Return(nil, gorm.ErrRecordNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.expectation().Times(1)
+
+			h := NewUpdateHandler(&UpdateHandlerConfig{
+				Service: environment.service,
+				Logger:  environment.log,
+			})
+
+			// Initialize test request and response recorder.
+			r := httptest.NewRequest(http.MethodPatch, "/"+recordID.String(), bytes.NewBufferString(`{"title": "Updated Record"}`))
+			r.SetPathValue("id", recordID.String())
+			r = r.WithContext(middleware.WithJWTClaims(r.Context(), middleware.JWTClaims{
+				XUserID: tt.userID,
+			}))
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("ServeHTTP() = %v, want %v: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+		})
+	}
+}
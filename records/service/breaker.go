@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/mrinalwahal/boilerplate/model"
+	"github.com/mrinalwahal/boilerplate/pkg/db/breaker"
+	"github.com/mrinalwahal/boilerplate/records/db"
+)
+
+// guarded wraps the database layer and routes its calls through a circuit breaker.
+type guarded struct {
+	db.DB
+
+	//	Circuit breaker.
+	breaker *breaker.Breaker
+}
+
+func (g *guarded) Create(ctx context.Context, options *db.CreateOptions) (record *model.Record, err error) {
+	err = g.breaker.Do(func() error {
+		record, err = g.DB.Create(ctx, options)
+		return err
+	})
+	return
+}
+
+func (g *guarded) List(ctx context.Context, options *db.ListOptions) (records []*model.Record, err error) {
+	err = g.breaker.Do(func() error {
+		records, err = g.DB.List(ctx, options)
+		return err
+	})
+	return
+}
+
+// ListChan routes the stream through the circuit breaker.
+// Its outcome is recorded once the stream completes, with the error it ended with.
+func (g *guarded) ListChan(ctx context.Context, options *db.ListOptions) (<-chan *model.Record, <-chan error) {
+	done, err := g.breaker.Begin()
+	if err != nil {
+		records := make(chan *model.Record)
+		close(records)
+		errc := make(chan error, 1)
+		errc <- err
+		close(errc)
+		return records, errc
+	}
+
+	records, errc := g.DB.ListChan(ctx, options)
+
+	// Buffered, so the goroutine can exit even if the consumer never reads the error.
+	out := make(chan error, 1)
+	go func() {
+		defer close(out)
+		err := <-errc
+		done(err)
+		if err != nil {
+			out <- err
+		}
+	}()
+	return records, out
+}
+
+func (g *guarded) Get(ctx context.Context, ID uuid.UUID) (record *model.Record, err error) {
+	err = g.breaker.Do(func() error {
+		record, err = g.DB.Get(ctx, ID)
+		return err
+	})
+	return
+}
+
+func (g *guarded) GetByIDs(ctx context.Context, IDs []uuid.UUID) (records map[uuid.UUID]*model.Record, err error) {
+	err = g.breaker.Do(func() error {
+		records, err = g.DB.GetByIDs(ctx, IDs)
+		return err
+	})
+	return
+}
+
+func (g *guarded) Exists(ctx context.Context, ID uuid.UUID) (exists bool, err error) {
+	err = g.breaker.Do(func() error {
+		exists, err = g.DB.Exists(ctx, ID)
+		return err
+	})
+	return
+}
+
+func (g *guarded) GetIncludingDeleted(ctx context.Context, ID uuid.UUID) (record *model.Record, err error) {
+	err = g.breaker.Do(func() error {
+		record, err = g.DB.GetIncludingDeleted(ctx, ID)
+		return err
+	})
+	return
+}
+
+func (g *guarded) Update(ctx context.Context, ID uuid.UUID, options *db.UpdateOptions) (record *model.Record, err error) {
+	err = g.breaker.Do(func() error {
+		record, err = g.DB.Update(ctx, ID, options)
+		return err
+	})
+	return
+}
+
+func (g *guarded) Count(ctx context.Context, options *db.CountOptions) (count int64, err error) {
+	err = g.breaker.Do(func() error {
+		count, err = g.DB.Count(ctx, options)
+		return err
+	})
+	return
+}
+
+func (g *guarded) UpdateResult(ctx context.Context, ID uuid.UUID, options *db.UpdateOptions) (result *model.Result, err error) {
+	err = g.breaker.Do(func() error {
+		result, err = g.DB.UpdateResult(ctx, ID, options)
+		return err
+	})
+	return
+}
+
+func (g *guarded) CountDeleted(ctx context.Context) (count int64, err error) {
+	err = g.breaker.Do(func() error {
+		count, err = g.DB.CountDeleted(ctx)
+		return err
+	})
+	return
+}
+
+func (g *guarded) Aggregate(ctx context.Context, options *db.AggregateOptions) (groups []*model.Group, err error) {
+	err = g.breaker.Do(func() error {
+		groups, err = g.DB.Aggregate(ctx, options)
+		return err
+	})
+	return
+}
+
+func (g *guarded) DistinctTitles(ctx context.Context, prefix string, limit int) (titles []string, err error) {
+	err = g.breaker.Do(func() error {
+		titles, err = g.DB.DistinctTitles(ctx, prefix, limit)
+		return err
+	})
+	return
+}
+
+func (g *guarded) Upsert(ctx context.Context, options *db.CreateOptions) (record *model.Record, created bool, err error) {
+	err = g.breaker.Do(func() error {
+		record, created, err = g.DB.Upsert(ctx, options)
+		return err
+	})
+	return
+}
+
+func (g *guarded) CreateIfNotExists(ctx context.Context, options *db.CreateOptions) (record *model.Record, created bool, err error) {
+	err = g.breaker.Do(func() error {
+		record, created, err = g.DB.CreateIfNotExists(ctx, options)
+		return err
+	})
+	return
+}
+
+func (g *guarded) Import(ctx context.Context, options *db.ImportOptions) (record *model.Record, err error) {
+	err = g.breaker.Do(func() error {
+		record, err = g.DB.Import(ctx, options)
+		return err
+	})
+	return
+}
+
+func (g *guarded) CreateAuditLog(ctx context.Context, options *db.CreateAuditLogOptions) (log *model.AuditLog, err error) {
+	err = g.breaker.Do(func() error {
+		log, err = g.DB.CreateAuditLog(ctx, options)
+		return err
+	})
+	return
+}
+
+func (g *guarded) ListAuditLogs(ctx context.Context, options *db.ListAuditLogsOptions) (logs []*model.AuditLog, err error) {
+	err = g.breaker.Do(func() error {
+		logs, err = g.DB.ListAuditLogs(ctx, options)
+		return err
+	})
+	return
+}
+
+func (g *guarded) Reassign(ctx context.Context, ID uuid.UUID, userID uuid.UUID) (record *model.Record, err error) {
+	err = g.breaker.Do(func() error {
+		record, err = g.DB.Reassign(ctx, ID, userID)
+		return err
+	})
+	return
+}
+
+func (g *guarded) Delete(ctx context.Context, ID uuid.UUID) error {
+	return g.breaker.Do(func() error {
+		return g.DB.Delete(ctx, ID)
+	})
+}
+
+func (g *guarded) DeleteResult(ctx context.Context, ID uuid.UUID) (result *model.Result, err error) {
+	err = g.breaker.Do(func() error {
+		result, err = g.DB.DeleteResult(ctx, ID)
+		return err
+	})
+	return
+}
+
+func (g *guarded) Touch(ctx context.Context, ID uuid.UUID) error {
+	return g.breaker.Do(func() error {
+		return g.DB.Touch(ctx, ID)
+	})
+}
+
+// WithTransaction routes the transaction through the circuit breaker.
+// The calls made with the transactional database layer are not guarded individually.
+func (g *guarded) WithTransaction(ctx context.Context, fn func(db.DB) error, options ...*sql.TxOptions) error {
+	return g.breaker.Do(func() error {
+		return g.DB.WithTransaction(ctx, fn, options...)
+	})
+}
+
+// WithNestedTransaction routes the transaction through the circuit breaker.
+func (g *guarded) WithNestedTransaction(ctx context.Context, fn func(db.DB) error) error {
+	return g.breaker.Do(func() error {
+		return g.DB.WithNestedTransaction(ctx, fn)
+	})
+}
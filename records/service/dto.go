@@ -1,7 +1,11 @@
 package service
 
 import (
+	"strings"
+	"time"
+
 	"github.com/google/uuid"
+	"github.com/mrinalwahal/boilerplate/records/db"
 )
 
 // CreateOptions holds the options for creating a new record.
@@ -10,6 +14,9 @@ type CreateOptions struct {
 	//	Title of the record.
 	Title string
 
+	//	Description of the record.
+	Description string
+
 	// ID of the user who is creating the record.
 	UserID uuid.UUID
 }
@@ -24,39 +31,237 @@ func (o *CreateOptions) validate() error {
 	return nil
 }
 
+// ImportOptions holds the options for importing a historical record.
+//
+// Unlike `CreateOptions`, the timestamps are set explicitly instead of being generated.
+type ImportOptions struct {
+
+	//	Title of the record.
+	Title string
+
+	//	Description of the record.
+	Description string
+
+	// ID of the user who created the record.
+	UserID uuid.UUID
+
+	//	Time when the record was originally created.
+	CreatedAt time.Time
+
+	//	Time when the record was last updated.
+	//	If it is zero, it defaults to the creation time.
+	UpdatedAt time.Time
+}
+
+func (o *ImportOptions) validate() error {
+	if o.Title == "" {
+		return ErrInvalidTitle
+	}
+	if o.UserID == uuid.Nil {
+		return ErrInvalidUserID
+	}
+	if o.CreatedAt.IsZero() {
+		return ErrInvalidTimestamps
+	}
+	if !o.UpdatedAt.IsZero() && o.UpdatedAt.Before(o.CreatedAt) {
+		return ErrInvalidTimestamps
+	}
+	return nil
+}
+
 type ListOptions struct {
 
 	//	Title of the record.
 	Title string
+	//	Search term matched against the title and the description of the record.
+	Search string
+	//	Rank the records matching `Search` by relevance, before `OrderBy`:
+	//	the exact titles first, then the titles starting with the term, then the other matches.
+	//	It's only meaningful along with `Search`, so it's rejected without it.
+	OrderByRelevance bool
 	//	Skip for pagination.
 	Skip int
 	//	Limit for pagination.
 	Limit int
 	//	Order by field.
-	OrderBy string
+	OrderBy OrderBy
 	//	Order by direction.
-	OrderDirection string
+	//	It's only meaningful along with `OrderBy`, so it's rejected without it.
+	//	Default: `asc`, when `OrderBy` is set.
+	OrderDirection OrderDirection
+	//	Include the soft-deleted records along with the active ones.
+	IncludeDeleted bool
+	//	Only list the soft-deleted records, the most recently deleted first.
+	//	It can't be combined with `IncludeDeleted`.
+	DeletedOnly bool
+}
+
+// validate validates the options.
+//
+// Skipping more than `maxSkip` records is rejected to nudge the clients toward narrower filters.
+// If `maxSkip` is zero, the offset is unbounded.
+//
+// Pages larger than `maxLimit` are rejected; it depends on the role of the requester, see `service.maxLimit`.
+func (o *ListOptions) validate(maxSkip, maxLimit int) error {
+	if o.Skip < 0 || (maxSkip > 0 && o.Skip > maxSkip) {
+		return ErrInvalidFilters
+	}
+	if o.Limit < 0 || o.Limit > maxLimit {
+		return ErrInvalidFilters
+	}
+	if o.OrderBy != "" && !o.OrderBy.valid() {
+		return ErrInvalidOrderBy
+	}
+	if o.OrderDirection != "" && (!o.OrderDirection.valid() || o.OrderBy == "") {
+		return ErrInvalidOrderDirection
+	}
+	if o.IncludeDeleted && o.DeletedOnly {
+		return ErrInvalidFilters
+	}
+	if o.OrderByRelevance && o.Search == "" {
+		return ErrInvalidFilters
+	}
+	return nil
+}
+
+type CountOptions struct {
+
+	//	Title of the record.
+	Title string
+}
+
+type AggregateOptions struct {
+
+	//	Field the records are grouped by.
+	GroupBy GroupBy
+	//	Number of leading characters of the title which make up the key, when grouping by `GroupByTitlePrefix`.
+	//	Default: `1`
+	PrefixLength int
 }
 
-func (o *ListOptions) validate() error {
-	if o.Skip < 0 {
+// validate validates the options.
+func (o *AggregateOptions) validate() error {
+	if !o.GroupBy.valid() {
+		return ErrInvalidGroupBy
+	}
+	if o.PrefixLength < 0 || o.PrefixLength > MaxPrefixLength {
+		return ErrInvalidFilters
+	}
+	return nil
+}
+
+// MaxPrefixLength is the maximum length of the title prefix the records can be grouped by.
+const MaxPrefixLength = 64
+
+type ListAuditLogsOptions struct {
+
+	//	ID of the user who performed the mutations.
+	ActorID uuid.UUID
+	//	Mutation performed on the records. For example, `model.AuditActionUpdate`.
+	Action string
+	//	ID of the mutated record.
+	EntityID uuid.UUID
+	//	Only the entries created at or after this time.
+	Since time.Time
+	//	Only the entries created before this time.
+	Until time.Time
+	//	Skip for pagination.
+	Skip int
+	//	Limit for pagination.
+	Limit int
+}
+
+// validate validates the options.
+//
+// If `maxSkip` is zero, the offset is unbounded.
+func (o *ListAuditLogsOptions) validate(maxSkip int) error {
+	if o.Skip < 0 || (maxSkip > 0 && o.Skip > maxSkip) {
 		return ErrInvalidFilters
 	}
 	if o.Limit < 0 || o.Limit > 100 {
 		return ErrInvalidFilters
 	}
+	if !o.Since.IsZero() && !o.Until.IsZero() && !o.Since.Before(o.Until) {
+		return ErrInvalidFilters
+	}
 	return nil
 }
 
+// OrderBy is the field by which the records can be ordered.
+type OrderBy string
+
+const (
+	OrderByCreatedAt OrderBy = "created_at"
+	OrderByUpdatedAt OrderBy = "updated_at"
+	OrderByTitle     OrderBy = "title"
+)
+
+// valid checks whether the field is in the list of allowed fields.
+func (o OrderBy) valid() bool {
+	switch o {
+	case OrderByCreatedAt, OrderByUpdatedAt, OrderByTitle:
+		return true
+	}
+	return false
+}
+
+// GroupBy is the field by which the records can be aggregated.
+type GroupBy string
+
+const (
+	// GroupByDay groups the records by the day they were created on.
+	GroupByDay GroupBy = db.GroupByDay
+
+	// GroupByTitlePrefix groups the records by the leading characters of their title.
+	GroupByTitlePrefix GroupBy = db.GroupByTitlePrefix
+)
+
+// valid checks whether the field is in the list of allowed fields.
+func (g GroupBy) valid() bool {
+	switch g {
+	case GroupByDay, GroupByTitlePrefix:
+		return true
+	}
+	return false
+}
+
+// OrderDirection is the direction in which the records can be ordered.
+type OrderDirection string
+
+const (
+	OrderDirectionAsc  OrderDirection = "asc"
+	OrderDirectionDesc OrderDirection = "desc"
+)
+
+// valid checks whether the direction is in the list of allowed directions.
+func (o OrderDirection) valid() bool {
+	switch o {
+	case OrderDirectionAsc, OrderDirectionDesc:
+		return true
+	}
+	return false
+}
+
 type UpdateOptions struct {
 
 	//	Title of the record.
 	Title string
+
+	//	Description of the record.
+	Description string
 }
 
 func (o *UpdateOptions) validate() error {
-	if o.Title == "" {
+	if o.empty() {
+		return ErrNoFieldsToUpdate
+	}
+	if o.Title != "" && strings.TrimSpace(o.Title) == "" {
 		return ErrInvalidTitle
 	}
 	return nil
 }
+
+// empty checks whether none of the updatable fields are set.
+func (o *UpdateOptions) empty() bool {
+	return o.Title == "" && o.Description == ""
+}
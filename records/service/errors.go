@@ -1,12 +1,34 @@
 package service
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/mrinalwahal/boilerplate/pkg/db/breaker"
+	"github.com/mrinalwahal/boilerplate/pkg/errs"
+	"github.com/mrinalwahal/boilerplate/records/db"
+)
 
 var (
-	ErrInvalidOptions  = fmt.Errorf("invalid options")
-	ErrInvalidRecordID = fmt.Errorf("invalid record_id")
-	ErrInvalidUserID   = fmt.Errorf("invalid user_id")
-	ErrInvalidTitle    = fmt.Errorf("invalid title")
-	ErrInvalidFilters  = fmt.Errorf("invalid filters")
-	ErrInvalidDB       = fmt.Errorf("invalid db")
+	ErrInvalidOptions   = errs.Wrap(errs.InvalidArgument, "invalid options")
+	ErrInvalidRecordID  = errs.Wrap(errs.InvalidArgument, "invalid record_id")
+	ErrInvalidUserID    = errs.Wrap(errs.InvalidArgument, "invalid user_id")
+	ErrInvalidTitle     = errs.Wrap(errs.Unprocessable, "invalid title")
+	ErrNoFieldsToUpdate = errs.Wrap(errs.Unprocessable, "no fields to update")
+	ErrInvalidFilters   = errs.Wrap(errs.InvalidArgument, "invalid filters")
+	ErrInvalidDB        = fmt.Errorf("invalid db")
+
+	ErrInvalidTimestamps = errs.Wrap(errs.InvalidArgument, "invalid timestamps")
+
+	ErrServiceUnavailable = breaker.ErrServiceUnavailable
+	ErrQuotaExceeded      = errs.Wrap(errs.PermissionDenied, "quota exceeded")
+	ErrTooManyCopies      = errs.Wrap(errs.Unprocessable, "too many copies")
+	ErrPermissionDenied   = errs.Wrap(errs.PermissionDenied, "permission denied")
+	ErrPartialResults     = db.ErrPartialResults
+	ErrNoRowsAffected     = db.ErrNoRowsAffected
+	ErrDuplicateTitle     = db.ErrDuplicateTitle
+	ErrResultTooLarge     = db.ErrResultTooLarge
+
+	ErrInvalidOrderBy        = errs.Wrap(errs.InvalidArgument, "invalid order_by")
+	ErrInvalidOrderDirection = errs.Wrap(errs.InvalidArgument, "invalid order_direction")
+	ErrInvalidGroupBy        = errs.Wrap(errs.InvalidArgument, "invalid group_by")
 )
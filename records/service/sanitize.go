@@ -0,0 +1,53 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// TitlePolicy is how the HTML markup in the titles of the records is handled.
+//
+// The titles are rendered by web UIs downstream, so the markup is a stored XSS vector.
+// It's defense-in-depth: the UIs must still escape the titles they render.
+type TitlePolicy int
+
+const (
+
+	// TitleAllowHTML stores the titles as they are.
+	TitleAllowHTML TitlePolicy = iota
+
+	// TitleSanitizeHTML strips the HTML tags from the titles.
+	TitleSanitizeHTML
+
+	// TitleRejectHTML rejects the titles containing HTML tags with `ErrInvalidTitle`.
+	TitleRejectHTML
+)
+
+// tags matches the HTML tags and comments.
+var tags = regexp.MustCompile(`<!--.*?-->|</?[a-zA-Z][^>]*>`)
+
+// scripts matches the script and style elements, whose content is dropped along with their tags.
+var scripts = regexp.MustCompile(`(?is)<script\b.*?</script\s*>|<style\b.*?</style\s*>`)
+
+// title applies the policy to the title and returns the title to store.
+//
+// The titles containing control characters are rejected with `ErrInvalidTitle` regardless of the policy.
+func (p TitlePolicy) title(title string) (string, error) {
+	if strings.ContainsFunc(title, unicode.IsControl) {
+		return "", ErrInvalidTitle
+	}
+
+	switch p {
+	case TitleSanitizeHTML:
+		title = strings.TrimSpace(tags.ReplaceAllString(scripts.ReplaceAllString(title, ""), ""))
+		if title == "" {
+			return "", ErrInvalidTitle
+		}
+	case TitleRejectHTML:
+		if tags.MatchString(title) {
+			return "", ErrInvalidTitle
+		}
+	}
+	return title, nil
+}
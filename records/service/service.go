@@ -3,19 +3,78 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"log/slog"
 
 	"github.com/google/uuid"
 	"github.com/mrinalwahal/boilerplate/model"
+	"github.com/mrinalwahal/boilerplate/pkg/db/breaker"
+	"github.com/mrinalwahal/boilerplate/pkg/middleware"
 	"github.com/mrinalwahal/boilerplate/records/db"
 )
 
 type Service interface {
 	Create(context.Context, *CreateOptions) (*model.Record, error)
+
+	// GetOrCreate creates a record, or returns the one the user already owns with the same title.
+	// It reports whether the record was created, which makes it suitable for idempotent provisioning.
+	GetOrCreate(context.Context, *CreateOptions) (*model.Record, bool, error)
 	List(context.Context, *ListOptions) ([]*model.Record, error)
+	Count(context.Context, *CountOptions) (int64, error)
+
+	// CountDeleted counts the requester's soft-deleted records, e.g. for the badge of the trash.
+	CountDeleted(context.Context) (int64, error)
+
+	// Aggregate counts the records per group, so the clients don't have to fetch the rows to compute the counts.
+	Aggregate(context.Context, *AggregateOptions) ([]*model.Group, error)
 	Get(context.Context, uuid.UUID) (*model.Record, error)
+
+	// GetByIDs fetches the records with the supplied IDs, keyed by their ID, for hydrating the references to them.
+	// The records which don't exist, or which the requester doesn't own, are simply absent from the map.
+	GetByIDs(context.Context, []uuid.UUID) (map[uuid.UUID]*model.Record, error)
+
+	// IsOwner reports whether the requester owns the record, without fetching it.
+	// It's meant for the handlers which assert the ownership before an action outside the database.
+	// A missing record isn't an error; nobody owns it.
+	IsOwner(context.Context, uuid.UUID) (bool, error)
+
+	// GetIncludingDeleted fetches a record even if it's soft-deleted, so a restore flow can fetch it first.
+	GetIncludingDeleted(context.Context, uuid.UUID) (*model.Record, error)
 	Update(context.Context, uuid.UUID, *UpdateOptions) (*model.Record, error)
+
+	// UpdateResult updates a record like `Update`, and reports the number of the updated records along with it,
+	// so the handlers can report them uniformly.
+	UpdateResult(context.Context, uuid.UUID, *UpdateOptions) (*model.Result, error)
 	Delete(context.Context, uuid.UUID) error
+
+	// DeleteResult deletes a record like `Delete`, and reports the number of the deleted records.
+	DeleteResult(context.Context, uuid.UUID) (*model.Result, error)
+
+	// Touch marks a record as recently active by bumping its `updated_at` timestamp, without changing its content.
+	// It fails with `ErrNoRowsAffected` if the requester doesn't own the record or it doesn't exist.
+	Touch(context.Context, uuid.UUID) error
+
+	// Clone creates a copy of the record, owned by the requester and titled after the original with a " (copy)" suffix.
+	// The original is read with the Row Level Security (RLS) checks, so only the records the requester can read can be cloned.
+	Clone(context.Context, uuid.UUID) (*model.Record, error)
+
+	// Reassign moves the record to another user.
+	// It's reserved to the administrators and the service-role contexts, see `WithServiceRole`.
+	Reassign(ctx context.Context, ID uuid.UUID, userID uuid.UUID) (*model.Record, error)
+
+	// Import creates a record with the supplied timestamps instead of generated ones.
+	// It's meant for importing historical data, so the per-user quota doesn't apply.
+	Import(context.Context, *ImportOptions) (*model.Record, error)
+
+	// ListAuditLogs fetches the entries of the audit trail of the records, the most recent first.
+	// Every create, import, update and delete appends an entry in the same transaction as the mutation.
+	ListAuditLogs(context.Context, *ListAuditLogsOptions) ([]*model.AuditLog, error)
+
+	// Tx runs the supplied function with a transactional service.
+	// All the operations performed through the transactional service are committed if the function returns nil,
+	// and rolled back otherwise.
+	Tx(context.Context, func(Service) error) error
 }
 
 type Config struct {
@@ -25,8 +84,55 @@ type Config struct {
 
 	//	Logger.
 	Logger *slog.Logger
+
+	//	Circuit breaker guarding the database layer calls.
+	//	If set, the calls fast-fail with `ErrServiceUnavailable` while the database is unhealthy.
+	Breaker *breaker.Breaker
+
+	//	Maximum number of records a single user can own.
+	//	If it is zero, the number of records is unlimited.
+	MaxRecordsPerUser int64
+
+	//	Maximum number of records a list can skip.
+	//	Larger offsets are rejected with `ErrInvalidFilters`, because they cause slow offset scans.
+	//	Default: `DefaultMaxSkip`
+	MaxSkip int
+
+	//	Maximum number of records a list of a regular user can return.
+	//	Default: `DefaultMaxPageSize`
+	MaxPageSize int
+
+	//	Maximum number of records a list of an administrator, or of a service-role context, can return.
+	//	It can't exceed `db.MaxLimit`.
+	//	Default: `DefaultAdminMaxPageSize`
+	AdminMaxPageSize int
+
+	//	How the HTML markup in the titles is handled on create, import and update.
+	//	The titles containing control characters are always rejected.
+	//	Default: `TitleAllowHTML`
+	TitlePolicy TitlePolicy
+
+	//	Field the lists are ordered by when they don't ask for an order,
+	//	so their output is deterministic rather than in the natural order of the database.
+	//	Default: `OrderByCreatedAt`
+	DefaultOrderBy OrderBy
+
+	//	Direction of the default order.
+	//	Default: `OrderDirectionDesc`
+	DefaultOrderDirection OrderDirection
 }
 
+// DefaultMaxSkip is the maximum number of records a list can skip, unless configured otherwise.
+const DefaultMaxSkip = 10000
+
+const (
+	// DefaultMaxPageSize is the maximum number of records a list of a regular user can return, unless configured otherwise.
+	DefaultMaxPageSize = 100
+
+	// DefaultAdminMaxPageSize is the maximum number of records a list of an administrator can return, unless configured otherwise.
+	DefaultAdminMaxPageSize = db.MaxLimit
+)
+
 // Initializes and gets the service with the supplied database connection.
 func NewService(config *Config) Service {
 
@@ -35,8 +141,38 @@ func NewService(config *Config) Service {
 	}
 
 	svc := service{
-		db:     config.DB,
-		logger: config.Logger,
+		db:                config.DB,
+		logger:            config.Logger,
+		maxRecordsPerUser: config.MaxRecordsPerUser,
+		maxSkip:           config.MaxSkip,
+		maxPageSize:       config.MaxPageSize,
+		adminMaxPageSize:  config.AdminMaxPageSize,
+		titlePolicy:       config.TitlePolicy,
+	}
+
+	if svc.maxSkip <= 0 {
+		svc.maxSkip = DefaultMaxSkip
+	}
+
+	if svc.maxPageSize <= 0 {
+		svc.maxPageSize = DefaultMaxPageSize
+	}
+	if svc.adminMaxPageSize <= 0 {
+		svc.adminMaxPageSize = DefaultAdminMaxPageSize
+	}
+	if svc.maxPageSize > db.MaxLimit || svc.adminMaxPageSize > db.MaxLimit {
+		panic("service: max page size over db.MaxLimit")
+	}
+
+	svc.defaultOrderBy, svc.defaultOrderDirection = config.DefaultOrderBy, config.DefaultOrderDirection
+	if svc.defaultOrderBy == "" {
+		svc.defaultOrderBy = OrderByCreatedAt
+	}
+	if svc.defaultOrderDirection == "" {
+		svc.defaultOrderDirection = OrderDirectionDesc
+	}
+	if !svc.defaultOrderBy.valid() || !svc.defaultOrderDirection.valid() {
+		panic("service: invalid default order")
 	}
 
 	if svc.logger == nil {
@@ -45,6 +181,13 @@ func NewService(config *Config) Service {
 
 	svc.logger = svc.logger.With("layer", "service")
 
+	if config.Breaker != nil {
+		svc.db = &guarded{
+			DB:      svc.db,
+			breaker: config.Breaker,
+		}
+	}
+
 	return &svc
 }
 
@@ -55,6 +198,24 @@ type service struct {
 
 	//	Logger.
 	logger *slog.Logger
+
+	//	Maximum number of records a single user can own.
+	maxRecordsPerUser int64
+
+	//	Maximum number of records a list can skip.
+	//	If it is zero, the offset is unbounded.
+	maxSkip int
+
+	//	Maximum number of records a list can return, for the regular users and for the administrators.
+	maxPageSize      int
+	adminMaxPageSize int
+
+	//	How the HTML markup in the titles is handled.
+	titlePolicy TitlePolicy
+
+	//	Order of the lists which don't ask for one.
+	defaultOrderBy        OrderBy
+	defaultOrderDirection OrderDirection
 }
 
 func (s *service) Create(ctx context.Context, options *CreateOptions) (*model.Record, error) {
@@ -67,11 +228,98 @@ func (s *service) Create(ctx context.Context, options *CreateOptions) (*model.Re
 	if err := options.validate(); err != nil {
 		return nil, err
 	}
+	title, err := s.titlePolicy.title(options.Title)
+	if err != nil {
+		return nil, err
+	}
+
+	// Enforce the per-user quota.
+	// The check is best-effort: concurrent creates by the same user may overshoot it.
+	if s.maxRecordsPerUser > 0 {
+		count, err := s.db.Count(ctx, &db.CountOptions{
+			UserID: options.UserID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if count >= s.maxRecordsPerUser {
+			return nil, ErrQuotaExceeded
+		}
+	}
+
+	var record *model.Record
+	err = s.db.WithNestedTransaction(ctx, func(tx db.DB) (err error) {
+		record, err = tx.Create(ctx, &db.CreateOptions{
+			Title:       title,
+			Description: options.Description,
+			UserID:      options.UserID,
+		})
+		if err != nil {
+			return err
+		}
+		return s.audit(ctx, tx, model.AuditActionCreate, record.ID, map[string]any{
+			"title":       record.Title,
+			"description": record.Description,
+			"user_id":     record.UserID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+func (s *service) GetOrCreate(ctx context.Context, options *CreateOptions) (*model.Record, bool, error) {
+	s.logger.LogAttrs(ctx, slog.LevelDebug, "getting or creating a record",
+		slog.String("function", "get_or_create"),
+	)
+	if options == nil {
+		return nil, false, ErrInvalidOptions
+	}
+	if err := options.validate(); err != nil {
+		return nil, false, err
+	}
+	title, err := s.titlePolicy.title(options.Title)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var record *model.Record
+	var created bool
+	err = s.db.WithNestedTransaction(ctx, func(tx db.DB) (err error) {
+		record, created, err = tx.CreateIfNotExists(ctx, &db.CreateOptions{
+			Title:       title,
+			Description: options.Description,
+			UserID:      options.UserID,
+		})
+		if err != nil || !created {
+			return err
+		}
 
-	return s.db.Create(ctx, &db.CreateOptions{
-		Title:  options.Title,
-		UserID: options.UserID,
+		// Enforce the per-user quota only when a record was created.
+		// The insert is rolled back if it takes the user over the quota.
+		if s.maxRecordsPerUser > 0 {
+			count, err := tx.Count(ctx, &db.CountOptions{
+				UserID: options.UserID,
+			})
+			if err != nil {
+				return err
+			}
+			if count > s.maxRecordsPerUser {
+				return ErrQuotaExceeded
+			}
+		}
+
+		return s.audit(ctx, tx, model.AuditActionCreate, record.ID, map[string]any{
+			"title":       record.Title,
+			"description": record.Description,
+			"user_id":     record.UserID,
+		})
 	})
+	if err != nil {
+		return nil, false, err
+	}
+	return record, created, nil
 }
 
 func (s *service) List(ctx context.Context, options *ListOptions) ([]*model.Record, error) {
@@ -81,16 +329,67 @@ func (s *service) List(ctx context.Context, options *ListOptions) ([]*model.Reco
 	if options == nil {
 		return nil, ErrInvalidOptions
 	}
-	if err := options.validate(); err != nil {
+	if err := options.validate(s.maxSkip, s.maxLimit(ctx)); err != nil {
 		return nil, err
 	}
 
+	// Fall back to the default order, so the pages are stable.
+	orderBy, orderDirection := options.OrderBy, options.OrderDirection
+	if orderBy == "" {
+		orderBy, orderDirection = s.defaultOrderBy, s.defaultOrderDirection
+	}
+
 	return s.db.List(ctx, &db.ListOptions{
-		Title:          options.Title,
-		Skip:           options.Skip,
-		Limit:          options.Limit,
-		OrderBy:        options.OrderBy,
-		OrderDirection: options.OrderDirection,
+		Title:            options.Title,
+		Search:           options.Search,
+		OrderByRelevance: options.OrderByRelevance,
+		Skip:             options.Skip,
+		Limit:            options.Limit,
+		OrderBy:          string(orderBy),
+		OrderDirection:   string(orderDirection),
+		IncludeDeleted:   options.IncludeDeleted,
+		DeletedOnly:      options.DeletedOnly,
+	})
+}
+
+func (s *service) Count(ctx context.Context, options *CountOptions) (int64, error) {
+	s.logger.LogAttrs(ctx, slog.LevelDebug, "counting records",
+		slog.String("function", "count"),
+	)
+	if options == nil {
+		return 0, ErrInvalidOptions
+	}
+
+	return s.db.Count(ctx, &db.CountOptions{
+		Title: options.Title,
+	})
+}
+
+func (s *service) CountDeleted(ctx context.Context) (int64, error) {
+	s.logger.LogAttrs(ctx, slog.LevelDebug, "counting deleted records",
+		slog.String("function", "count_deleted"),
+	)
+	return s.db.CountDeleted(ctx)
+}
+
+func (s *service) Aggregate(ctx context.Context, options *AggregateOptions) ([]*model.Group, error) {
+	s.logger.LogAttrs(ctx, slog.LevelDebug, "aggregating records",
+		slog.String("function", "aggregate"),
+	)
+	if options == nil {
+		return nil, ErrInvalidOptions
+	}
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+
+	length := options.PrefixLength
+	if length == 0 {
+		length = 1
+	}
+	return s.db.Aggregate(ctx, &db.AggregateOptions{
+		GroupBy:      string(options.GroupBy),
+		PrefixLength: length,
 	})
 }
 
@@ -104,7 +403,57 @@ func (s *service) Get(ctx context.Context, ID uuid.UUID) (*model.Record, error)
 	return s.db.Get(ctx, ID)
 }
 
+func (s *service) GetByIDs(ctx context.Context, IDs []uuid.UUID) (map[uuid.UUID]*model.Record, error) {
+	s.logger.LogAttrs(ctx, slog.LevelDebug, "retrieving records by their IDs",
+		slog.String("function", "get_by_ids"),
+	)
+	if len(IDs) > s.maxLimit(ctx) {
+		return nil, ErrInvalidOptions
+	}
+	for _, ID := range IDs {
+		if ID == uuid.Nil {
+			return nil, ErrInvalidRecordID
+		}
+	}
+	return s.db.GetByIDs(ctx, IDs)
+}
+
+func (s *service) IsOwner(ctx context.Context, ID uuid.UUID) (bool, error) {
+	s.logger.LogAttrs(ctx, slog.LevelDebug, "checking the ownership of a record",
+		slog.String("function", "is_owner"),
+	)
+	if ID == uuid.Nil {
+		return false, ErrInvalidRecordID
+	}
+
+	// Without JWT claims, there's no requester to own the record.
+	if _, exists := middleware.JWTClaimsFromContext(ctx); !exists {
+		return false, nil
+	}
+
+	// The existence is checked with the Row Level Security (RLS) checks, so only the owner sees the record.
+	return s.db.Exists(ctx, ID)
+}
+
+func (s *service) GetIncludingDeleted(ctx context.Context, ID uuid.UUID) (*model.Record, error) {
+	s.logger.LogAttrs(ctx, slog.LevelDebug, "retrieving a record including the deleted ones",
+		slog.String("function", "get_including_deleted"),
+	)
+	if ID == uuid.Nil {
+		return nil, ErrInvalidOptions
+	}
+	return s.db.GetIncludingDeleted(ctx, ID)
+}
+
 func (s *service) Update(ctx context.Context, ID uuid.UUID, options *UpdateOptions) (*model.Record, error) {
+	result, err := s.UpdateResult(ctx, ID, options)
+	if err != nil {
+		return nil, err
+	}
+	return result.Record, nil
+}
+
+func (s *service) UpdateResult(ctx context.Context, ID uuid.UUID, options *UpdateOptions) (*model.Result, error) {
 	s.logger.LogAttrs(ctx, slog.LevelDebug, "updating a record",
 		slog.String("function", "update"),
 	)
@@ -117,17 +466,346 @@ func (s *service) Update(ctx context.Context, ID uuid.UUID, options *UpdateOptio
 	if err := options.validate(); err != nil {
 		return nil, err
 	}
-	return s.db.Update(ctx, ID, &db.UpdateOptions{
-		Title: options.Title,
+	title := options.Title
+	if title != "" {
+		var err error
+		if title, err = s.titlePolicy.title(title); err != nil {
+			return nil, err
+		}
+	}
+
+	var result *model.Result
+	err := s.db.WithNestedTransaction(ctx, func(tx db.DB) (err error) {
+		result, err = tx.UpdateResult(ctx, ID, &db.UpdateOptions{
+			Title:       title,
+			Description: options.Description,
+		})
+		if err != nil {
+			return err
+		}
+
+		// Only record the fields which were changed.
+		diff := make(map[string]any)
+		if title != "" {
+			diff["title"] = title
+		}
+		if options.Description != "" {
+			diff["description"] = options.Description
+		}
+		return s.audit(ctx, tx, model.AuditActionUpdate, ID, diff)
 	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
 func (s *service) Delete(ctx context.Context, ID uuid.UUID) error {
+	_, err := s.DeleteResult(ctx, ID)
+	return err
+}
+
+func (s *service) DeleteResult(ctx context.Context, ID uuid.UUID) (*model.Result, error) {
 	s.logger.LogAttrs(ctx, slog.LevelDebug, "deleting a record",
 		slog.String("function", "delete"),
 	)
+	if ID == uuid.Nil {
+		return nil, ErrInvalidRecordID
+	}
+
+	var result *model.Result
+	err := s.db.WithNestedTransaction(ctx, func(tx db.DB) (err error) {
+		result, err = tx.DeleteResult(ctx, ID)
+		if err != nil {
+			return err
+		}
+		return s.audit(ctx, tx, model.AuditActionDelete, ID, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *service) Touch(ctx context.Context, ID uuid.UUID) error {
+	s.logger.LogAttrs(ctx, slog.LevelDebug, "touching a record",
+		slog.String("function", "touch"),
+	)
 	if ID == uuid.Nil {
 		return ErrInvalidRecordID
 	}
-	return s.db.Delete(ctx, ID)
+	return s.db.Touch(ctx, ID)
+}
+
+func (s *service) Clone(ctx context.Context, ID uuid.UUID) (*model.Record, error) {
+	s.logger.LogAttrs(ctx, slog.LevelDebug, "cloning a record",
+		slog.String("function", "clone"),
+	)
+	if ID == uuid.Nil {
+		return nil, ErrInvalidRecordID
+	}
+
+	var record *model.Record
+	err := s.db.WithNestedTransaction(ctx, func(tx db.DB) error {
+		original, err := tx.Get(ctx, ID)
+		if err != nil {
+			return err
+		}
+
+		// The copy belongs to the requester.
+		owner := original.UserID
+		if claims, exists := middleware.JWTClaimsFromContext(ctx); exists {
+			owner = claims.XUserID
+		}
+
+		// Enforce the per-user quota.
+		if s.maxRecordsPerUser > 0 {
+			count, err := tx.Count(ctx, &db.CountOptions{
+				UserID: owner,
+			})
+			if err != nil {
+				return err
+			}
+			if count >= s.maxRecordsPerUser {
+				return ErrQuotaExceeded
+			}
+		}
+
+		// Number the copies, since the titles are unique per user.
+		title, err := s.copyTitle(ctx, tx, original.Title, owner)
+		if err != nil {
+			return err
+		}
+
+		record, err = tx.Create(ctx, &db.CreateOptions{
+			Title:       title,
+			Description: original.Description,
+			UserID:      owner,
+		})
+		if err != nil {
+			return err
+		}
+		return s.audit(ctx, tx, model.AuditActionCreate, record.ID, map[string]any{
+			"title":       record.Title,
+			"description": record.Description,
+			"user_id":     record.UserID,
+			"cloned_from": ID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// maxCopies is the number of copies of the same record a user can have, so numbering the copies can't loop forever.
+const maxCopies = 100
+
+// copyTitle returns the first title, among "<title> (copy)", "<title> (copy 2)" and so on, the user doesn't own yet.
+func (s *service) copyTitle(ctx context.Context, tx db.DB, title string, userID uuid.UUID) (string, error) {
+	for n := 1; n <= maxCopies; n++ {
+		candidate := title + " (copy)"
+		if n > 1 {
+			candidate = fmt.Sprintf("%s (copy %d)", title, n)
+		}
+		count, err := tx.Count(ctx, &db.CountOptions{
+			Title:  candidate,
+			UserID: userID,
+		})
+		if err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return candidate, nil
+		}
+	}
+	return "", ErrTooManyCopies
+}
+
+func (s *service) Reassign(ctx context.Context, ID uuid.UUID, userID uuid.UUID) (*model.Record, error) {
+	s.logger.LogAttrs(ctx, slog.LevelDebug, "reassigning a record",
+		slog.String("function", "reassign"),
+	)
+	if ID == uuid.Nil {
+		return nil, ErrInvalidRecordID
+	}
+	if userID == uuid.Nil {
+		return nil, ErrInvalidUserID
+	}
+	if !privileged(ctx) {
+		return nil, ErrPermissionDenied
+	}
+
+	var record *model.Record
+	err := s.db.WithNestedTransaction(ctx, func(tx db.DB) (err error) {
+		record, err = tx.Reassign(ctx, ID, userID)
+		if err != nil {
+			return err
+		}
+		return s.audit(ctx, tx, model.AuditActionReassign, ID, map[string]any{
+			"user_id": userID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+func (s *service) Import(ctx context.Context, options *ImportOptions) (*model.Record, error) {
+	s.logger.LogAttrs(ctx, slog.LevelDebug, "importing a record",
+		slog.String("function", "import"),
+	)
+	if options == nil {
+		return nil, ErrInvalidOptions
+	}
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+	title, err := s.titlePolicy.title(options.Title)
+	if err != nil {
+		return nil, err
+	}
+
+	var record *model.Record
+	err = s.db.WithNestedTransaction(ctx, func(tx db.DB) (err error) {
+		record, err = tx.Import(ctx, &db.ImportOptions{
+			Title:       title,
+			Description: options.Description,
+			UserID:      options.UserID,
+			CreatedAt:   options.CreatedAt,
+			UpdatedAt:   options.UpdatedAt,
+		})
+		if err != nil {
+			return err
+		}
+		return s.audit(ctx, tx, model.AuditActionImport, record.ID, map[string]any{
+			"title":       record.Title,
+			"description": record.Description,
+			"user_id":     record.UserID,
+			"created_at":  record.CreatedAt,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+func (s *service) ListAuditLogs(ctx context.Context, options *ListAuditLogsOptions) ([]*model.AuditLog, error) {
+	s.logger.LogAttrs(ctx, slog.LevelDebug, "listing the audit logs",
+		slog.String("function", "list_audit_logs"),
+	)
+	if options == nil {
+		return nil, ErrInvalidOptions
+	}
+	if err := options.validate(s.maxSkip); err != nil {
+		return nil, err
+	}
+
+	return s.db.ListAuditLogs(ctx, &db.ListAuditLogsOptions{
+		ActorID:  options.ActorID,
+		Action:   options.Action,
+		Entity:   auditEntity,
+		EntityID: options.EntityID,
+		Since:    options.Since,
+		Until:    options.Until,
+		Skip:     options.Skip,
+		Limit:    options.Limit,
+	})
+}
+
+// AdminScope is the JWT scope which grants the administrative operations, like `Reassign`.
+const AdminScope = "records:admin"
+
+// ctxKey is the type of the keys used to store values in the context.
+type ctxKey int
+
+// serviceRoleKey is the key of the service-role marker in the context.
+const serviceRoleKey ctxKey = iota
+
+// WithServiceRole marks the context as the one of a trusted caller which doesn't act on behalf of a user,
+// like a background job, so it's allowed to perform the administrative operations.
+//
+// It must only be called by the trusted code itself, never on the context of a request.
+func WithServiceRole(ctx context.Context) context.Context {
+	return context.WithValue(ctx, serviceRoleKey, true)
+}
+
+// privileged checks whether the context is allowed to perform the administrative operations.
+//
+// It requires a positive signal: either a service-role context, marked with `WithServiceRole`,
+// or the context of an administrator, whose JWT claims grant `AdminScope`.
+// A context without JWT claims isn't privileged by itself, so a request which skipped the authentication can't escalate.
+func privileged(ctx context.Context) bool {
+	if role, _ := ctx.Value(serviceRoleKey).(bool); role {
+		return true
+	}
+	claims, exists := middleware.JWTClaimsFromContext(ctx)
+	return exists && claims.HasScopes(AdminScope)
+}
+
+// maxLimit returns the maximum number of records a list can return for the role of the requester.
+//
+// The privileged contexts get the higher cap of the administrators.
+// The contexts without JWT claims get the cap of the regular users, unless they're marked with `WithServiceRole`.
+func (s *service) maxLimit(ctx context.Context) int {
+	if privileged(ctx) {
+		return s.adminMaxPageSize
+	}
+	return s.maxPageSize
+}
+
+// auditEntity is the kind of the entities recorded in the audit trail by this service.
+const auditEntity = "record"
+
+// audit appends an entry for the mutation of the record to the audit trail.
+//
+// It must be called with the transactional database layer of the mutation,
+// so the entry is rolled back along with it.
+func (s *service) audit(ctx context.Context, tx db.DB, action string, ID uuid.UUID, diff map[string]any) error {
+	options := db.CreateAuditLogOptions{
+		Action:   action,
+		Entity:   auditEntity,
+		EntityID: ID,
+	}
+
+	// Attribute the mutation to the user in the JWT claims, if any.
+	if claims, exists := middleware.JWTClaimsFromContext(ctx); exists {
+		options.ActorID = claims.XUserID
+	}
+
+	if len(diff) > 0 {
+		raw, err := json.Marshal(diff)
+		if err != nil {
+			return err
+		}
+		options.Diff = raw
+	}
+
+	_, err := tx.CreateAuditLog(ctx, &options)
+	return err
+}
+
+func (s *service) Tx(ctx context.Context, fn func(Service) error) error {
+	s.logger.LogAttrs(ctx, slog.LevelDebug, "running a transaction",
+		slog.String("function", "tx"),
+	)
+	if fn == nil {
+		return ErrInvalidOptions
+	}
+	return s.db.WithTransaction(ctx, func(tx db.DB) error {
+		return fn(&service{
+			db:                tx,
+			logger:            s.logger,
+			maxRecordsPerUser: s.maxRecordsPerUser,
+			maxSkip:           s.maxSkip,
+			maxPageSize:       s.maxPageSize,
+			adminMaxPageSize:  s.adminMaxPageSize,
+			titlePolicy:       s.titlePolicy,
+
+			defaultOrderBy:        s.defaultOrderBy,
+			defaultOrderDirection: s.defaultOrderDirection,
+		})
+	})
 }
@@ -41,6 +41,66 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
+// Aggregate mocks base method.
+func (m *MockService) Aggregate(arg0 context.Context, arg1 *AggregateOptions) ([]*model.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Aggregate", arg0, arg1)
+	ret0, _ := ret[0].([]*model.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Aggregate indicates an expected call of Aggregate.
+func (mr *MockServiceMockRecorder) Aggregate(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Aggregate", reflect.TypeOf((*MockService)(nil).Aggregate), arg0, arg1)
+}
+
+// Clone mocks base method.
+func (m *MockService) Clone(arg0 context.Context, arg1 uuid.UUID) (*model.Record, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Clone", arg0, arg1)
+	ret0, _ := ret[0].(*model.Record)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Clone indicates an expected call of Clone.
+func (mr *MockServiceMockRecorder) Clone(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clone", reflect.TypeOf((*MockService)(nil).Clone), arg0, arg1)
+}
+
+// Count mocks base method.
+func (m *MockService) Count(arg0 context.Context, arg1 *CountOptions) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockServiceMockRecorder) Count(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockService)(nil).Count), arg0, arg1)
+}
+
+// CountDeleted mocks base method.
+func (m *MockService) CountDeleted(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountDeleted", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountDeleted indicates an expected call of CountDeleted.
+func (mr *MockServiceMockRecorder) CountDeleted(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountDeleted", reflect.TypeOf((*MockService)(nil).CountDeleted), arg0)
+}
+
 // Create mocks base method.
 func (m *MockService) Create(arg0 context.Context, arg1 *CreateOptions) (*model.Record, error) {
 	m.ctrl.T.Helper()
@@ -70,6 +130,21 @@ func (mr *MockServiceMockRecorder) Delete(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockService)(nil).Delete), arg0, arg1)
 }
 
+// DeleteResult mocks base method.
+func (m *MockService) DeleteResult(arg0 context.Context, arg1 uuid.UUID) (*model.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteResult", arg0, arg1)
+	ret0, _ := ret[0].(*model.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteResult indicates an expected call of DeleteResult.
+func (mr *MockServiceMockRecorder) DeleteResult(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteResult", reflect.TypeOf((*MockService)(nil).DeleteResult), arg0, arg1)
+}
+
 // Get mocks base method.
 func (m *MockService) Get(arg0 context.Context, arg1 uuid.UUID) (*model.Record, error) {
 	m.ctrl.T.Helper()
@@ -85,6 +160,82 @@ func (mr *MockServiceMockRecorder) Get(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockService)(nil).Get), arg0, arg1)
 }
 
+// GetByIDs mocks base method.
+func (m *MockService) GetByIDs(arg0 context.Context, arg1 []uuid.UUID) (map[uuid.UUID]*model.Record, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIDs", arg0, arg1)
+	ret0, _ := ret[0].(map[uuid.UUID]*model.Record)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIDs indicates an expected call of GetByIDs.
+func (mr *MockServiceMockRecorder) GetByIDs(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDs", reflect.TypeOf((*MockService)(nil).GetByIDs), arg0, arg1)
+}
+
+// GetIncludingDeleted mocks base method.
+func (m *MockService) GetIncludingDeleted(arg0 context.Context, arg1 uuid.UUID) (*model.Record, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncludingDeleted", arg0, arg1)
+	ret0, _ := ret[0].(*model.Record)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncludingDeleted indicates an expected call of GetIncludingDeleted.
+func (mr *MockServiceMockRecorder) GetIncludingDeleted(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncludingDeleted", reflect.TypeOf((*MockService)(nil).GetIncludingDeleted), arg0, arg1)
+}
+
+// GetOrCreate mocks base method.
+func (m *MockService) GetOrCreate(arg0 context.Context, arg1 *CreateOptions) (*model.Record, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrCreate", arg0, arg1)
+	ret0, _ := ret[0].(*model.Record)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetOrCreate indicates an expected call of GetOrCreate.
+func (mr *MockServiceMockRecorder) GetOrCreate(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrCreate", reflect.TypeOf((*MockService)(nil).GetOrCreate), arg0, arg1)
+}
+
+// Import mocks base method.
+func (m *MockService) Import(arg0 context.Context, arg1 *ImportOptions) (*model.Record, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", arg0, arg1)
+	ret0, _ := ret[0].(*model.Record)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockServiceMockRecorder) Import(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockService)(nil).Import), arg0, arg1)
+}
+
+// IsOwner mocks base method.
+func (m *MockService) IsOwner(arg0 context.Context, arg1 uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsOwner", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsOwner indicates an expected call of IsOwner.
+func (mr *MockServiceMockRecorder) IsOwner(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsOwner", reflect.TypeOf((*MockService)(nil).IsOwner), arg0, arg1)
+}
+
 // List mocks base method.
 func (m *MockService) List(arg0 context.Context, arg1 *ListOptions) ([]*model.Record, error) {
 	m.ctrl.T.Helper()
@@ -100,6 +251,64 @@ func (mr *MockServiceMockRecorder) List(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockService)(nil).List), arg0, arg1)
 }
 
+// ListAuditLogs mocks base method.
+func (m *MockService) ListAuditLogs(arg0 context.Context, arg1 *ListAuditLogsOptions) ([]*model.AuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditLogs", arg0, arg1)
+	ret0, _ := ret[0].([]*model.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditLogs indicates an expected call of ListAuditLogs.
+func (mr *MockServiceMockRecorder) ListAuditLogs(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditLogs", reflect.TypeOf((*MockService)(nil).ListAuditLogs), arg0, arg1)
+}
+
+// Reassign mocks base method.
+func (m *MockService) Reassign(ctx context.Context, ID, userID uuid.UUID) (*model.Record, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reassign", ctx, ID, userID)
+	ret0, _ := ret[0].(*model.Record)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reassign indicates an expected call of Reassign.
+func (mr *MockServiceMockRecorder) Reassign(ctx, ID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reassign", reflect.TypeOf((*MockService)(nil).Reassign), ctx, ID, userID)
+}
+
+// Touch mocks base method.
+func (m *MockService) Touch(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Touch", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Touch indicates an expected call of Touch.
+func (mr *MockServiceMockRecorder) Touch(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Touch", reflect.TypeOf((*MockService)(nil).Touch), arg0, arg1)
+}
+
+// Tx mocks base method.
+func (m *MockService) Tx(arg0 context.Context, arg1 func(Service) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Tx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Tx indicates an expected call of Tx.
+func (mr *MockServiceMockRecorder) Tx(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tx", reflect.TypeOf((*MockService)(nil).Tx), arg0, arg1)
+}
+
 // Update mocks base method.
 func (m *MockService) Update(arg0 context.Context, arg1 uuid.UUID, arg2 *UpdateOptions) (*model.Record, error) {
 	m.ctrl.T.Helper()
@@ -114,3 +323,18 @@ func (mr *MockServiceMockRecorder) Update(arg0, arg1, arg2 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockService)(nil).Update), arg0, arg1, arg2)
 }
+
+// UpdateResult mocks base method.
+func (m *MockService) UpdateResult(arg0 context.Context, arg1 uuid.UUID, arg2 *UpdateOptions) (*model.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateResult", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateResult indicates an expected call of UpdateResult.
+func (mr *MockServiceMockRecorder) UpdateResult(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateResult", reflect.TypeOf((*MockService)(nil).UpdateResult), arg0, arg1, arg2)
+}
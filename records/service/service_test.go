@@ -1,14 +1,27 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/mrinalwahal/boilerplate/model"
+	"github.com/mrinalwahal/boilerplate/pkg/db/breaker"
+	"github.com/mrinalwahal/boilerplate/pkg/logger"
+	"github.com/mrinalwahal/boilerplate/pkg/middleware"
 	"github.com/mrinalwahal/boilerplate/records/db"
 	"go.uber.org/mock/gomock"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
 )
 
 // Contains all the configuration required by our tests.
@@ -26,12 +39,26 @@ func configure(t *testing.T) *testconfig {
 
 	// Get the mock database layer.
 	db := db.NewMockDB(gomock.NewController(t))
+
+	// Let the mutations run their transactions against the mock database layer
+	// and append their audit entries.
+	expectAudit(db)
+
 	return &testconfig{
 		db:  db,
 		log: slog.Default(),
 	}
 }
 
+// expectAudit sets up the mock database layer to run the transactions of the mutations with itself
+// and accept their audit entries.
+func expectAudit(mock *db.MockDB) {
+	mock.EXPECT().WithNestedTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(db.DB) error) error {
+		return fn(mock)
+	}).AnyTimes()
+	mock.EXPECT().CreateAuditLog(gomock.Any(), gomock.Any()).Return(&model.AuditLog{}, nil).AnyTimes()
+}
+
 func Test_NewService(t *testing.T) {
 
 	t.Run("nil config", func(t *testing.T) {
@@ -76,6 +103,21 @@ func Test_NewService(t *testing.T) {
 			t.Errorf("NewService() = %v, want a valid service", s)
 		}
 	})
+
+	t.Run("max page size over the limit of the database layer", func(t *testing.T) {
+
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("NewService() did not panic")
+			}
+		}()
+
+		// Initialize the service.
+		NewService(&Config{
+			DB:               db.NewMockDB(gomock.NewController(t)),
+			AdminMaxPageSize: db.MaxLimit + 1,
+		})
+	})
 }
 
 func Test_Service_Create(t *testing.T) {
@@ -150,8 +192,10 @@ func Test_Service_List(t *testing.T) {
 
 	// Initialize the service.
 	s := &service{
-		db:     config.db,
-		logger: config.log,
+		db:               config.db,
+		logger:           config.log,
+		maxPageSize:      DefaultMaxPageSize,
+		adminMaxPageSize: DefaultAdminMaxPageSize,
 	}
 
 	t.Run("list records with nil options", func(t *testing.T) {
@@ -179,6 +223,157 @@ func Test_Service_List(t *testing.T) {
 		}
 	})
 
+	t.Run("list records by relevance w/o a search term", func(t *testing.T) {
+
+		// Make sure the database layer is not expecting a call.
+		config.db.EXPECT().List(gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := s.List(context.Background(), &ListOptions{
+			OrderByRelevance: true,
+		})
+		if err != ErrInvalidFilters {
+			t.Errorf("service.List() error = %v, want %v", err, ErrInvalidFilters)
+		}
+	})
+
+	t.Run("list records by relevance", func(t *testing.T) {
+
+		// Initialize the service with the default order.
+		s := NewService(&Config{
+			DB:     config.db,
+			Logger: config.log,
+		})
+
+		// The default order breaks the ties between the equally relevant records.
+		config.db.EXPECT().List(gomock.Any(), &db.ListOptions{
+			Search:           "milk",
+			OrderByRelevance: true,
+			OrderBy:          string(OrderByCreatedAt),
+			OrderDirection:   string(OrderDirectionDesc),
+		}).Return([]*model.Record{}, nil).Times(1)
+
+		if _, err := s.List(context.Background(), &ListOptions{
+			Search:           "milk",
+			OrderByRelevance: true,
+		}); err != nil {
+			t.Errorf("service.List() error = %v, wantErr %v", err, false)
+		}
+	})
+
+	t.Run("list records skipping up to the max", func(t *testing.T) {
+
+		// Initialize the service with the default maximum skip.
+		s := NewService(&Config{
+			DB:     config.db,
+			Logger: config.log,
+		})
+
+		config.db.EXPECT().List(gomock.Any(), &db.ListOptions{
+			Skip:           DefaultMaxSkip,
+			OrderBy:        string(OrderByCreatedAt),
+			OrderDirection: string(OrderDirectionDesc),
+		}).Return([]*model.Record{}, nil).Times(1)
+
+		if _, err := s.List(context.Background(), &ListOptions{
+			Skip: DefaultMaxSkip,
+		}); err != nil {
+			t.Errorf("service.List() error = %v, wantErr %v", err, false)
+		}
+	})
+
+	t.Run("list records w/ configured default order", func(t *testing.T) {
+
+		// Initialize the service with a default order.
+		s := NewService(&Config{
+			DB:                    config.db,
+			Logger:                config.log,
+			DefaultOrderBy:        OrderByTitle,
+			DefaultOrderDirection: OrderDirectionAsc,
+		})
+
+		config.db.EXPECT().List(gomock.Any(), &db.ListOptions{
+			OrderBy:        string(OrderByTitle),
+			OrderDirection: string(OrderDirectionAsc),
+		}).Return([]*model.Record{}, nil).Times(1)
+
+		if _, err := s.List(context.Background(), &ListOptions{}); err != nil {
+			t.Errorf("service.List() error = %v, wantErr %v", err, false)
+		}
+	})
+
+	t.Run("list records w/ explicit order over the default one", func(t *testing.T) {
+
+		config.db.EXPECT().List(gomock.Any(), &db.ListOptions{
+			OrderBy: string(OrderByUpdatedAt),
+		}).Return([]*model.Record{}, nil).Times(1)
+
+		if _, err := s.List(context.Background(), &ListOptions{
+			OrderBy: OrderByUpdatedAt,
+		}); err != nil {
+			t.Errorf("service.List() error = %v, wantErr %v", err, false)
+		}
+	})
+
+	t.Run("list records skipping over the max", func(t *testing.T) {
+
+		// Initialize the service with a low maximum skip.
+		s := NewService(&Config{
+			DB:      config.db,
+			Logger:  config.log,
+			MaxSkip: 10,
+		})
+
+		// Make sure the database layer is not expecting a call.
+		config.db.EXPECT().List(gomock.Any(), gomock.Any()).Times(0)
+
+		if _, err := s.List(context.Background(), &ListOptions{
+			Skip: 11,
+		}); err != ErrInvalidFilters {
+			t.Errorf("service.List() error = %v, wantErr %v", err, ErrInvalidFilters)
+		}
+	})
+
+	t.Run("list records with invalid order by field", func(t *testing.T) {
+
+		// Make sure the database layer is not expecting a call.
+		config.db.EXPECT().List(gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := s.List(context.Background(), &ListOptions{
+			OrderBy:        "user_id; DROP TABLE records",
+			OrderDirection: OrderDirectionAsc,
+		})
+		if err != ErrInvalidOrderBy {
+			t.Errorf("service.List() error = %v, wantErr %v", err, ErrInvalidOrderBy)
+		}
+	})
+
+	t.Run("list records with invalid order direction", func(t *testing.T) {
+
+		// Make sure the database layer is not expecting a call.
+		config.db.EXPECT().List(gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := s.List(context.Background(), &ListOptions{
+			OrderBy:        OrderByTitle,
+			OrderDirection: "sideways",
+		})
+		if err != ErrInvalidOrderDirection {
+			t.Errorf("service.List() error = %v, wantErr %v", err, ErrInvalidOrderDirection)
+		}
+	})
+
+	t.Run("list records with order direction w/o order by field", func(t *testing.T) {
+
+		// Make sure the database layer is not expecting a call.
+		config.db.EXPECT().List(gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := s.List(context.Background(), &ListOptions{
+			OrderDirection: OrderDirectionDesc,
+		})
+		if err != ErrInvalidOrderDirection {
+			t.Errorf("service.List() error = %v, wantErr %v", err, ErrInvalidOrderDirection)
+		}
+	})
+
 	t.Run("list records with valid options", func(t *testing.T) {
 
 		records := []*model.Record{
@@ -204,6 +399,205 @@ func Test_Service_List(t *testing.T) {
 			t.Errorf("service.List() = %v, want %v", len(got), len(records))
 		}
 	})
+
+	// Contexts of a regular user and of an administrator.
+	user := middleware.WithJWTClaims(context.Background(), middleware.JWTClaims{
+		XUserID: uuid.New(),
+	})
+	admin := middleware.WithJWTClaims(context.Background(), middleware.JWTClaims{
+		XUserID: uuid.New(),
+		Scope:   middleware.Scopes{AdminScope},
+	})
+
+	t.Run("list a page over the cap of the regular users", func(t *testing.T) {
+
+		// Make sure the database layer is not expecting a call.
+		config.db.EXPECT().List(gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := s.List(user, &ListOptions{
+			Limit: DefaultMaxPageSize + 1,
+		})
+		if err != ErrInvalidFilters {
+			t.Errorf("service.List() error = %v, wantErr %v", err, ErrInvalidFilters)
+		}
+	})
+
+	t.Run("list a page over the cap of the regular users w/o claims", func(t *testing.T) {
+
+		// Make sure the database layer is not expecting a call.
+		config.db.EXPECT().List(gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := s.List(context.Background(), &ListOptions{
+			Limit: DefaultMaxPageSize + 1,
+		})
+		if err != ErrInvalidFilters {
+			t.Errorf("service.List() error = %v, wantErr %v", err, ErrInvalidFilters)
+		}
+	})
+
+	t.Run("list a page over the cap of the regular users w/ a service-role context", func(t *testing.T) {
+
+		// Set the expectation at the database layer.
+		config.db.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+
+		if _, err := s.List(WithServiceRole(context.Background()), &ListOptions{
+			Limit: DefaultAdminMaxPageSize,
+		}); err != nil {
+			t.Errorf("service.List() error = %v, wantErr %v", err, false)
+		}
+	})
+
+	t.Run("list a page over the cap of the regular users as an administrator", func(t *testing.T) {
+
+		// Set the expectation at the database layer.
+		config.db.EXPECT().List(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, options *db.ListOptions) ([]*model.Record, error) {
+			if options.Limit != DefaultAdminMaxPageSize {
+				t.Errorf("expected the limit %d, got %d", DefaultAdminMaxPageSize, options.Limit)
+			}
+			return nil, nil
+		}).Times(1)
+
+		if _, err := s.List(admin, &ListOptions{
+			Limit: DefaultAdminMaxPageSize,
+		}); err != nil {
+			t.Errorf("service.List() error = %v, wantErr %v", err, false)
+		}
+	})
+
+	t.Run("list a page over the cap of the administrators", func(t *testing.T) {
+
+		// Make sure the database layer is not expecting a call.
+		config.db.EXPECT().List(gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := s.List(admin, &ListOptions{
+			Limit: DefaultAdminMaxPageSize + 1,
+		})
+		if err != ErrInvalidFilters {
+			t.Errorf("service.List() error = %v, wantErr %v", err, ErrInvalidFilters)
+		}
+	})
+}
+
+func Test_Service_Count(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	// Initialize the service.
+	s := &service{
+		db:     config.db,
+		logger: config.log,
+	}
+
+	t.Run("count records with nil options", func(t *testing.T) {
+
+		// Make sure the database layer is not expecting a call.
+		config.db.EXPECT().Count(gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := s.Count(context.Background(), nil)
+		if err == nil || err != ErrInvalidOptions {
+			t.Errorf("service.Count() error = %v, wantErr %v", err, true)
+		}
+	})
+
+	t.Run("count records with a title", func(t *testing.T) {
+
+		// Set the expectation at the database layer.
+		config.db.EXPECT().Count(gomock.Any(), &db.CountOptions{
+			Title: "Test Record",
+		}).Return(int64(2), nil).Times(1)
+
+		got, err := s.Count(context.Background(), &CountOptions{
+			Title: "Test Record",
+		})
+		if err != nil {
+			t.Errorf("service.Count() error = %v, wantErr %v", err, false)
+		}
+		if got != 2 {
+			t.Errorf("service.Count() = %v, want %v", got, 2)
+		}
+	})
+}
+
+func Test_Service_CountDeleted(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	// Initialize the service.
+	s := &service{
+		db:     config.db,
+		logger: config.log,
+	}
+
+	// Set the expectation at the database layer.
+	config.db.EXPECT().CountDeleted(gomock.Any()).Return(int64(3), nil).Times(1)
+
+	got, err := s.CountDeleted(context.Background())
+	if err != nil {
+		t.Errorf("service.CountDeleted() error = %v, wantErr %v", err, false)
+	}
+	if got != 3 {
+		t.Errorf("service.CountDeleted() = %v, want %v", got, 3)
+	}
+}
+
+func Test_Service_Aggregate(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	// Initialize the service.
+	s := &service{
+		db:     config.db,
+		logger: config.log,
+	}
+
+	t.Run("aggregate records w/ unknown grouping", func(t *testing.T) {
+
+		// Make sure the database layer is not expecting a call.
+		config.db.EXPECT().Aggregate(gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := s.Aggregate(context.Background(), &AggregateOptions{
+			GroupBy: "description",
+		})
+		if err != ErrInvalidGroupBy {
+			t.Errorf("service.Aggregate() error = %v, wantErr %v", err, ErrInvalidGroupBy)
+		}
+	})
+
+	t.Run("aggregate records w/ too long prefix", func(t *testing.T) {
+
+		// Make sure the database layer is not expecting a call.
+		config.db.EXPECT().Aggregate(gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := s.Aggregate(context.Background(), &AggregateOptions{
+			GroupBy:      GroupByTitlePrefix,
+			PrefixLength: MaxPrefixLength + 1,
+		})
+		if err != ErrInvalidFilters {
+			t.Errorf("service.Aggregate() error = %v, wantErr %v", err, ErrInvalidFilters)
+		}
+	})
+
+	t.Run("aggregate records per title prefix w/ default length", func(t *testing.T) {
+
+		// Set the expectation at the database layer.
+		config.db.EXPECT().Aggregate(gomock.Any(), &db.AggregateOptions{
+			GroupBy:      db.GroupByTitlePrefix,
+			PrefixLength: 1,
+		}).Return([]*model.Group{{Key: "a", Count: 2}}, nil).Times(1)
+
+		groups, err := s.Aggregate(context.Background(), &AggregateOptions{
+			GroupBy: GroupByTitlePrefix,
+		})
+		if err != nil {
+			t.Errorf("service.Aggregate() error = %v, wantErr %v", err, false)
+		}
+		if len(groups) != 1 || groups[0].Count != 2 {
+			t.Errorf("service.Aggregate() = %v, want 1 group of 2 records", groups)
+		}
+	})
 }
 
 func Test_Service_Get(t *testing.T) {
@@ -256,6 +650,102 @@ func Test_Service_Get(t *testing.T) {
 	})
 }
 
+func Test_Service_IsOwner(t *testing.T) {
+
+	// Open an in-memory database connection with SQLite.
+	conn, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open the database connection: %v", err)
+	}
+
+	// Migrate the schema.
+	if err := conn.AutoMigrate(&model.Record{}, &model.AuditLog{}); err != nil {
+		t.Fatalf("failed to migrate the schema: %v", err)
+	}
+
+	// Cleanup the environment after the test is complete.
+	t.Cleanup(func() {
+		sqlDB, err := conn.DB()
+		if err != nil {
+			t.Fatalf("failed to get the database connection: %v", err)
+		}
+		if err := sqlDB.Close(); err != nil {
+			t.Fatalf("failed to close the database connection: %v", err)
+		}
+	})
+
+	// Initialize the service.
+	s := NewService(&Config{
+		DB: db.NewSQLDB(&db.SQLDBConfig{
+			DB: conn,
+		}),
+	})
+
+	userID := uuid.New()
+	owner := middleware.WithJWTClaims(context.Background(), middleware.JWTClaims{
+		XUserID: userID,
+	})
+	record, err := s.Create(owner, &CreateOptions{
+		Title:  "Test Record",
+		UserID: userID,
+	})
+	if err != nil {
+		t.Fatalf("failed to seed the database: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		ID      uuid.UUID
+		want    bool
+		wantErr error
+	}{
+		{
+			name:    "check ownership with invalid ID",
+			ctx:     owner,
+			ID:      uuid.Nil,
+			wantErr: ErrInvalidRecordID,
+		},
+		{
+			name: "check ownership as the owner",
+			ctx:  owner,
+			ID:   record.ID,
+			want: true,
+		},
+		{
+			name: "check ownership as another user",
+			ctx: middleware.WithJWTClaims(context.Background(), middleware.JWTClaims{
+				XUserID: uuid.New(),
+			}),
+			ID:   record.ID,
+			want: false,
+		},
+		{
+			name: "check ownership without JWT claims",
+			ctx:  context.Background(),
+			ID:   record.ID,
+			want: false,
+		},
+		{
+			name: "check ownership of a nonexistent record",
+			ctx:  owner,
+			ID:   uuid.New(),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.IsOwner(tt.ctx, tt.ID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("service.IsOwner() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("service.IsOwner() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_Service_Update(t *testing.T) {
 
 	// Setup the test config.
@@ -273,7 +763,7 @@ func Test_Service_Update(t *testing.T) {
 	t.Run("update record with invalid ID", func(t *testing.T) {
 
 		// Make sure the database layer is not expecting a call.
-		config.db.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		config.db.EXPECT().UpdateResult(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 
 		_, err := s.Update(context.Background(), uuid.Nil, &UpdateOptions{
 			Title: "Test Record",
@@ -286,7 +776,7 @@ func Test_Service_Update(t *testing.T) {
 	t.Run("update record with nil options", func(t *testing.T) {
 
 		// Make sure the database layer is not expecting a call.
-		config.db.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		config.db.EXPECT().UpdateResult(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 
 		_, err := s.Update(context.Background(), id, nil)
 		if err == nil || err != ErrInvalidOptions {
@@ -297,7 +787,7 @@ func Test_Service_Update(t *testing.T) {
 	t.Run("update record with invalid options", func(t *testing.T) {
 
 		// Make sure the database layer is not expecting a call.
-		config.db.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		config.db.EXPECT().UpdateResult(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 
 		_, err := s.Update(context.Background(), id, &UpdateOptions{
 			Title: "",
@@ -307,6 +797,30 @@ func Test_Service_Update(t *testing.T) {
 		}
 	})
 
+	t.Run("update record with no fields to update", func(t *testing.T) {
+
+		// Make sure the database layer is not expecting a call.
+		config.db.EXPECT().UpdateResult(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := s.Update(context.Background(), id, &UpdateOptions{})
+		if err != ErrNoFieldsToUpdate {
+			t.Errorf("service.Update() error = %v, wantErr %v", err, ErrNoFieldsToUpdate)
+		}
+	})
+
+	t.Run("update record with blank title", func(t *testing.T) {
+
+		// Make sure the database layer is not expecting a call.
+		config.db.EXPECT().UpdateResult(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := s.Update(context.Background(), id, &UpdateOptions{
+			Title: "   ",
+		})
+		if err != ErrInvalidTitle {
+			t.Errorf("service.Update() error = %v, wantErr %v", err, ErrInvalidTitle)
+		}
+	})
+
 	t.Run("update record with valid options", func(t *testing.T) {
 
 		record := model.Record{
@@ -317,7 +831,7 @@ func Test_Service_Update(t *testing.T) {
 		}
 
 		// Set the expectation at the database layer.
-		config.db.EXPECT().Update(gomock.Any(), id, gomock.Any()).Return(&record, nil).Times(1)
+		config.db.EXPECT().UpdateResult(gomock.Any(), id, gomock.Any()).Return(&model.Result{Record: &record, RowsAffected: 1}, nil).Times(1)
 
 		got, err := s.Update(context.Background(), id, &UpdateOptions{
 			Title: "Updated Record",
@@ -351,7 +865,7 @@ func Test_Service_Delete(t *testing.T) {
 	t.Run("delete record with invalid ID", func(t *testing.T) {
 
 		// Make sure the database layer is not expecting a call.
-		config.db.EXPECT().Delete(gomock.Any(), gomock.Any()).Times(0)
+		config.db.EXPECT().DeleteResult(gomock.Any(), gomock.Any()).Times(0)
 
 		err := s.Delete(context.Background(), uuid.Nil)
 		if err == nil || err != ErrInvalidRecordID {
@@ -362,7 +876,7 @@ func Test_Service_Delete(t *testing.T) {
 	t.Run("delete record with valid ID", func(t *testing.T) {
 
 		// Set the expectation at the database layer.
-		config.db.EXPECT().Delete(gomock.Any(), id).Return(nil).Times(1)
+		config.db.EXPECT().DeleteResult(gomock.Any(), id).Return(&model.Result{RowsAffected: 1}, nil).Times(1)
 
 		err := s.Delete(context.Background(), id)
 		if err != nil {
@@ -370,3 +884,1103 @@ func Test_Service_Delete(t *testing.T) {
 		}
 	})
 }
+
+func Test_Service_GetByIDs(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	// Initialize the service.
+	s := &service{
+		db:               config.db,
+		logger:           config.log,
+		maxPageSize:      DefaultMaxPageSize,
+		adminMaxPageSize: DefaultAdminMaxPageSize,
+	}
+
+	t.Run("get records w/ nil ID", func(t *testing.T) {
+
+		// Make sure the database layer is not expecting a call.
+		config.db.EXPECT().GetByIDs(gomock.Any(), gomock.Any()).Times(0)
+
+		if _, err := s.GetByIDs(context.Background(), []uuid.UUID{uuid.New(), uuid.Nil}); err != ErrInvalidRecordID {
+			t.Errorf("service.GetByIDs() error = %v, want %v", err, ErrInvalidRecordID)
+		}
+	})
+
+	t.Run("get more records than a page", func(t *testing.T) {
+
+		// Make sure the database layer is not expecting a call.
+		config.db.EXPECT().GetByIDs(gomock.Any(), gomock.Any()).Times(0)
+
+		ctx := middleware.WithJWTClaims(context.Background(), middleware.JWTClaims{XUserID: uuid.New()})
+		IDs := make([]uuid.UUID, DefaultMaxPageSize+1)
+		for i := range IDs {
+			IDs[i] = uuid.New()
+		}
+		if _, err := s.GetByIDs(ctx, IDs); err != ErrInvalidOptions {
+			t.Errorf("service.GetByIDs() error = %v, want %v", err, ErrInvalidOptions)
+		}
+	})
+
+	t.Run("get present and absent records", func(t *testing.T) {
+
+		present, absent := uuid.New(), uuid.New()
+
+		// Set the expectation at the database layer.
+		config.db.EXPECT().GetByIDs(gomock.Any(), []uuid.UUID{present, absent}).Return(map[uuid.UUID]*model.Record{
+			present: {Base: model.Base{ID: present}},
+		}, nil).Times(1)
+
+		got, err := s.GetByIDs(context.Background(), []uuid.UUID{present, absent})
+		if err != nil {
+			t.Fatalf("service.GetByIDs() error = %v", err)
+		}
+		if _, exists := got[present]; !exists {
+			t.Errorf("service.GetByIDs() is missing record %v", present)
+		}
+		if _, exists := got[absent]; exists {
+			t.Errorf("service.GetByIDs() has absent record %v", absent)
+		}
+	})
+}
+
+func Test_Service_Touch(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	// Initialize the service.
+	s := &service{
+		db:     config.db,
+		logger: config.log,
+	}
+
+	t.Run("touch record with invalid ID", func(t *testing.T) {
+
+		// Make sure the database layer is not expecting a call.
+		config.db.EXPECT().Touch(gomock.Any(), gomock.Any()).Times(0)
+
+		if err := s.Touch(context.Background(), uuid.Nil); err != ErrInvalidRecordID {
+			t.Errorf("service.Touch() error = %v, want %v", err, ErrInvalidRecordID)
+		}
+	})
+
+	t.Run("touch nonexistent record", func(t *testing.T) {
+
+		id := uuid.New()
+
+		// Set the expectation at the database layer.
+		config.db.EXPECT().Touch(gomock.Any(), id).Return(db.ErrNoRowsAffected).Times(1)
+
+		if err := s.Touch(context.Background(), id); !errors.Is(err, ErrNoRowsAffected) {
+			t.Errorf("service.Touch() error = %v, want %v", err, ErrNoRowsAffected)
+		}
+	})
+}
+
+func Test_Service_Result(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	// Initialize the service.
+	s := &service{
+		db:     config.db,
+		logger: config.log,
+	}
+
+	// Sample record UUID.
+	id := uuid.New()
+
+	t.Run("update record and report the updated count", func(t *testing.T) {
+
+		// Set the expectation at the database layer.
+		config.db.EXPECT().UpdateResult(gomock.Any(), id, gomock.Any()).Return(&model.Result{
+			Record:       &model.Record{Base: model.Base{ID: id}, Title: "Updated Record"},
+			RowsAffected: 1,
+		}, nil).Times(1)
+
+		got, err := s.UpdateResult(context.Background(), id, &UpdateOptions{
+			Title: "Updated Record",
+		})
+		if err != nil {
+			t.Fatalf("service.UpdateResult() error = %v, wantErr %v", err, false)
+		}
+		if got.RowsAffected != 1 || got.Record.ID != id {
+			t.Errorf("service.UpdateResult() = %+v, want 1 updated record %v", got, id)
+		}
+	})
+
+	t.Run("delete record and report the deleted count", func(t *testing.T) {
+
+		// Set the expectation at the database layer.
+		config.db.EXPECT().DeleteResult(gomock.Any(), id).Return(&model.Result{
+			RowsAffected: 1,
+		}, nil).Times(1)
+
+		got, err := s.DeleteResult(context.Background(), id)
+		if err != nil {
+			t.Fatalf("service.DeleteResult() error = %v, wantErr %v", err, false)
+		}
+		if got.RowsAffected != 1 || got.Record != nil {
+			t.Errorf("service.DeleteResult() = %+v, want 1 deleted record", got)
+		}
+	})
+
+	t.Run("delete nonexistent record", func(t *testing.T) {
+
+		// Set the expectation at the database layer.
+		config.db.EXPECT().DeleteResult(gomock.Any(), id).Return(nil, db.ErrNoRowsAffected).Times(1)
+
+		got, err := s.DeleteResult(context.Background(), id)
+		if !errors.Is(err, db.ErrNoRowsAffected) {
+			t.Errorf("service.DeleteResult() error = %v, want %v", err, db.ErrNoRowsAffected)
+		}
+		if got != nil {
+			t.Errorf("service.DeleteResult() = %+v, want nil", got)
+		}
+	})
+}
+
+func Test_Service_Breaker(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	// Initialize the service with a breaker that opens after a single failure.
+	s := NewService(&Config{
+		DB:     config.db,
+		Logger: config.log,
+		Breaker: breaker.New(&breaker.Config{
+			Threshold: 1,
+			Cooldown:  time.Minute,
+		}),
+	})
+
+	// Sample record UUID.
+	id := uuid.New()
+
+	// Sample infrastructure failure.
+	errConnection := &net.OpError{Op: "dial", Net: "tcp", Err: fmt.Errorf("connection refused")}
+
+	t.Run("get missing records repeatedly", func(t *testing.T) {
+
+		// The requests fail on their own, so the breaker must keep letting them through.
+		config.db.EXPECT().Get(gomock.Any(), id).Return(nil, db.ErrNoRowsAffected).Times(3)
+
+		for i := 0; i < 3; i++ {
+			if _, err := s.Get(context.Background(), id); !errors.Is(err, db.ErrNoRowsAffected) {
+				t.Errorf("service.Get() error = %v, want %v", err, db.ErrNoRowsAffected)
+			}
+		}
+	})
+
+	t.Run("get record while the database is down", func(t *testing.T) {
+
+		// The database layer fails only once, after which the breaker must fast-fail.
+		config.db.EXPECT().Get(gomock.Any(), id).Return(nil, errConnection).Times(1)
+
+		if _, err := s.Get(context.Background(), id); err == nil {
+			t.Errorf("service.Get() error = %v, wantErr %v", err, true)
+		}
+
+		if _, err := s.Get(context.Background(), id); err != ErrServiceUnavailable {
+			t.Errorf("service.Get() error = %v, wantErr %v", err, ErrServiceUnavailable)
+		}
+	})
+
+	t.Run("stream records while the database is down", func(t *testing.T) {
+
+		g := &guarded{
+			DB: config.db,
+			breaker: breaker.New(&breaker.Config{
+				Threshold: 1,
+				Cooldown:  time.Minute,
+			}),
+		}
+
+		// The stream fails only once, after which the breaker must fast-fail.
+		failed := make(chan error, 1)
+		failed <- errConnection
+		close(failed)
+		empty := make(chan *model.Record)
+		close(empty)
+		config.db.EXPECT().ListChan(gomock.Any(), gomock.Any()).Return(empty, failed).Times(1)
+
+		records, errc := g.ListChan(context.Background(), &db.ListOptions{})
+		for range records {
+		}
+		if err := <-errc; err != errConnection {
+			t.Errorf("guarded.ListChan() error = %v, want %v", err, errConnection)
+		}
+
+		records, errc = g.ListChan(context.Background(), &db.ListOptions{})
+		for range records {
+		}
+		if err := <-errc; err != ErrServiceUnavailable {
+			t.Errorf("guarded.ListChan() error = %v, want %v", err, ErrServiceUnavailable)
+		}
+	})
+}
+
+func Test_Service_Create_Quota(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	// Initialize the service with a quota of 2 records per user.
+	s := &service{
+		db:                config.db,
+		logger:            config.log,
+		maxRecordsPerUser: 2,
+	}
+
+	options := CreateOptions{
+		Title:  "Test Record",
+		UserID: uuid.New(),
+	}
+
+	t.Run("create records within the quota", func(t *testing.T) {
+
+		for count := int64(0); count < 2; count++ {
+
+			// Set the expectations at the database layer.
+			config.db.EXPECT().Count(gomock.Any(), &db.CountOptions{UserID: options.UserID}).Return(count, nil).Times(1)
+			config.db.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&model.Record{}, nil).Times(1)
+
+			if _, err := s.Create(context.Background(), &options); err != nil {
+				t.Errorf("service.Create() error = %v, wantErr %v", err, false)
+			}
+		}
+	})
+
+	t.Run("create record over the quota", func(t *testing.T) {
+
+		// The user already owns the maximum number of records.
+		config.db.EXPECT().Count(gomock.Any(), gomock.Any()).Return(int64(2), nil).Times(1)
+		config.db.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+
+		if _, err := s.Create(context.Background(), &options); err != ErrQuotaExceeded {
+			t.Errorf("service.Create() error = %v, wantErr %v", err, ErrQuotaExceeded)
+		}
+	})
+}
+
+func Test_Service_Tx(t *testing.T) {
+
+	// Open an in-memory database connection with SQLite.
+	conn, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open the database connection: %v", err)
+	}
+
+	// Migrate the schema.
+	if err := conn.AutoMigrate(&model.Record{}, &model.AuditLog{}); err != nil {
+		t.Fatalf("failed to migrate the schema: %v", err)
+	}
+
+	// Cleanup the environment after the test is complete.
+	t.Cleanup(func() {
+		sqlDB, err := conn.DB()
+		if err != nil {
+			t.Fatalf("failed to get the database connection: %v", err)
+		}
+		if err := sqlDB.Close(); err != nil {
+			t.Fatalf("failed to close the database connection: %v", err)
+		}
+	})
+
+	// Initialize the service.
+	s := NewService(&Config{
+		DB: db.NewSQLDB(&db.SQLDBConfig{
+			DB: conn,
+		}),
+	})
+
+	ctx := context.Background()
+
+	t.Run("commit creates and return their results", func(t *testing.T) {
+
+		userID := uuid.New()
+		var created []*model.Record
+
+		err := s.Tx(ctx, func(tx Service) error {
+			for _, title := range []string{"Record 1", "Record 2"} {
+				record, err := tx.Create(ctx, &CreateOptions{
+					Title:  title,
+					UserID: userID,
+				})
+				if err != nil {
+					return err
+				}
+				created = append(created, record)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("service.Tx() error = %v", err)
+		}
+
+		if len(created) != 2 {
+			t.Fatalf("expected 2 records, got %d", len(created))
+		}
+
+		for _, record := range created {
+			if _, err := s.Get(ctx, record.ID); err != nil {
+				t.Errorf("expected the record %s to be committed, got %v", record.ID, err)
+			}
+		}
+	})
+
+	t.Run("roll back creates on a later error", func(t *testing.T) {
+
+		failure := errors.New("failure")
+		var created *model.Record
+
+		err := s.Tx(ctx, func(tx Service) error {
+			record, err := tx.Create(ctx, &CreateOptions{
+				Title:  "Rolled Back Record",
+				UserID: uuid.New(),
+			})
+			if err != nil {
+				return err
+			}
+			created = record
+			return failure
+		})
+		if !errors.Is(err, failure) {
+			t.Fatalf("service.Tx() error = %v, want %v", err, failure)
+		}
+
+		if _, err := s.Get(ctx, created.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("expected the record to be rolled back, got %v", err)
+		}
+	})
+
+	t.Run("list in the default order", func(t *testing.T) {
+
+		var created []*model.Record
+		for i := 0; i < 2; i++ {
+			record, err := s.Create(ctx, &CreateOptions{
+				Title:  "Ordered Record",
+				UserID: uuid.New(),
+			})
+			if err != nil {
+				t.Fatalf("failed to seed the database: %v", err)
+			}
+			created = append(created, record)
+		}
+
+		var records []*model.Record
+		err := s.Tx(ctx, func(tx Service) (err error) {
+			records, err = tx.List(ctx, &ListOptions{
+				Title: "Ordered Record",
+			})
+			return err
+		})
+		if err != nil {
+			t.Fatalf("service.Tx() error = %v", err)
+		}
+
+		// The most recently created record comes first.
+		if len(records) != 2 || records[0].ID != created[1].ID || records[1].ID != created[0].ID {
+			t.Errorf("expected the records in the default order, got %v", records)
+		}
+	})
+
+	t.Run("list more records than the cap of the database layer", func(t *testing.T) {
+
+		// Initialize the service with a hard cap of 2 records per list.
+		s := NewService(&Config{
+			DB: db.NewSQLDB(&db.SQLDBConfig{
+				DB:      conn,
+				MaxRows: 2,
+			}),
+		})
+
+		for i := 0; i < 3; i++ {
+			if _, err := s.Create(ctx, &CreateOptions{
+				Title:  "Capped Record",
+				UserID: uuid.New(),
+			}); err != nil {
+				t.Fatalf("failed to seed the database: %v", err)
+			}
+		}
+
+		err := s.Tx(ctx, func(tx Service) error {
+			_, err := tx.List(ctx, &ListOptions{
+				Title: "Capped Record",
+			})
+			return err
+		})
+		if !errors.Is(err, ErrResultTooLarge) {
+			t.Errorf("service.Tx() error = %v, want %v", err, ErrResultTooLarge)
+		}
+	})
+
+	t.Run("nil function", func(t *testing.T) {
+
+		if err := s.Tx(ctx, nil); err != ErrInvalidOptions {
+			t.Errorf("service.Tx() error = %v, want %v", err, ErrInvalidOptions)
+		}
+	})
+}
+
+func Test_Service_Clone(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	// Initialize the service.
+	s := &service{
+		db:     config.db,
+		logger: config.log,
+	}
+
+	// The requester and their record.
+	owner := uuid.New()
+	original := &model.Record{
+		Base: model.Base{
+			ID: uuid.New(),
+		},
+		Title:       "Test Record",
+		Description: "Notes",
+		UserID:      owner,
+	}
+	ctx := middleware.WithJWTClaims(context.Background(), middleware.JWTClaims{
+		XUserID: owner,
+	})
+
+	t.Run("clone own record", func(t *testing.T) {
+
+		// Set the expectations at the database layer.
+		config.db.EXPECT().Get(gomock.Any(), original.ID).Return(original, nil).Times(1)
+		config.db.EXPECT().Count(gomock.Any(), &db.CountOptions{
+			Title:  "Test Record (copy)",
+			UserID: owner,
+		}).Return(int64(1), nil).Times(1)
+		config.db.EXPECT().Count(gomock.Any(), &db.CountOptions{
+			Title:  "Test Record (copy 2)",
+			UserID: owner,
+		}).Return(int64(0), nil).Times(1)
+		config.db.EXPECT().Create(gomock.Any(), &db.CreateOptions{
+			Title:       "Test Record (copy 2)",
+			Description: original.Description,
+			UserID:      owner,
+		}).Return(&model.Record{Title: "Test Record (copy 2)"}, nil).Times(1)
+
+		record, err := s.Clone(ctx, original.ID)
+		if err != nil {
+			t.Fatalf("service.Clone() error = %v, wantErr %v", err, false)
+		}
+		if record.Title != "Test Record (copy 2)" {
+			t.Errorf("service.Clone() title = %q, want %q", record.Title, "Test Record (copy 2)")
+		}
+	})
+
+	t.Run("clone record w/ all the copy titles taken", func(t *testing.T) {
+
+		// Set the expectations at the database layer.
+		config.db.EXPECT().Get(gomock.Any(), original.ID).Return(original, nil).Times(1)
+		config.db.EXPECT().Count(gomock.Any(), gomock.Any()).Return(int64(1), nil).Times(maxCopies)
+		config.db.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+
+		if _, err := s.Clone(ctx, original.ID); err != ErrTooManyCopies {
+			t.Errorf("service.Clone() error = %v, wantErr %v", err, ErrTooManyCopies)
+		}
+	})
+
+	t.Run("clone another user's record", func(t *testing.T) {
+
+		// The Row Level Security (RLS) checks hide the record from the requester.
+		config.db.EXPECT().Get(gomock.Any(), original.ID).Return(nil, gorm.ErrRecordNotFound).Times(1)
+		config.db.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+
+		ctx := middleware.WithJWTClaims(context.Background(), middleware.JWTClaims{
+			XUserID: uuid.New(),
+		})
+		if _, err := s.Clone(ctx, original.ID); err != gorm.ErrRecordNotFound {
+			t.Errorf("service.Clone() error = %v, wantErr %v", err, gorm.ErrRecordNotFound)
+		}
+	})
+
+	t.Run("clone record w/ nil ID", func(t *testing.T) {
+
+		if _, err := s.Clone(ctx, uuid.Nil); err != ErrInvalidRecordID {
+			t.Errorf("service.Clone() error = %v, wantErr %v", err, ErrInvalidRecordID)
+		}
+	})
+}
+
+func Test_Service_GetOrCreate(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	// Initialize the service with a quota of 2 records per user.
+	s := &service{
+		db:                config.db,
+		logger:            config.log,
+		maxRecordsPerUser: 2,
+	}
+
+	options := CreateOptions{
+		Title:  "Test Record",
+		UserID: uuid.New(),
+	}
+
+	t.Run("get or create w/ new record", func(t *testing.T) {
+
+		// Set the expectations at the database layer.
+		config.db.EXPECT().CreateIfNotExists(gomock.Any(), &db.CreateOptions{
+			Title:  options.Title,
+			UserID: options.UserID,
+		}).Return(&model.Record{}, true, nil).Times(1)
+		config.db.EXPECT().Count(gomock.Any(), &db.CountOptions{UserID: options.UserID}).Return(int64(1), nil).Times(1)
+
+		_, created, err := s.GetOrCreate(context.Background(), &options)
+		if err != nil {
+			t.Fatalf("service.GetOrCreate() error = %v, wantErr %v", err, false)
+		}
+		if !created {
+			t.Errorf("service.GetOrCreate() created = %v, want %v", created, true)
+		}
+	})
+
+	t.Run("get or create w/ existing record", func(t *testing.T) {
+
+		// The existing record doesn't count against the quota.
+		config.db.EXPECT().CreateIfNotExists(gomock.Any(), gomock.Any()).Return(&model.Record{}, false, nil).Times(1)
+		config.db.EXPECT().Count(gomock.Any(), gomock.Any()).Times(0)
+
+		_, created, err := s.GetOrCreate(context.Background(), &options)
+		if err != nil {
+			t.Fatalf("service.GetOrCreate() error = %v, wantErr %v", err, false)
+		}
+		if created {
+			t.Errorf("service.GetOrCreate() created = %v, want %v", created, false)
+		}
+	})
+
+	t.Run("get or create w/ new record over the quota", func(t *testing.T) {
+
+		config.db.EXPECT().CreateIfNotExists(gomock.Any(), gomock.Any()).Return(&model.Record{}, true, nil).Times(1)
+		config.db.EXPECT().Count(gomock.Any(), gomock.Any()).Return(int64(3), nil).Times(1)
+
+		if _, _, err := s.GetOrCreate(context.Background(), &options); err != ErrQuotaExceeded {
+			t.Errorf("service.GetOrCreate() error = %v, wantErr %v", err, ErrQuotaExceeded)
+		}
+	})
+
+	t.Run("get or create w/ invalid options", func(t *testing.T) {
+
+		config.db.EXPECT().CreateIfNotExists(gomock.Any(), gomock.Any()).Times(0)
+
+		if _, _, err := s.GetOrCreate(context.Background(), &CreateOptions{}); err == nil {
+			t.Errorf("service.GetOrCreate() error = %v, wantErr %v", err, true)
+		}
+	})
+}
+
+func Test_Service_Import(t *testing.T) {
+
+	// Setup the test config.
+	config := configure(t)
+
+	// Initialize the service.
+	s := &service{
+		db:     config.db,
+		logger: config.log,
+	}
+
+	createdAt := time.Date(2019, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("import record w/ timestamps", func(t *testing.T) {
+
+		options := ImportOptions{
+			Title:     "Historical Record",
+			UserID:    uuid.New(),
+			CreatedAt: createdAt,
+		}
+
+		// The timestamps must reach the database layer untouched.
+		config.db.EXPECT().Import(gomock.Any(), &db.ImportOptions{
+			Title:     options.Title,
+			UserID:    options.UserID,
+			CreatedAt: createdAt,
+		}).Return(&model.Record{}, nil).Times(1)
+
+		if _, err := s.Import(context.Background(), &options); err != nil {
+			t.Errorf("service.Import() error = %v, wantErr %v", err, false)
+		}
+	})
+
+	t.Run("import record w/o created at", func(t *testing.T) {
+
+		// Make sure the database layer is not expecting a call.
+		config.db.EXPECT().Import(gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := s.Import(context.Background(), &ImportOptions{
+			Title:  "Historical Record",
+			UserID: uuid.New(),
+		})
+		if err != ErrInvalidTimestamps {
+			t.Errorf("service.Import() error = %v, wantErr %v", err, ErrInvalidTimestamps)
+		}
+	})
+}
+
+func Test_Service_AuditLogs(t *testing.T) {
+
+	// Open an in-memory database connection with SQLite.
+	conn, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open the database connection: %v", err)
+	}
+
+	// Migrate the schema.
+	if err := conn.AutoMigrate(&model.Record{}, &model.AuditLog{}); err != nil {
+		t.Fatalf("failed to migrate the schema: %v", err)
+	}
+
+	// Cleanup the environment after the test is complete.
+	t.Cleanup(func() {
+		sqlDB, err := conn.DB()
+		if err != nil {
+			t.Fatalf("failed to get the database connection: %v", err)
+		}
+		if err := sqlDB.Close(); err != nil {
+			t.Fatalf("failed to close the database connection: %v", err)
+		}
+	})
+
+	// Initialize the service.
+	s := NewService(&Config{
+		DB: db.NewSQLDB(&db.SQLDBConfig{
+			DB: conn,
+		}),
+	})
+
+	claims := middleware.JWTClaims{
+		XUserID: uuid.New(),
+	}
+	ctx := middleware.WithJWTClaims(context.Background(), claims)
+
+	// actions returns the actions recorded for the record, the most recent first.
+	actions := func(t *testing.T, ID uuid.UUID) []string {
+		logs, err := s.ListAuditLogs(ctx, &ListAuditLogsOptions{
+			EntityID: ID,
+		})
+		if err != nil {
+			t.Fatalf("service.ListAuditLogs() error = %v", err)
+		}
+		var actions []string
+		for _, log := range logs {
+			if log.ActorID != claims.XUserID {
+				t.Errorf("expected the actor %s, got %s", claims.XUserID, log.ActorID)
+			}
+			actions = append(actions, log.Action)
+		}
+		return actions
+	}
+
+	t.Run("write an audit log per mutation", func(t *testing.T) {
+
+		record, err := s.Create(ctx, &CreateOptions{
+			Title:  "Audited Record",
+			UserID: claims.XUserID,
+		})
+		if err != nil {
+			t.Fatalf("service.Create() error = %v", err)
+		}
+		if got := actions(t, record.ID); len(got) != 1 || got[0] != model.AuditActionCreate {
+			t.Fatalf("expected a create audit log, got %v", got)
+		}
+
+		if _, err := s.Update(ctx, record.ID, &UpdateOptions{
+			Title: "Updated Record",
+		}); err != nil {
+			t.Fatalf("service.Update() error = %v", err)
+		}
+
+		logs, err := s.ListAuditLogs(ctx, &ListAuditLogsOptions{
+			EntityID: record.ID,
+			Action:   model.AuditActionUpdate,
+		})
+		if err != nil {
+			t.Fatalf("service.ListAuditLogs() error = %v", err)
+		}
+		if len(logs) != 1 || string(logs[0].Diff) != `{"title":"Updated Record"}` {
+			t.Fatalf("expected an update audit log with the diff, got %+v", logs)
+		}
+
+		if err := s.Delete(ctx, record.ID); err != nil {
+			t.Fatalf("service.Delete() error = %v", err)
+		}
+		if got := actions(t, record.ID); len(got) != 3 {
+			t.Fatalf("expected 3 audit logs, got %v", got)
+		}
+	})
+
+	t.Run("roll back the audit log with the transaction", func(t *testing.T) {
+
+		failure := errors.New("failure")
+		var created *model.Record
+
+		err := s.Tx(ctx, func(tx Service) error {
+			record, err := tx.Create(ctx, &CreateOptions{
+				Title:  "Rolled Back Record",
+				UserID: claims.XUserID,
+			})
+			if err != nil {
+				return err
+			}
+			created = record
+			return failure
+		})
+		if !errors.Is(err, failure) {
+			t.Fatalf("service.Tx() error = %v, want %v", err, failure)
+		}
+
+		if got := actions(t, created.ID); len(got) != 0 {
+			t.Fatalf("expected the audit log to be rolled back, got %v", got)
+		}
+	})
+
+	t.Run("skip the audit log of a failed mutation", func(t *testing.T) {
+
+		if err := s.Delete(ctx, uuid.New()); err == nil {
+			t.Fatalf("expected an error, got nil")
+		}
+
+		logs, err := s.ListAuditLogs(ctx, &ListAuditLogsOptions{
+			Action: model.AuditActionDelete,
+		})
+		if err != nil {
+			t.Fatalf("service.ListAuditLogs() error = %v", err)
+		}
+		if len(logs) != 1 {
+			t.Fatalf("expected only the delete audit log of the existing record, got %d", len(logs))
+		}
+	})
+}
+
+func Test_Service_LogCorrelation(t *testing.T) {
+
+	// Open an in-memory database connection with SQLite.
+	conn, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open the database connection: %v", err)
+	}
+
+	// Migrate the schema.
+	if err := conn.AutoMigrate(&model.Record{}, &model.AuditLog{}); err != nil {
+		t.Fatalf("failed to migrate the schema: %v", err)
+	}
+
+	// Cleanup the environment after the test is complete.
+	t.Cleanup(func() {
+		sqlDB, err := conn.DB()
+		if err != nil {
+			t.Fatalf("failed to get the database connection: %v", err)
+		}
+		if err := sqlDB.Close(); err != nil {
+			t.Fatalf("failed to close the database connection: %v", err)
+		}
+	})
+
+	// Capture the logs of both layers.
+	var buffer bytes.Buffer
+	log := slog.New(logger.NewContextHandler(slog.NewJSONHandler(&buffer, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	})))
+
+	// Initialize the service.
+	// The RLS audit makes the database layer log the denied access.
+	s := NewService(&Config{
+		DB: db.NewSQLDB(&db.SQLDBConfig{
+			DB:       conn,
+			Logger:   log,
+			AuditRLS: true,
+		}),
+		Logger: log,
+	})
+
+	// Create a record owned by another user.
+	record, err := s.Create(context.Background(), &CreateOptions{
+		Title:  "Someone Else's Record",
+		UserID: uuid.New(),
+	})
+	if err != nil {
+		t.Fatalf("service.Create() error = %v", err)
+	}
+	buffer.Reset()
+
+	// Serve a request which reads the record.
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r = r.WithContext(middleware.WithJWTClaims(r.Context(), middleware.JWTClaims{
+		XUserID: uuid.New(),
+	}))
+	w := httptest.NewRecorder()
+	middleware.RequestID(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Get(r.Context(), record.ID)
+	})).ServeHTTP(w, r)
+
+	id := w.Header().Get(string(middleware.XRequestID))
+
+	// Every log line of both layers carries the request id.
+	layers := make(map[string]bool)
+	for _, line := range bytes.Split(bytes.TrimSpace(buffer.Bytes()), []byte("\n")) {
+		var entry map[string]any
+		if err := json.Unmarshal(line, &entry); err != nil {
+			t.Fatalf("failed to decode the log line %q: %v", line, err)
+		}
+		if entry["request_id"] != id {
+			t.Errorf("expected the request id %q, got %v", id, entry)
+		}
+		layer, _ := entry["layer"].(string)
+		layers[layer] = true
+	}
+
+	if !layers["service"] || !layers["database"] {
+		t.Fatalf("expected the logs of both layers, got %v", layers)
+	}
+}
+
+func Test_Service_TitlePolicy(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		policy TitlePolicy
+		title  string
+
+		// want is the title stored by the database layer.
+		// It is empty if the title is expected to be rejected.
+		want string
+	}{
+		{
+			name:   "allow html",
+			policy: TitleAllowHTML,
+			title:  "<b>Groceries</b>",
+			want:   "<b>Groceries</b>",
+		},
+		{
+			name:   "sanitize script title",
+			policy: TitleSanitizeHTML,
+			title:  "<script>alert('xss')</script>Groceries",
+			want:   "Groceries",
+		},
+		{
+			name:   "sanitize tags but keep the text",
+			policy: TitleSanitizeHTML,
+			title:  "<img src=x onerror=alert(1)>Tom & <i>Jerry</i>",
+			want:   "Tom & Jerry",
+		},
+		{
+			name:   "sanitize title made only of markup",
+			policy: TitleSanitizeHTML,
+			title:  "<script>alert('xss')</script>",
+		},
+		{
+			name:   "reject script title",
+			policy: TitleRejectHTML,
+			title:  "<script>alert('xss')</script>Groceries",
+		},
+		{
+			name:   "reject allows text with angle brackets",
+			policy: TitleRejectHTML,
+			title:  "1 < 2 > 0",
+			want:   "1 < 2 > 0",
+		},
+		{
+			name:   "reject control characters regardless of the policy",
+			policy: TitleAllowHTML,
+			title:  "Groceries\x00",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Setup the test config.
+			config := configure(t)
+
+			// Initialize the service.
+			s := NewService(&Config{
+				DB:          config.db,
+				Logger:      config.log,
+				TitlePolicy: tt.policy,
+			})
+
+			userID := uuid.New()
+
+			if tt.want == "" {
+
+				// Make sure the database layer is not expecting a call.
+				config.db.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+
+				if _, err := s.Create(context.Background(), &CreateOptions{
+					Title:  tt.title,
+					UserID: userID,
+				}); err != ErrInvalidTitle {
+					t.Errorf("service.Create() error = %v, want %v", err, ErrInvalidTitle)
+				}
+				return
+			}
+
+			config.db.EXPECT().Create(gomock.Any(), &db.CreateOptions{
+				Title:  tt.want,
+				UserID: userID,
+			}).Return(&model.Record{Title: tt.want, UserID: userID}, nil).Times(1)
+
+			if _, err := s.Create(context.Background(), &CreateOptions{
+				Title:  tt.title,
+				UserID: userID,
+			}); err != nil {
+				t.Errorf("service.Create() error = %v", err)
+			}
+		})
+	}
+
+	t.Run("sanitize title on update", func(t *testing.T) {
+
+		// Setup the test config.
+		config := configure(t)
+
+		// Initialize the service.
+		s := NewService(&Config{
+			DB:          config.db,
+			Logger:      config.log,
+			TitlePolicy: TitleSanitizeHTML,
+		})
+
+		id := uuid.New()
+		config.db.EXPECT().UpdateResult(gomock.Any(), id, &db.UpdateOptions{
+			Title: "Groceries",
+		}).Return(&model.Result{Record: &model.Record{Title: "Groceries"}, RowsAffected: 1}, nil).Times(1)
+
+		if _, err := s.Update(context.Background(), id, &UpdateOptions{
+			Title: "<script>alert('xss')</script>Groceries",
+		}); err != nil {
+			t.Errorf("service.Update() error = %v", err)
+		}
+	})
+}
+
+func Test_Service_Reassign(t *testing.T) {
+
+	// Open an in-memory database connection with SQLite.
+	conn, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open the database connection: %v", err)
+	}
+
+	// Migrate the schema.
+	if err := conn.AutoMigrate(&model.Record{}, &model.AuditLog{}); err != nil {
+		t.Fatalf("failed to migrate the schema: %v", err)
+	}
+
+	// Cleanup the environment after the test is complete.
+	t.Cleanup(func() {
+		sqlDB, err := conn.DB()
+		if err != nil {
+			t.Fatalf("failed to get the database connection: %v", err)
+		}
+		if err := sqlDB.Close(); err != nil {
+			t.Fatalf("failed to close the database connection: %v", err)
+		}
+	})
+
+	// Initialize the service.
+	s := NewService(&Config{
+		DB: db.NewSQLDB(&db.SQLDBConfig{
+			DB: conn,
+		}),
+	})
+
+	owner := uuid.New()
+	newOwner := uuid.New()
+
+	tests := []struct {
+		name string
+
+		// ctx is the context of the caller.
+		ctx context.Context
+
+		// wantErr is the error we expect. If nil, the record is expected to be reassigned.
+		wantErr error
+	}{
+		{
+			name: "reassign as an administrator",
+			ctx: middleware.WithJWTClaims(context.Background(), middleware.JWTClaims{
+				XUserID: uuid.New(),
+				Scopes:  middleware.Scopes{AdminScope},
+			}),
+		},
+		{
+			name: "reassign w/ a service-role context",
+			ctx:  WithServiceRole(context.Background()),
+		},
+		{
+			name:    "reassign w/o claims",
+			ctx:     context.Background(),
+			wantErr: ErrPermissionDenied,
+		},
+		{
+			name: "reassign as the owner",
+			ctx: middleware.WithJWTClaims(context.Background(), middleware.JWTClaims{
+				XUserID: owner,
+			}),
+			wantErr: ErrPermissionDenied,
+		},
+		{
+			name: "reassign as another user",
+			ctx: middleware.WithJWTClaims(context.Background(), middleware.JWTClaims{
+				XUserID: uuid.New(),
+				Scopes:  middleware.Scopes{"records:read"},
+			}),
+			wantErr: ErrPermissionDenied,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			record, err := s.Create(context.Background(), &CreateOptions{
+				Title:  tt.name,
+				UserID: owner,
+			})
+			if err != nil {
+				t.Fatalf("service.Create() error = %v", err)
+			}
+
+			reassigned, err := s.Reassign(tt.ctx, record.ID, newOwner)
+			if err != tt.wantErr {
+				t.Fatalf("service.Reassign() error = %v, want %v", err, tt.wantErr)
+			}
+
+			// Read the record back without the RLS checks.
+			stored, err := s.Get(context.Background(), record.ID)
+			if err != nil {
+				t.Fatalf("service.Get() error = %v", err)
+			}
+
+			if tt.wantErr != nil {
+				if stored.UserID != owner {
+					t.Fatalf("expected the record to stay with %s, got %s", owner, stored.UserID)
+				}
+				return
+			}
+
+			if reassigned.UserID != newOwner || stored.UserID != newOwner {
+				t.Fatalf("expected the record to be reassigned to %s, got %s", newOwner, stored.UserID)
+			}
+		})
+	}
+
+	t.Run("reassign a missing record", func(t *testing.T) {
+
+		if _, err := s.Reassign(WithServiceRole(context.Background()), uuid.New(), newOwner); !errors.Is(err, db.ErrNoRowsAffected) {
+			t.Fatalf("service.Reassign() error = %v, want %v", err, db.ErrNoRowsAffected)
+		}
+	})
+}